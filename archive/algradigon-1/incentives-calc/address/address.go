@@ -0,0 +1,76 @@
+package address
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Amino prefix of an ed25519 public key (tendermint/PubKeyEd25519) including the length byte
+var ed25519AminoPrefix = []byte{0x16, 0x24, 0xde, 0x64, 0x20}
+
+// ConsPubKeyToHexAddress - Converts a bech32 consensus pubkey (valconspub) into
+// the hex address used by tendermint in block signatures
+func ConsPubKeyToHexAddress(consPubKey string) (string, error) {
+	_, bz, err := DecodeAndConvert(consPubKey)
+	if err != nil {
+		return "", err
+	}
+
+	if len(bz) != len(ed25519AminoPrefix)+32 || !bytes.HasPrefix(bz, ed25519AminoPrefix) {
+		return "", fmt.Errorf("unsupported consensus pubkey type: %s", consPubKey)
+	}
+
+	hash := sha256.Sum256(bz[len(ed25519AminoPrefix):])
+
+	return strings.ToUpper(hex.EncodeToString(hash[:20])), nil
+}
+
+// Ed25519PubKeyToHexAddress - Converts a raw ed25519 public key, as listed in the
+// genesis validators, into its tendermint hex address
+func Ed25519PubKeyToHexAddress(pubKey []byte) (string, error) {
+	if len(pubKey) != 32 {
+		return "", fmt.Errorf("invalid ed25519 pubkey length %d", len(pubKey))
+	}
+
+	hash := sha256.Sum256(pubKey)
+
+	return strings.ToUpper(hex.EncodeToString(hash[:20])), nil
+}
+
+// Bech32 prefixes used by regen ledger
+const (
+	AccountPrefix    = "xrn:"
+	ValOperPrefix    = "xrn:valoper"
+	ValConsPubPrefix = "xrn:valconspub"
+)
+
+// ValidateBech32 - Checks that the address is valid bech32 with the given prefix
+func ValidateBech32(addr string, prefix string) error {
+	hrp, _, err := DecodeAndConvert(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %v", addr, err)
+	}
+
+	if hrp != prefix {
+		return fmt.Errorf("address %q has prefix %q, expected %q", addr, hrp, prefix)
+	}
+
+	return nil
+}
+
+// ValOperToAccount - Converts a validator operator address to the account address of the operator
+func ValOperToAccount(valOperAddr string) (string, error) {
+	hrp, bz, err := DecodeAndConvert(valOperAddr)
+	if err != nil {
+		return "", err
+	}
+
+	if hrp != ValOperPrefix {
+		return "", fmt.Errorf("address %q is not a validator operator address", valOperAddr)
+	}
+
+	return ConvertAndEncode(AccountPrefix, bz)
+}
@@ -0,0 +1,118 @@
+package address
+
+import (
+	"testing"
+)
+
+// Vectors computed with an independent bech32 implementation, the operator and account addresses hold
+// the bytes 1 to 20 and the consensus pubkey the ed25519 key of bytes 0 to 31
+const (
+	testValOper  = "xrn:valoper1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc500g569"
+	testAccount  = "xrn:1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5eye2ar"
+	testConsPub  = "xrn:valconspub1zcjduepqqqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0ssl033y"
+	testHexAddr  = "630DCD2966C4336691125448BBB25B4FF412A49C"
+	testSecpCons = "xrn:valconspub1addwnpepqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq49ajyq"
+)
+
+func TestValOperToAccount(t *testing.T) {
+	got, err := ValOperToAccount(testValOper)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != testAccount {
+		t.Errorf("ValOperToAccount = %s, want %s", got, testAccount)
+	}
+
+	if _, err := ValOperToAccount(testAccount); err == nil {
+		t.Error("ValOperToAccount accepted an account address")
+	}
+}
+
+func TestValidateBech32(t *testing.T) {
+	tests := []struct {
+		addr    string
+		prefix  string
+		wantErr bool
+	}{
+		{addr: testAccount, prefix: AccountPrefix},
+		{addr: testValOper, prefix: ValOperPrefix},
+		{addr: testConsPub, prefix: ValConsPubPrefix},
+		{addr: testValOper, prefix: AccountPrefix, wantErr: true},
+		{addr: "xrn:1contract0erc20", prefix: AccountPrefix, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateBech32(tt.addr, tt.prefix); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateBech32(%q, %q) error = %v, want error %v", tt.addr, tt.prefix, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConsPubKeyToHexAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		pubKey  string
+		want    string
+		wantErr bool
+	}{
+		{name: "ed25519", pubKey: testConsPub, want: testHexAddr},
+		{name: "secp256k1", pubKey: testSecpCons, wantErr: true},
+		{name: "account address", pubKey: testAccount, wantErr: true},
+		{name: "bad checksum", pubKey: testConsPub[:len(testConsPub)-1] + "q", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConsPubKeyToHexAddress(tt.pubKey)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConsPubKeyToHexAddress error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ConsPubKeyToHexAddress = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEd25519PubKeyToHexAddress(t *testing.T) {
+	pubKey := make([]byte, 32)
+	for i := range pubKey {
+		pubKey[i] = byte(i)
+	}
+
+	got, err := Ed25519PubKeyToHexAddress(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != testHexAddr {
+		t.Errorf("Ed25519PubKeyToHexAddress = %s, want %s", got, testHexAddr)
+	}
+
+	if _, err := Ed25519PubKeyToHexAddress(pubKey[:31]); err == nil {
+		t.Error("Ed25519PubKeyToHexAddress accepted a 31 byte key")
+	}
+}
+
+func TestConvertAndEncodeRoundTrip(t *testing.T) {
+	hrp, bz, err := DecodeAndConvert(testAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hrp != "xrn:" || len(bz) != 20 || bz[0] != 1 || bz[19] != 20 {
+		t.Fatalf("DecodeAndConvert = %q %v", hrp, bz)
+	}
+
+	got, err := ConvertAndEncode("xrn:valoper", bz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != testValOper {
+		t.Errorf("ConvertAndEncode = %s, want %s", got, testValOper)
+	}
+}
@@ -0,0 +1,32 @@
+package address
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// ConvertAndEncode - Encodes the bytes of an address as bech32 with the given prefix
+func ConvertAndEncode(hrp string, data []byte) (string, error) {
+	converted, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("encoding bech32 failed: %v", err)
+	}
+
+	return bech32.Encode(hrp, converted)
+}
+
+// DecodeAndConvert - Decodes a bech32 address into its prefix and bytes
+func DecodeAndConvert(bech string) (string, []byte, error) {
+	hrp, data, err := bech32.Decode(bech)
+	if err != nil {
+		return "", nil, fmt.Errorf("decoding bech32 failed: %v", err)
+	}
+
+	converted, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("decoding bech32 failed: %v", err)
+	}
+
+	return hrp, converted, nil
+}
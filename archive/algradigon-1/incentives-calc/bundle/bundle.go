@@ -0,0 +1,321 @@
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+// Format of the bundle files written by this version
+const Format = 1
+
+// CodeVersion is set at build time with -ldflags "-X <module>/bundle.CodeVersion=<version>",
+// git describe of the working directory is used when it is not set
+var CodeVersion string
+
+// blocks hashed per query
+const hashBatchSize = 10000
+
+// Bundle holds everything needed to reproduce a calculation and its outputs
+type Bundle struct {
+	Format      int          `json:"format"`
+	CodeVersion string       `json:"codeVersion"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	StartBlock  int64        `json:"startBlock"`
+	EndBlock    int64        `json:"endBlock"`
+	Rules       src.Rules    `json:"rules"`
+	DataHash    string       `json:"dataHash"`
+	Results     *src.Results `json:"results"`
+	OutputHash  string       `json:"outputHash"`
+	Signature   *Signature   `json:"signature,omitempty"`
+}
+
+// Signature is an ed25519 signature of the bundle without its signature
+type Signature struct {
+	PublicKey string `json:"publicKey"`
+	Value     string `json:"value"`
+}
+
+// Create - Runs the calculation over the block range with the configured rules and bundles its inputs and outputs
+func Create(store db.DB, cfg *config.Config, startBlock, endBlock int64) (*Bundle, error) {
+	rules, err := src.ResolveRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dataHash, err := DataHash(store, startBlock, endBlock, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	results := src.New(store, cfg).ScoreValidators(startBlock, endBlock)
+	sortResults(results)
+
+	outputHash, err := OutputHash(results)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bundle{
+		Format:      Format,
+		CodeVersion: codeVersion(),
+		CreatedAt:   time.Now().UTC(),
+		StartBlock:  startBlock,
+		EndBlock:    endBlock,
+		Rules:       rules,
+		DataHash:    dataHash,
+		Results:     results,
+		OutputHash:  outputHash,
+	}, nil
+}
+
+// Load - Reads a bundle file
+func Load(path string) (*Bundle, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Bundle
+
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if b.Format != Format {
+		return nil, fmt.Errorf("%s: unsupported bundle format %d", path, b.Format)
+	}
+
+	return &b, nil
+}
+
+// Save - Writes the bundle as indented JSON
+func (b *Bundle) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Sign - Signs the bundle with an ed25519 private key, replacing any previous signature
+func (b *Bundle) Sign(key ed25519.PrivateKey) error {
+	b.Signature = nil
+
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	b.Signature = &Signature{
+		PublicKey: hex.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Value:     hex.EncodeToString(ed25519.Sign(key, payload)),
+	}
+
+	return nil
+}
+
+// VerifySignature - Checks the signature of the bundle, and that it was made by the
+// expected public key when one is given
+func (b *Bundle) VerifySignature(expectedKey string) error {
+	if b.Signature == nil {
+		return errors.New("bundle is not signed")
+	}
+
+	if expectedKey != "" && !strings.EqualFold(expectedKey, b.Signature.PublicKey) {
+		return fmt.Errorf("signed by %s, expected %s", b.Signature.PublicKey, expectedKey)
+	}
+
+	publicKey, err := hex.DecodeString(b.Signature.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key %s", b.Signature.PublicKey)
+	}
+
+	signature, err := hex.DecodeString(b.Signature.Value)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+
+	unsigned := *b
+	unsigned.Signature = nil
+
+	payload, err := json.Marshal(&unsigned)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(publicKey), payload, signature) {
+		return errors.New("signature does not match the bundle")
+	}
+
+	return nil
+}
+
+// DataHash - Hashes the block data the calculation reads: the signing validators of every block in
+// the range and of block 2 (genesis points), the operator address and moniker of the validators,
+// the headers and powers of the range when the rules give proposer points and the evidence of the
+// range when the rules disqualify double-signers
+func DataHash(store db.DB, startBlock, endBlock int64, rules src.Rules) (string, error) {
+	hash := sha256.New()
+
+	batches := func(from, to int64, write func(from, to int64) error) error {
+		for ; from <= to; from += hashBatchSize {
+			batchEnd := from + hashBatchSize - 1
+			if batchEnd > to {
+				batchEnd = to
+			}
+
+			if err := write(from, batchEnd); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	writeBlocks := func(from, to int64) error {
+		blocks, err := store.QueryBlocks(from, to)
+		if err != nil {
+			return err
+		}
+
+		for _, block := range blocks {
+			validators := append([]string{}, block.Validators...)
+			sort.Strings(validators)
+
+			fmt.Fprintf(hash, "%d:%s\n", block.Height, strings.Join(validators, ","))
+		}
+
+		return nil
+	}
+
+	if startBlock > 2 || endBlock < 2 {
+		if err := writeBlocks(2, 2); err != nil {
+			return "", err
+		}
+	}
+
+	if err := batches(startBlock, endBlock, writeBlocks); err != nil {
+		return "", err
+	}
+
+	validators, err := store.QueryValidators()
+	if err != nil {
+		return "", err
+	}
+
+	sort.Slice(validators, func(i, j int) bool { return validators[i].Address < validators[j].Address })
+
+	fmt.Fprintln(hash, "validators")
+
+	for _, v := range validators {
+		fmt.Fprintf(hash, "%s:%s:%s\n", v.Address, v.OperatorAddress, v.Description.Moniker)
+	}
+
+	if rules.ProposerPoints > 0 {
+		fmt.Fprintln(hash, "headers")
+
+		err := batches(startBlock, endBlock, func(from, to int64) error {
+			headers, err := store.QueryBlockHeaders(from, to)
+			if err != nil {
+				return err
+			}
+
+			for _, header := range headers {
+				fmt.Fprintf(hash, "%d:%s:%d\n", header.Height, header.Proposer, header.Round)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+
+		//The set of the last height is stored with the next height
+		fmt.Fprintln(hash, "powers")
+
+		err = batches(startBlock, endBlock+1, func(from, to int64) error {
+			powers, err := store.QueryBlockPowers(from, to)
+			if err != nil {
+				return err
+			}
+
+			for _, power := range powers {
+				set := make([]string, 0, len(power.Validators))
+
+				for _, v := range power.Validators {
+					set = append(set, fmt.Sprintf("%s=%d", v.Address, v.VotingPower))
+				}
+
+				sort.Strings(set)
+
+				fmt.Fprintf(hash, "%d:%d:%s\n", power.Height, power.TotalPower, strings.Join(set, ","))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(rules.DoubleSignDisqualify) > 0 {
+		evidence, err := store.QueryEvidence(startBlock, endBlock)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintln(hash, "evidence")
+
+		for _, e := range evidence {
+			fmt.Fprintf(hash, "%s:%d:%s:%s:%d:%d\n", e.ID, e.Height, e.Type, e.Address, e.VoteHeight, e.VoteRound)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// OutputHash - Hashes the JSON encoding of the results
+func OutputHash(results *src.Results) (string, error) {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// sortResults - Orders the validators by address, the aggregate returns them in any order
+func sortResults(results *src.Results) {
+	for _, list := range [][]src.ValidatorInfo{results.Validators, results.Unresolved} {
+		sort.Slice(list, func(i, j int) bool { return list[i].ValAddress < list[j].ValAddress })
+	}
+}
+
+func codeVersion() string {
+	if CodeVersion != "" {
+		return CodeVersion
+	}
+
+	out, err := exec.Command("git", "describe", "--always", "--dirty").Output()
+	if err != nil {
+		return "unknown"
+	}
+
+	return strings.TrimSpace(string(out))
+}
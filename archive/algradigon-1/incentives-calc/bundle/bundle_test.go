@@ -0,0 +1,294 @@
+package bundle
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/src"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// fakeStore serves blocks, headers, powers, evidence and validators from memory, the aggregate counts the signatures of all
+// its blocks. Queries the bundle does not make are left to the nil DB and panic
+type fakeStore struct {
+	db.DB
+	blocks     []db.Blocks
+	headers    []db.BlockHeader
+	powers     []db.BlockPower
+	validators []db.Validator
+	evidence   []db.Evidence
+}
+
+func (s *fakeStore) QueryBlocks(fromHeight, toHeight int64) ([]db.Blocks, error) {
+	var result []db.Blocks
+
+	for _, block := range s.blocks {
+		if block.Height >= fromHeight && block.Height <= toHeight {
+			result = append(result, block)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *fakeStore) QueryBlockHeaders(fromHeight, toHeight int64) ([]db.BlockHeader, error) {
+	var result []db.BlockHeader
+
+	for _, header := range s.headers {
+		if header.Height >= fromHeight && header.Height <= toHeight {
+			result = append(result, header)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *fakeStore) QueryBlockPowers(fromHeight, toHeight int64) ([]db.BlockPower, error) {
+	var result []db.BlockPower
+
+	for _, power := range s.powers {
+		if power.Height >= fromHeight && power.Height <= toHeight {
+			result = append(result, power)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *fakeStore) QueryEvidence(fromHeight, toHeight int64) ([]db.Evidence, error) {
+	var result []db.Evidence
+
+	for _, e := range s.evidence {
+		if e.Height >= fromHeight && e.Height <= toHeight {
+			result = append(result, e)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *fakeStore) QueryValidators() ([]db.Validator, error) {
+	return s.validators, nil
+}
+
+func (s *fakeStore) QueryValAggregateData(aggQuery []bson.M) ([]db.ValAggregateResult, error) {
+	index := make(map[string]int)
+
+	var results []db.ValAggregateResult
+
+	for _, block := range s.blocks {
+		for _, address := range block.Validators {
+			i, ok := index[address]
+			if !ok {
+				i = len(results)
+				index[address] = i
+				results = append(results, db.ValAggregateResult{Id: address})
+
+				for _, v := range s.validators {
+					if v.Address == address {
+						results[i].Validator_details = []db.Validator_details{{
+							Address: v.Address, Operator_address: v.OperatorAddress, Description: v.Description,
+						}}
+					}
+				}
+			}
+
+			results[i].Uptime_count++
+		}
+	}
+
+	return results, nil
+}
+
+func (s *fakeStore) QueryTxsByMsgType(msgType string) ([]db.Transaction, error) {
+	return nil, nil
+}
+
+// testStore - Blocks 1 to 10 signed by A and B, B misses the even heights and A proposes them all
+func testStore() *fakeStore {
+	s := &fakeStore{validators: []db.Validator{
+		{Address: "A", OperatorAddress: "xrn:valoper1a", Description: db.Description{Moniker: "alpha"}},
+		{Address: "B", OperatorAddress: "xrn:valoper1b", Description: db.Description{Moniker: "bravo"}},
+	}}
+
+	for height := int64(1); height <= 10; height++ {
+		validators := []string{"A"}
+		if height%2 == 1 {
+			validators = append(validators, "B")
+		}
+
+		s.blocks = append(s.blocks, db.Blocks{Height: height, Validators: validators})
+		s.headers = append(s.headers, db.BlockHeader{Height: height, Proposer: "A"})
+		s.powers = append(s.powers, db.BlockPower{Height: height, TotalPower: 20, Validators: []db.ValidatorPower{
+			{Address: "A", VotingPower: 10}, {Address: "B", VotingPower: 10},
+		}})
+	}
+
+	return s
+}
+
+func testBundle(t *testing.T, store *fakeStore) *Bundle {
+	CodeVersion = "test"
+
+	cfg := src.Rules{NodeRewards: 100, MaxUptimeRewards: 500}.Apply(&config.Config{})
+
+	b, err := Create(store, cfg, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestVerify(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	otherKey := ed25519.NewKeyFromSeed(append(make([]byte, ed25519.SeedSize-1), 1))
+
+	tests := []struct {
+		name       string
+		change     func(b *Bundle, store *fakeStore)
+		publicKey  string
+		signed     bool
+		errors     int
+		mismatches int
+	}{
+		{name: "unchanged"},
+		{
+			name:      "signed",
+			change:    func(b *Bundle, store *fakeStore) { b.Sign(key) },
+			publicKey: hex.EncodeToString(key.Public().(ed25519.PublicKey)),
+			signed:    true,
+		},
+		{
+			name:      "signed by another key",
+			change:    func(b *Bundle, store *fakeStore) { b.Sign(otherKey) },
+			publicKey: hex.EncodeToString(key.Public().(ed25519.PublicKey)),
+			errors:    1,
+		},
+		{
+			name:      "unsigned with an expected key",
+			publicKey: hex.EncodeToString(key.Public().(ed25519.PublicKey)),
+			errors:    1,
+		},
+		{
+			name: "signed then edited",
+			change: func(b *Bundle, store *fakeStore) {
+				b.Sign(key)
+				b.CodeVersion = "edited"
+			},
+			errors: 1,
+		},
+		{
+			name:       "results edited",
+			change:     func(b *Bundle, store *fakeStore) { b.Results.Validators[0].Info.TotalPoints++ },
+			errors:     1,
+			mismatches: 1,
+		},
+		{
+			name:       "block data changed",
+			change:     func(b *Bundle, store *fakeStore) { store.blocks[1].Validators = []string{"A", "B"} },
+			errors:     1,
+			mismatches: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := testStore()
+			b := testBundle(t, store)
+
+			if tt.change != nil {
+				tt.change(b, store)
+			}
+
+			report := Verify(b, store, &config.Config{}, tt.publicKey)
+
+			if report.Signed != tt.signed || len(report.Errors) != tt.errors || len(report.Mismatches) != tt.mismatches {
+				t.Errorf("report = %+v, want signed %v with %d errors and %d mismatches",
+					report, tt.signed, tt.errors, tt.mismatches)
+			}
+
+			if report.Passed() != (tt.errors == 0 && tt.mismatches == 0) {
+				t.Errorf("report passed = %v", report.Passed())
+			}
+		})
+	}
+}
+
+func TestDataHash(t *testing.T) {
+	hash := func(store *fakeStore, start, end int64, rules src.Rules) string {
+		h, err := DataHash(store, start, end, rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return h
+	}
+
+	rules := src.Rules{}
+	proposer := src.Rules{ProposerPoints: 1}
+	doubleSign := src.Rules{DoubleSignDisqualify: []string{"duplicate/vote"}}
+
+	store := testStore()
+	base := hash(store, 5, 10, rules)
+
+	//The signatures are hashed whatever their order in the block
+	store.blocks[6].Validators = []string{"B", "A"}
+	if hash(store, 5, 10, rules) != base {
+		t.Error("data hash depends on the order of the block signatures")
+	}
+
+	//Block 2 is read for the genesis points even outside of the range
+	store.blocks[1].Validators = []string{"A", "B"}
+	if hash(store, 5, 10, rules) == base {
+		t.Error("data hash does not cover block 2")
+	}
+
+	store = testStore()
+	store.validators[0].Description.Moniker = "renamed"
+
+	if hash(store, 5, 10, rules) == base {
+		t.Error("data hash does not cover the validator monikers")
+	}
+
+	store = testStore()
+	store.blocks[0].Validators = nil
+
+	if hash(store, 5, 10, rules) != base {
+		t.Error("data hash covers blocks outside of the range")
+	}
+
+	//Headers and powers are only hashed when the rules give proposer points
+	store = testStore()
+	store.headers[6].Proposer = "B"
+
+	if hash(store, 5, 10, rules) != base {
+		t.Error("data hash covers the headers without proposer points")
+	}
+
+	if hash(store, 5, 10, proposer) == hash(testStore(), 5, 10, proposer) {
+		t.Error("data hash does not cover the headers")
+	}
+
+	store = testStore()
+	store.powers[6].Validators[0].VotingPower = 15
+
+	if hash(store, 5, 10, proposer) == hash(testStore(), 5, 10, proposer) {
+		t.Error("data hash does not cover the powers")
+	}
+
+	//Evidence is only hashed when the rules disqualify double-signers
+	store = testStore()
+	store.evidence = []db.Evidence{{ID: "E1", Height: 7, Type: "duplicate/vote", Address: "B"}}
+
+	if hash(store, 5, 10, rules) != base {
+		t.Error("data hash covers the evidence without disqualification")
+	}
+
+	if hash(store, 5, 10, doubleSign) == hash(testStore(), 5, 10, doubleSign) {
+		t.Error("data hash does not cover the evidence")
+	}
+}
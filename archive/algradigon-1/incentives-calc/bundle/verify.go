@@ -0,0 +1,123 @@
+package bundle
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+// Report is the outcome of verifying a bundle, warnings don't fail the verification
+type Report struct {
+	Signed     bool     `json:"signed"`
+	Errors     []string `json:"errors"`
+	Warnings   []string `json:"warnings"`
+	Mismatches []string `json:"mismatches"`
+}
+
+func (r Report) Passed() bool {
+	return len(r.Errors) == 0 && len(r.Mismatches) == 0
+}
+
+// Verify - Checks the signature and output hash of the bundle, then re-runs the calculation with
+// the rules of the bundle applied to the config and compares the data hash and every validator output
+func Verify(b *Bundle, store db.DB, cfg *config.Config, publicKey string) Report {
+	var report Report
+
+	if b.Signature != nil || publicKey != "" {
+		if err := b.VerifySignature(publicKey); err != nil {
+			report.Errors = append(report.Errors, "signature: "+err.Error())
+		} else {
+			report.Signed = true
+		}
+	}
+
+	if outputHash, err := OutputHash(b.Results); err != nil || outputHash != b.OutputHash {
+		report.Errors = append(report.Errors, "output hash does not match the bundled results")
+	}
+
+	if version := codeVersion(); version != b.CodeVersion {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("code version %s differs from the bundle %s", version, b.CodeVersion))
+	}
+
+	for _, input := range []struct{ kind, file, sha256 string }{
+		{"genesis", b.Rules.GenesisFile, b.Rules.GenesisSHA256},
+		{"adjustments", b.Rules.AdjustmentsFile, b.Rules.AdjustmentsSHA256},
+	} {
+		if input.file == "" {
+			continue
+		}
+
+		sum, err := src.FileSHA256(input.file)
+
+		switch {
+		case err != nil:
+			report.Errors = append(report.Errors, fmt.Sprintf("%s file: %v", input.kind, err))
+		case sum != input.sha256:
+			report.Errors = append(report.Errors, fmt.Sprintf("%s file %s has changed", input.kind, input.file))
+		}
+	}
+
+	dataHash, err := DataHash(store, b.StartBlock, b.EndBlock, b.Rules)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("data hash: %v", err))
+		return report
+	}
+
+	if dataHash != b.DataHash {
+		report.Errors = append(report.Errors, "block data has changed since the bundle was created")
+	}
+
+	results := src.New(store, b.Rules.Apply(cfg)).ScoreValidators(b.StartBlock, b.EndBlock)
+	sortResults(results)
+
+	report.Mismatches = compareResults(b.Results, results)
+
+	return report
+}
+
+// compareResults - Lists the validators whose outputs differ
+func compareResults(expected, actual *src.Results) []string {
+	var mismatches []string
+
+	actualByAddr := make(map[string]src.Info)
+
+	for _, v := range actual.Validators {
+		actualByAddr[v.ValAddress] = v.Info
+	}
+
+	for _, v := range expected.Validators {
+		info, ok := actualByAddr[v.ValAddress]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s (%s): missing from the re-run", v.Info.Moniker, v.ValAddress))
+			continue
+		}
+
+		delete(actualByAddr, v.ValAddress)
+
+		if !reflect.DeepEqual(info, v.Info) {
+			mismatches = append(mismatches, fmt.Sprintf("%s (%s): total %f, re-run %f",
+				v.Info.Moniker, v.ValAddress, v.Info.TotalPoints, info.TotalPoints))
+		}
+	}
+
+	for _, v := range actual.Validators {
+		if _, ok := actualByAddr[v.ValAddress]; ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s (%s): not in the bundle", v.Info.Moniker, v.ValAddress))
+		}
+	}
+
+	if len(expected.Unapplied) != len(actual.Unapplied) {
+		mismatches = append(mismatches, fmt.Sprintf("%d unapplied adjustments, re-run %d",
+			len(expected.Unapplied), len(actual.Unapplied)))
+	}
+
+	if len(expected.Unresolved) != len(actual.Unresolved) {
+		mismatches = append(mismatches, fmt.Sprintf("%d unresolved validators, re-run %d",
+			len(expected.Unresolved), len(actual.Unresolved)))
+	}
+
+	return mismatches
+}
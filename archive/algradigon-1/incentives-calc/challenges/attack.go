@@ -0,0 +1,334 @@
+package challenges
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// verdicts of an attack claim
+const (
+	VERDICT_PROVEN     = "proven"
+	VERDICT_NOT_PROVEN = "not proven"
+	VERDICT_INVALID    = "invalid"
+)
+
+// ChainSource is used for evaluating attack claims against the indexed blocks, the headers
+// (headers ingest) are needed to resolve attack windows given as times
+type ChainSource interface {
+	db.TxSource
+	QueryBlocks(fromHeight, toHeight int64) ([]db.Blocks, error)
+	QueryBlockHeaders(fromHeight, toHeight int64) ([]db.BlockHeader, error)
+	QueryLatestHeaderHeight() (int64, error)
+	QueryValidators() ([]db.Validator, error)
+}
+
+// AttackRules - Phase-6 attack bonus, see kontraua/PLAN.md. A target is taken down when it signed at
+// least MinBaseline of the baseline commits before the window and its signed ratio dropped by at least
+// MinDrop in the window. Baseline is the number of commits compared, the window length when 0. The
+// attack txs may start TxMargin blocks before the window
+type AttackRules struct {
+	Points      int64
+	Baseline    int64
+	MinBaseline float64
+	MinDrop     float64
+	TxMargin    int64
+}
+
+var DefaultAttackRules = AttackRules{Points: 200, MinBaseline: 0.9, MinDrop: 0.5, TxMargin: 100}
+
+// TargetMetrics compares the signing of a target in the attack window with its baseline,
+// longest missed is the longest run of consecutive missed commits in the window
+type TargetMetrics struct {
+	Operator        string   `json:"operator"`
+	Moniker         string   `json:"moniker"`
+	Address         string   `json:"address"`
+	BaselineCommits int64    `json:"baselineCommits"`
+	BaselineSigned  int64    `json:"baselineSigned"`
+	BaselineRatio   float64  `json:"baselineRatio"`
+	WindowCommits   int64    `json:"windowCommits"`
+	WindowSigned    int64    `json:"windowSigned"`
+	WindowRatio     float64  `json:"windowRatio"`
+	Drop            float64  `json:"drop"`
+	LongestMissed   int64    `json:"longestMissed"`
+	Down            bool     `json:"down"`
+	Errors          []string `json:"errors"`
+}
+
+// AttackVerdict is the evaluation of an attack claim with its supporting metrics
+type AttackVerdict struct {
+	File        string          `json:"file"`
+	Operator    string          `json:"operator"`
+	StartHeight int64           `json:"startHeight"`
+	EndHeight   int64           `json:"endHeight"`
+	Targets     []TargetMetrics `json:"targets"`
+	Txs         []TxCheck       `json:"txs"`
+	ValidTxs    int             `json:"validTxs"`
+	Verdict     string          `json:"verdict"`
+	Points      int64           `json:"points"`
+	Reasons     []string        `json:"reasons"`
+	Errors      []string        `json:"errors"`
+}
+
+// AttackEvaluator evaluates Phase-6 attack claims
+type AttackEvaluator struct {
+	chain ChainSource
+	Rules AttackRules
+}
+
+func NewAttackEvaluator(chain ChainSource) *AttackEvaluator {
+	return &AttackEvaluator{chain: chain, Rules: DefaultAttackRules}
+}
+
+// Evaluate - Checks the attacker's txs and compares the signing of the targets in the attack
+// window with their baseline. The claim is proven when a target was taken down and at least
+// one attack tx of the attacker was included around the window
+func (e *AttackEvaluator) Evaluate(file string, s *Phase6Submission) AttackVerdict {
+	verdict := AttackVerdict{File: file, Operator: s.ValOprAddr, Verdict: VERDICT_INVALID}
+
+	if verdict.Errors = s.Validate(); len(verdict.Errors) > 0 {
+		return verdict
+	}
+
+	start, end, err := e.window(s)
+	if err != nil {
+		verdict.Errors = append(verdict.Errors, err.Error())
+		return verdict
+	}
+
+	verdict.StartHeight, verdict.EndHeight = start, end
+
+	attackerAcc, err := address.ValOperToAccount(s.ValOprAddr)
+	if err != nil {
+		verdict.Errors = append(verdict.Errors, err.Error())
+		return verdict
+	}
+
+	for _, hash := range s.Txs {
+		check := e.checkAttackTx(hash, attackerAcc, start, end)
+		verdict.Txs = append(verdict.Txs, check)
+
+		if check.Valid() {
+			verdict.ValidTxs++
+		}
+	}
+
+	targets, err := e.targetMetrics(s.Targets, start, end)
+	if err != nil {
+		verdict.Errors = append(verdict.Errors, err.Error())
+		return verdict
+	}
+
+	verdict.Targets = targets
+
+	down := 0
+
+	for _, t := range targets {
+		if t.Down {
+			down++
+		}
+	}
+
+	if down == 0 {
+		verdict.Reasons = append(verdict.Reasons, fmt.Sprintf("no target signed less than %.0f%% of its baseline ratio in the window",
+			(1-e.Rules.MinDrop)*100))
+	}
+
+	if verdict.ValidTxs == 0 {
+		verdict.Reasons = append(verdict.Reasons, "no attack tx of the attacker was found around the window")
+	}
+
+	verdict.Verdict = VERDICT_NOT_PROVEN
+
+	if down > 0 && verdict.ValidTxs > 0 {
+		verdict.Verdict = VERDICT_PROVEN
+		verdict.Points = e.Rules.Points
+		verdict.Reasons = append(verdict.Reasons, fmt.Sprintf("%d of %d targets taken down", down, len(targets)))
+	}
+
+	return verdict
+}
+
+// window - Returns the attack heights, times are resolved to the first block at or after the start
+// time and the last block at or before the end time
+func (e *AttackEvaluator) window(s *Phase6Submission) (int64, int64, error) {
+	if s.StartHeight > 0 {
+		return s.StartHeight, s.EndHeight, nil
+	}
+
+	startTime, _ := time.Parse(time.RFC3339, s.StartTime)
+	endTime, _ := time.Parse(time.RFC3339, s.EndTime)
+
+	start, err := e.firstHeightAfter(startTime, false)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err := e.firstHeightAfter(endTime, true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end-1 < start {
+		return 0, 0, fmt.Errorf("no block in the attack window %s - %s", s.StartTime, s.EndTime)
+	}
+
+	return start, end - 1, nil
+}
+
+// firstHeightAfter - Binary search of the first header at (or strictly after) the time, the
+// height after the latest header when there is none
+func (e *AttackEvaluator) firstHeightAfter(t time.Time, strict bool) (int64, error) {
+	latest, err := e.chain.QueryLatestHeaderHeight()
+	if err != nil {
+		return 0, err
+	}
+
+	if latest == 0 {
+		return 0, fmt.Errorf("no block headers are ingested, run headers ingest or give the window as heights")
+	}
+
+	lo, hi := int64(1), latest+1
+
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		headers, err := e.chain.QueryBlockHeaders(mid, mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(headers) == 0 {
+			return 0, fmt.Errorf("the header of height %d is not ingested", mid)
+		}
+
+		after := headers[0].Time.After(t) || (!strict && headers[0].Time.Equal(t))
+
+		if after {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	return lo, nil
+}
+
+// checkAttackTx - Checks that the tx was signed by the attacker around the window. Failed txs are
+// accepted, a spam tx loads the network whether it succeeds or not
+func (e *AttackEvaluator) checkAttackTx(hash, attackerAcc string, start, end int64) TxCheck {
+	check := TxCheck{Hash: hash, Kind: "attack"}
+
+	tx, err := e.chain.QueryTxByHash(hash)
+	if err != nil {
+		check.Errors = append(check.Errors, fmt.Sprintf("lookup failed: %v", err))
+		return check
+	}
+
+	if tx == nil {
+		check.Errors = append(check.Errors, "tx not found")
+		return check
+	}
+
+	check.Height = tx.Height
+
+	if len(tx.Signers) > 0 {
+		check.Signer = tx.Signers[0]
+	}
+
+	if !contains(tx.Signers, attackerAcc) {
+		check.Errors = append(check.Errors, fmt.Sprintf("not signed by the attacker account %s", attackerAcc))
+	}
+
+	if tx.Height < start-e.Rules.TxMargin || tx.Height > end {
+		check.Errors = append(check.Errors, fmt.Sprintf("tx height %d is outside of the attack window %d - %d", tx.Height,
+			start-e.Rules.TxMargin, end))
+	}
+
+	return check
+}
+
+// targetMetrics - Compares the commits of the window with the baseline commits before it, the
+// commit of a height is included in the next block
+func (e *AttackEvaluator) targetMetrics(targets []string, start, end int64) ([]TargetMetrics, error) {
+	baseline := e.Rules.Baseline
+	if baseline <= 0 {
+		baseline = end - start + 1
+	}
+
+	validators, err := e.chain.QueryValidators()
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := e.chain.QueryBlocks(start-baseline+1, end+1)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []TargetMetrics
+
+	for _, target := range targets {
+		m := TargetMetrics{Operator: target}
+
+		for _, v := range validators {
+			if v.OperatorAddress == target {
+				m.Address, m.Moniker = v.Address, v.Description.Moniker
+			}
+		}
+
+		if m.Address == "" {
+			m.Errors = append(m.Errors, "no consensus address found for the target")
+			metrics = append(metrics, m)
+			continue
+		}
+
+		var missed int64
+
+		for _, block := range blocks {
+			signed := contains(block.Validators, m.Address)
+
+			if block.Height <= start {
+				m.BaselineCommits++
+
+				if signed {
+					m.BaselineSigned++
+				}
+
+				continue
+			}
+
+			m.WindowCommits++
+
+			if signed {
+				m.WindowSigned++
+				missed = 0
+
+				continue
+			}
+
+			if missed++; missed > m.LongestMissed {
+				m.LongestMissed = missed
+			}
+		}
+
+		if m.BaselineCommits > 0 {
+			m.BaselineRatio = float64(m.BaselineSigned) / float64(m.BaselineCommits)
+		}
+
+		if m.WindowCommits > 0 {
+			m.WindowRatio = float64(m.WindowSigned) / float64(m.WindowCommits)
+		} else {
+			m.Errors = append(m.Errors, "no blocks found in the attack window")
+		}
+
+		m.Drop = m.BaselineRatio - m.WindowRatio
+		m.Down = m.WindowCommits > 0 && m.BaselineRatio >= e.Rules.MinBaseline && m.Drop >= e.Rules.MinDrop
+
+		metrics = append(metrics, m)
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,230 @@
+package challenges
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+var attackStart = time.Date(2020, 4, 20, 10, 0, 0, 0, time.UTC)
+
+// attackChain is a chain source of blocks and headers in memory, txs are looked up in the txMap
+type attackChain struct {
+	txMap
+	blocks     []db.Blocks
+	headers    []db.BlockHeader
+	validators []db.Validator
+}
+
+func (c *attackChain) QueryBlocks(fromHeight, toHeight int64) ([]db.Blocks, error) {
+	var result []db.Blocks
+
+	for _, b := range c.blocks {
+		if b.Height >= fromHeight && b.Height <= toHeight {
+			result = append(result, b)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *attackChain) QueryBlockHeaders(fromHeight, toHeight int64) ([]db.BlockHeader, error) {
+	var result []db.BlockHeader
+
+	for _, h := range c.headers {
+		if h.Height >= fromHeight && h.Height <= toHeight {
+			result = append(result, h)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *attackChain) QueryLatestHeaderHeight() (int64, error) {
+	return int64(len(c.headers)), nil
+}
+
+func (c *attackChain) QueryValidators() ([]db.Validator, error) {
+	return c.validators, nil
+}
+
+// valoper - A valid operator address of 20 times the byte
+func valoper(t *testing.T, b byte) string {
+	addr, err := address.ConvertAndEncode(address.ValOperPrefix, []byte(strings.Repeat(string([]byte{b}), 20)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return addr
+}
+
+func attackHash(c string) string {
+	return strings.Repeat(c, 64)
+}
+
+// testAttackChain - Headers 1 to 40 a minute apart and blocks 1 to 41. T1 misses the commits of heights
+// 21 to 30, T2 signs every commit and T3 only the even blocks up to 21. The attacker sent tx A at 15, B
+// was sent by someone else and C after the window
+func testAttackChain(t *testing.T) *attackChain {
+	attacker, err := address.ValOperToAccount(valoper(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &attackChain{
+		txMap: txMap{
+			attackHash("A"): {Hash: attackHash("A"), Height: 15, Signers: []string{attacker}},
+			attackHash("B"): {Hash: attackHash("B"), Height: 25, Signers: []string{"xrn:1someone"}},
+			attackHash("C"): {Hash: attackHash("C"), Height: 35, Signers: []string{attacker}, Code: 11},
+		},
+		validators: []db.Validator{
+			{Address: "T1", OperatorAddress: valoper(t, 2), Description: db.Description{Moniker: "t1"}},
+			{Address: "T2", OperatorAddress: valoper(t, 3)},
+			{Address: "T3", OperatorAddress: valoper(t, 4)},
+		},
+	}
+
+	for height := int64(1); height <= 41; height++ {
+		if height <= 40 {
+			c.headers = append(c.headers, db.BlockHeader{Height: height, Time: attackStart.Add(time.Duration(height) * time.Minute)})
+		}
+
+		signers := []string{"T2"}
+
+		if height < 22 || height > 31 {
+			signers = append(signers, "T1")
+		}
+
+		if height <= 21 && height%2 == 0 {
+			signers = append(signers, "T3")
+		}
+
+		c.blocks = append(c.blocks, db.Blocks{Height: height, Validators: signers})
+	}
+
+	return c
+}
+
+func TestAttackEvaluate(t *testing.T) {
+	chain := testAttackChain(t)
+
+	submission := func(targets []byte, txs string) *Phase6Submission {
+		s := &Phase6Submission{ValOprAddr: valoper(t, 1), StartHeight: 21, EndHeight: 30, Description: "spam"}
+
+		for _, b := range targets {
+			s.Targets = append(s.Targets, valoper(t, b))
+		}
+
+		for _, c := range txs {
+			s.Txs = append(s.Txs, attackHash(string(c)))
+		}
+
+		return s
+	}
+
+	byTime := submission([]byte{2}, "A")
+	byTime.StartHeight, byTime.EndHeight = 0, 0
+	byTime.StartTime = attackStart.Add(21 * time.Minute).Format(time.RFC3339)
+	byTime.EndTime = attackStart.Add(30*time.Minute + 30*time.Second).Format(time.RFC3339)
+
+	invalid := submission([]byte{2}, "A")
+	invalid.Description = ""
+
+	tests := []struct {
+		name       string
+		submission *Phase6Submission
+		verdict    string
+		down       []bool
+		validTxs   int
+		start, end int64
+	}{
+		{name: "target taken down", submission: submission([]byte{2, 3}, "A"), verdict: VERDICT_PROVEN, down: []bool{true, false}, validTxs: 1, start: 21, end: 30},
+		{name: "targets kept signing or were offline", submission: submission([]byte{3, 4}, "A"), verdict: VERDICT_NOT_PROVEN, down: []bool{false, false}, validTxs: 1, start: 21, end: 30},
+		{name: "no attack tx of the attacker", submission: submission([]byte{2}, "BCD"), verdict: VERDICT_NOT_PROVEN, down: []bool{true}, start: 21, end: 30},
+		{name: "window given as times", submission: byTime, verdict: VERDICT_PROVEN, down: []bool{true}, validTxs: 1, start: 21, end: 30},
+		{name: "unknown target", submission: submission([]byte{9}, "A"), verdict: VERDICT_NOT_PROVEN, down: []bool{false}, validTxs: 1, start: 21, end: 30},
+		{name: "invalid submission", submission: invalid, verdict: VERDICT_INVALID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewAttackEvaluator(chain).Evaluate("claim.json", tt.submission)
+
+			var down []bool
+			for _, target := range v.Targets {
+				down = append(down, target.Down)
+			}
+
+			if v.Verdict != tt.verdict || !reflect.DeepEqual(down, tt.down) || v.ValidTxs != tt.validTxs {
+				t.Errorf("verdict = %s down %v valid txs %d, want %s %v %d\n%+v", v.Verdict, down, v.ValidTxs,
+					tt.verdict, tt.down, tt.validTxs, v)
+			}
+
+			if v.StartHeight != tt.start || v.EndHeight != tt.end {
+				t.Errorf("window = %d - %d, want %d - %d", v.StartHeight, v.EndHeight, tt.start, tt.end)
+			}
+
+			if (v.Points > 0) != (tt.verdict == VERDICT_PROVEN) {
+				t.Errorf("points = %d with verdict %s", v.Points, v.Verdict)
+			}
+		})
+	}
+
+	//T1 signed the 10 baseline commits and missed the 10 of the window
+	v := NewAttackEvaluator(chain).Evaluate("claim.json", submission([]byte{2}, "ABC"))
+
+	target := v.Targets[0]
+	if target.Address != "T1" || target.Moniker != "t1" || target.BaselineSigned != 10 || target.BaselineCommits != 10 ||
+		target.WindowSigned != 0 || target.WindowCommits != 10 || target.LongestMissed != 10 || target.Drop != 1 {
+		t.Errorf("target metrics = %+v", target)
+	}
+
+	var valid []bool
+	for _, check := range v.Txs {
+		valid = append(valid, check.Valid())
+	}
+
+	//Failed txs count, only the signer and the height are checked
+	if !reflect.DeepEqual(valid, []bool{true, false, false}) {
+		t.Errorf("tx checks = %+v", v.Txs)
+	}
+}
+
+func TestFirstHeightAfter(t *testing.T) {
+	e := NewAttackEvaluator(testAttackChain(t))
+
+	at := func(height int64) time.Time { return attackStart.Add(time.Duration(height) * time.Minute) }
+
+	tests := []struct {
+		name   string
+		time   time.Time
+		strict bool
+		want   int64
+	}{
+		{name: "before the first header", time: attackStart, want: 1},
+		{name: "at a header", time: at(5), want: 5},
+		{name: "strictly after a header", time: at(5), strict: true, want: 6},
+		{name: "in between headers", time: at(5).Add(time.Second), want: 6},
+		{name: "at the latest header", time: at(40), want: 40},
+		{name: "after the latest header", time: at(40), strict: true, want: 41},
+	}
+
+	for _, tt := range tests {
+		got, err := e.firstHeightAfter(tt.time, tt.strict)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: firstHeightAfter = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := NewAttackEvaluator(&attackChain{}).firstHeightAfter(attackStart, false); err == nil {
+		t.Error("firstHeightAfter found a height without ingested headers")
+	}
+}
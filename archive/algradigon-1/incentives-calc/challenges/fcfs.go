@@ -0,0 +1,259 @@
+package challenges
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// Claim is a tx submitted by a participant for a challenge, or the answer of a challenge without
+// tx, file is the submission file used for the git commit time
+type Claim struct {
+	Participant string `json:"participant"`
+	Challenge   string `json:"challenge"`
+	TxHash      string `json:"txHash,omitempty"`
+	Answer      string `json:"answer,omitempty"`
+	File        string `json:"file"`
+}
+
+// Evidence sources, chain evidence is preferred over the git commit time
+const (
+	EvidenceChain = "chain"
+	EvidenceGit   = "git"
+)
+
+type Evidence struct {
+	Source     string    `json:"source"`
+	Height     int64     `json:"height,omitempty"`
+	TxTime     time.Time `json:"txTime,omitempty"`
+	Commit     string    `json:"commit,omitempty"`
+	CommitTime time.Time `json:"commitTime,omitempty"`
+}
+
+// DeadlineRule describes how the claims of a challenge are ranked. Only claims committed
+// (and sent) before the cutoff are counted, participants are ranked by their first tx, or
+// by their last one for challenges ranked on completion. FirstOnly makes only the first
+// participant a winner, otherwise the first Winners participants win (all when 0)
+type DeadlineRule struct {
+	Cutoff    time.Time
+	FirstOnly bool
+	Winners   int
+	RankLast  bool
+}
+
+// Resolution is the outcome of a claim, excluded claims have the reason set
+type Resolution struct {
+	Claim    Claim    `json:"claim"`
+	Evidence Evidence `json:"evidence"`
+	Rank     int      `json:"rank,omitempty"`
+	Winner   bool     `json:"winner"`
+	Excluded string   `json:"excluded,omitempty"`
+}
+
+// ResolveClaims - Orders the claims of every challenge by the block height of the referenced tx,
+// claims without an indexed tx fall back to the commit time of their file and are ranked after
+// the claims with chain evidence. Either the txs or git may be nil
+func ResolveClaims(claims []Claim, txs db.TxSource, git *GitRepo, rule DeadlineRule) []Resolution {
+	var resolutions []Resolution
+
+	for _, claim := range claims {
+		resolutions = append(resolutions, resolveClaim(claim, txs, git, rule))
+	}
+
+	//Pick a single claim per participant and challenge
+	best := make(map[string]int)
+
+	for i, res := range resolutions {
+		if res.Excluded != "" {
+			continue
+		}
+
+		key := res.Claim.Challenge + "/" + res.Claim.Participant
+
+		j, ok := best[key]
+		if !ok {
+			best[key] = i
+			continue
+		}
+
+		if evidenceBefore(res.Evidence, resolutions[j].Evidence) != rule.RankLast {
+			resolutions[j].Excluded = "participant has a better ranked claim " + res.Claim.TxHash
+			best[key] = i
+		} else {
+			resolutions[i].Excluded = "participant has a better ranked claim " + resolutions[j].Claim.TxHash
+		}
+	}
+
+	sort.SliceStable(resolutions, func(i, j int) bool {
+		a, b := resolutions[i], resolutions[j]
+
+		if a.Claim.Challenge != b.Claim.Challenge {
+			return challengeLess(a.Claim.Challenge, b.Claim.Challenge)
+		}
+
+		if (a.Excluded == "") != (b.Excluded == "") {
+			return a.Excluded == ""
+		}
+
+		return evidenceBefore(a.Evidence, b.Evidence)
+	})
+
+	rank := 0
+
+	for i := range resolutions {
+		if i == 0 || resolutions[i].Claim.Challenge != resolutions[i-1].Claim.Challenge {
+			rank = 0
+		}
+
+		if resolutions[i].Excluded != "" {
+			continue
+		}
+
+		rank++
+		resolutions[i].Rank = rank
+
+		switch {
+		case rule.FirstOnly:
+			resolutions[i].Winner = rank == 1
+		case rule.Winners > 0:
+			resolutions[i].Winner = rank <= rule.Winners
+		default:
+			resolutions[i].Winner = true
+		}
+	}
+
+	return resolutions
+}
+
+func resolveClaim(claim Claim, txs db.TxSource, git *GitRepo, rule DeadlineRule) Resolution {
+	res := Resolution{Claim: claim}
+
+	if git != nil && claim.File != "" {
+		commit, commitTime, err := git.FirstCommit(claim.File)
+		if err == nil {
+			res.Evidence.Source = EvidenceGit
+			res.Evidence.Commit = commit
+			res.Evidence.CommitTime = commitTime
+		}
+	}
+
+	//An answer has no tx and is ranked on its commit only
+	if txs != nil && claim.TxHash != "" {
+		tx, err := txs.QueryTxByHash(claim.TxHash)
+
+		switch {
+		case err != nil:
+			res.Excluded = fmt.Sprintf("tx lookup failed: %v", err)
+			return res
+		case tx != nil && tx.Code != 0:
+			res.Excluded = fmt.Sprintf("tx failed with code %d", tx.Code)
+			return res
+		case tx != nil:
+			res.Evidence.Source = EvidenceChain
+			res.Evidence.Height = tx.Height
+			res.Evidence.TxTime = tx.Time
+		}
+	}
+
+	if res.Evidence.Source == "" {
+		res.Excluded = "no tx or commit found"
+		return res
+	}
+
+	if rule.Cutoff.IsZero() {
+		return res
+	}
+
+	if !res.Evidence.CommitTime.IsZero() && res.Evidence.CommitTime.After(rule.Cutoff) {
+		res.Excluded = "committed after the cutoff " + rule.Cutoff.UTC().Format(time.RFC3339)
+	} else if !res.Evidence.TxTime.IsZero() && res.Evidence.TxTime.After(rule.Cutoff) {
+		res.Excluded = "tx sent after the cutoff " + rule.Cutoff.UTC().Format(time.RFC3339)
+	}
+
+	return res
+}
+
+// evidenceBefore - Chain evidence is ordered by height and comes before git evidence,
+// which is ordered by commit time
+func evidenceBefore(a, b Evidence) bool {
+	if (a.Source == EvidenceChain) != (b.Source == EvidenceChain) {
+		return a.Source == EvidenceChain
+	}
+
+	if a.Source == EvidenceChain && a.Height != b.Height {
+		return a.Height < b.Height
+	}
+
+	return a.CommitTime.Before(b.CommitTime)
+}
+
+// challengeLess - Orders numbered challenges numerically
+func challengeLess(a, b string) bool {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+
+	if errA == nil && errB == nil {
+		return x < y
+	}
+
+	return a < b
+}
+
+// TreasureHuntClaims - Converts the parsed treasure hunt into claims, conflicting
+// submissions are skipped as it is unknown which of the files is meant. An answer without
+// tx is a single claim ranked on the commit of its file
+func TreasureHuntClaims(dir string, hunt *TreasureHunt) []Claim {
+	var claims []Claim
+
+	for _, submission := range hunt.Submissions {
+		if submission.Conflict {
+			continue
+		}
+
+		if submission.Answer != "" {
+			claims = append(claims, Claim{
+				Participant: submission.Validator,
+				Challenge:   strconv.Itoa(submission.Challenge),
+				Answer:      submission.Answer,
+				File:        filepath.Join(dir, submission.Validator, submission.Files[0]),
+			})
+		}
+
+		for _, entry := range submission.Entries {
+			claims = append(claims, Claim{
+				Participant: submission.Validator,
+				Challenge:   strconv.Itoa(submission.Challenge),
+				TxHash:      entry.TxHash,
+				File:        filepath.Join(dir, submission.Validator, submission.Files[0]),
+			})
+		}
+	}
+
+	return claims
+}
+
+// SubmissionClaims - Converts validated challenge submissions into claims of their txs
+func SubmissionClaims(challenge, dir string, reports []FileReport) []Claim {
+	var claims []Claim
+
+	for _, report := range reports {
+		if report.Submission == nil || len(report.Errors) > 0 {
+			continue
+		}
+
+		for _, hash := range report.Submission.TxHashes() {
+			claims = append(claims, Claim{
+				Participant: report.Submission.Operator(),
+				Challenge:   challenge,
+				TxHash:      hash,
+				File:        filepath.Join(dir, report.File),
+			})
+		}
+	}
+
+	return claims
+}
@@ -0,0 +1,184 @@
+package challenges
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+var fcfsStart = time.Date(2020, 4, 20, 10, 0, 0, 0, time.UTC)
+
+// txMap is a tx source of txs by hash
+type txMap map[string]db.Transaction
+
+func (m txMap) QueryTxByHash(hash string) (*db.Transaction, error) {
+	tx, ok := m[hash]
+	if !ok {
+		return nil, nil
+	}
+
+	return &tx, nil
+}
+
+func (m txMap) QueryInstantiateTx(contract string) (*db.Transaction, error) {
+	return nil, nil
+}
+
+// fcfsTxs - The tx hash is the height it was included at, a minute apart, tx 99 failed
+func fcfsTxs() txMap {
+	txs := make(txMap)
+
+	for height := int64(1); height < 100; height++ {
+		tx := db.Transaction{Hash: fmt.Sprint(height), Height: height, Time: fcfsStart.Add(time.Duration(height) * time.Minute)}
+
+		if height == 99 {
+			tx.Code = 5
+		}
+
+		txs[tx.Hash] = tx
+	}
+
+	return txs
+}
+
+type ranking struct {
+	Participant string
+	Challenge   string
+	TxHash      string
+	Rank        int
+	Winner      bool
+	Excluded    bool
+}
+
+func rankings(resolutions []Resolution) []ranking {
+	var result []ranking
+
+	for _, r := range resolutions {
+		result = append(result, ranking{
+			r.Claim.Participant, r.Claim.Challenge, r.Claim.TxHash, r.Rank, r.Winner, r.Excluded != "",
+		})
+	}
+
+	return result
+}
+
+func TestResolveClaims(t *testing.T) {
+	claims := []Claim{
+		{Participant: "carol", Challenge: "10", TxHash: "3"},
+		{Participant: "bob", Challenge: "2", TxHash: "20"},
+		{Participant: "alice", Challenge: "2", TxHash: "30"},
+		{Participant: "alice", Challenge: "2", TxHash: "10"},
+		{Participant: "dave", Challenge: "2", TxHash: "99"},
+		{Participant: "erin", Challenge: "2", TxHash: "404"},
+		{Participant: "frank", Challenge: "2", TxHash: "40"},
+	}
+
+	tests := []struct {
+		name string
+		rule DeadlineRule
+		want []ranking
+	}{
+		{
+			name: "first tx wins, challenges in numeric order",
+			want: []ranking{
+				{"alice", "2", "10", 1, true, false},
+				{"bob", "2", "20", 2, true, false},
+				{"frank", "2", "40", 3, true, false},
+				{"alice", "2", "30", 0, false, true},
+				{"dave", "2", "99", 0, false, true},
+				{"erin", "2", "404", 0, false, true},
+				{"carol", "10", "3", 1, true, false},
+			},
+		},
+		{
+			name: "ranked on the last tx with two winners",
+			rule: DeadlineRule{RankLast: true, Winners: 2},
+			want: []ranking{
+				{"bob", "2", "20", 1, true, false},
+				{"alice", "2", "30", 2, true, false},
+				{"frank", "2", "40", 3, false, false},
+				{"alice", "2", "10", 0, false, true},
+				{"dave", "2", "99", 0, false, true},
+				{"erin", "2", "404", 0, false, true},
+				{"carol", "10", "3", 1, true, false},
+			},
+		},
+		{
+			name: "first only with a cutoff",
+			rule: DeadlineRule{FirstOnly: true, Cutoff: fcfsStart.Add(25 * time.Minute)},
+			want: []ranking{
+				{"alice", "2", "10", 1, true, false},
+				{"bob", "2", "20", 2, false, false},
+				{"alice", "2", "30", 0, false, true},
+				{"frank", "2", "40", 0, false, true},
+				{"dave", "2", "99", 0, false, true},
+				{"erin", "2", "404", 0, false, true},
+				{"carol", "10", "3", 1, true, false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rankings(ResolveClaims(claims, fcfsTxs(), nil, tt.rule))
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rankings =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveClaimExclusions(t *testing.T) {
+	rule := DeadlineRule{Cutoff: fcfsStart.Add(time.Hour)}
+
+	tests := []struct {
+		hash string
+		want string
+	}{
+		{hash: "10"},
+		{hash: "99", want: "tx failed with code 5"},
+		{hash: "404", want: "no tx or commit found"},
+		{hash: "61", want: "tx sent after the cutoff 2020-04-20T11:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		res := resolveClaim(Claim{Participant: "alice", Challenge: "1", TxHash: tt.hash}, fcfsTxs(), nil, rule)
+
+		if res.Excluded != tt.want {
+			t.Errorf("tx %s excluded = %q, want %q", tt.hash, res.Excluded, tt.want)
+		}
+
+		if tt.want == "" && (res.Evidence.Source != EvidenceChain || res.Evidence.Height != 10) {
+			t.Errorf("tx %s evidence = %+v, want chain evidence at 10", tt.hash, res.Evidence)
+		}
+	}
+}
+
+func TestEvidenceBefore(t *testing.T) {
+	chain := func(height int64) Evidence { return Evidence{Source: EvidenceChain, Height: height} }
+	git := func(minutes int) Evidence {
+		return Evidence{Source: EvidenceGit, CommitTime: fcfsStart.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	tests := []struct {
+		a, b Evidence
+		want bool
+	}{
+		{chain(1), chain(2), true},
+		{chain(2), chain(1), false},
+		{chain(100), git(0), true},
+		{git(0), chain(1), false},
+		{git(1), git(2), true},
+		{git(2), git(1), false},
+	}
+
+	for _, tt := range tests {
+		if got := evidenceBefore(tt.a, tt.b); got != tt.want {
+			t.Errorf("evidenceBefore(%+v, %+v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
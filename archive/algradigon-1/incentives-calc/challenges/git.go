@@ -0,0 +1,67 @@
+package challenges
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GitRepo looks up when submission files were first committed
+type GitRepo struct {
+	Dir   string
+	cache map[string]commitInfo
+}
+
+type commitInfo struct {
+	hash string
+	time time.Time
+	err  error
+}
+
+func NewGitRepo(dir string) *GitRepo {
+	return &GitRepo{Dir: dir, cache: make(map[string]commitInfo)}
+}
+
+// FirstCommit - Returns the hash and commit time of the commit which added the file
+func (g *GitRepo) FirstCommit(path string) (string, time.Time, error) {
+	if info, ok := g.cache[path]; ok {
+		return info.hash, info.time, info.err
+	}
+
+	info := g.firstCommit(path)
+	g.cache[path] = info
+
+	return info.hash, info.time, info.err
+}
+
+func (g *GitRepo) firstCommit(path string) commitInfo {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return commitInfo{err: err}
+	}
+
+	cmd := exec.Command("git", "log", "--diff-filter=A", "--format=%H %cI", "--", abs)
+	cmd.Dir = g.Dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return commitInfo{err: fmt.Errorf("git log %s: %v", path, err)}
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+
+	//Commits are listed newest first, the file could have been re-added after a delete
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) != 2 {
+		return commitInfo{err: fmt.Errorf("%s is not committed", path)}
+	}
+
+	commitTime, err := time.Parse(time.RFC3339, fields[1])
+	if err != nil {
+		return commitInfo{err: err}
+	}
+
+	return commitInfo{hash: fields[0], time: commitTime}
+}
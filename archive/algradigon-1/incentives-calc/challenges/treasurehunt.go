@@ -0,0 +1,281 @@
+package challenges
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TreasureEntry is a single height/txhash pair of a treasure hunt submission
+type TreasureEntry struct {
+	Height int64  `json:"height"`
+	TxHash string `json:"txHash"`
+}
+
+// TreasureSubmission holds the entries of a validator for a challenge, files lists every
+// file submitted for the challenge, the entries are taken from the first one. A file of text
+// only is the answer of a challenge without tx, such as the congo-1 challenge 5
+type TreasureSubmission struct {
+	Validator string          `json:"validator"`
+	Challenge int             `json:"challenge"`
+	Files     []string        `json:"files"`
+	Entries   []TreasureEntry `json:"entries"`
+	Answer    string          `json:"answer,omitempty"`
+	Conflict  bool            `json:"conflict"`
+}
+
+// Kinds of treasure hunt issues
+const (
+	IssueUnparseable = "unparseable"
+	IssueInvalid     = "invalid"
+	IssueConflict    = "conflict"
+)
+
+type TreasureIssue struct {
+	Kind      string `json:"kind"`
+	Validator string `json:"validator"`
+	File      string `json:"file"`
+	Message   string `json:"message"`
+}
+
+// TreasureHunt is the normalised set of treasure hunt submissions
+type TreasureHunt struct {
+	Submissions []*TreasureSubmission `json:"submissions"`
+	Issues      []TreasureIssue       `json:"issues"`
+}
+
+var (
+	//1.txt, Challenge1.txt, challenge-1.txt, challenge5
+	challengeFileRegex = regexp.MustCompile(`(?i)^(?:challenge)?[ _-]*(\d+)(?:\.txt)?$`)
+	//height: 700656, txhash: 9349..., tx hash = 9349...
+	entryLineRegex = regexp.MustCompile(`(?i)^\s*(height|tx\s*hash|hash)\s*[:=]\s*(\S+)\s*$`)
+)
+
+// ParseTreasureHunt - Parses the <validator>/<challenge file> submissions of a treasure hunt directory
+func ParseTreasureHunt(dir string) (*TreasureHunt, error) {
+	validators, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	hunt := &TreasureHunt{}
+
+	for _, validator := range validators {
+		if !validator.IsDir() {
+			continue
+		}
+
+		files, err := ioutil.ReadDir(filepath.Join(dir, validator.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		byChallenge := make(map[int]*TreasureSubmission)
+
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+
+			hunt.addFile(byChallenge, filepath.Join(dir, validator.Name()), validator.Name(), file.Name())
+		}
+
+		for _, submission := range byChallenge {
+			hunt.Submissions = append(hunt.Submissions, submission)
+		}
+	}
+
+	sort.Slice(hunt.Submissions, func(i, j int) bool {
+		a, b := hunt.Submissions[i], hunt.Submissions[j]
+		if a.Validator != b.Validator {
+			return a.Validator < b.Validator
+		}
+
+		return a.Challenge < b.Challenge
+	})
+
+	return hunt, nil
+}
+
+func (h *TreasureHunt) addFile(byChallenge map[int]*TreasureSubmission, dir, validator, name string) {
+	issue := func(kind, format string, args ...interface{}) {
+		h.Issues = append(h.Issues, TreasureIssue{
+			Kind: kind, Validator: validator, File: name, Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	match := challengeFileRegex.FindStringSubmatch(name)
+	if match == nil {
+		issue(IssueUnparseable, "file name has no challenge number")
+		return
+	}
+
+	challenge, _ := strconv.Atoi(match[1])
+
+	path := filepath.Join(dir, name)
+
+	entries, errs, err := parseTreasureFile(path)
+	if err != nil {
+		issue(IssueUnparseable, "%v", err)
+		return
+	}
+
+	answer := ""
+
+	if len(entries) == 0 {
+		if answer, err = parseTreasureAnswer(path); err != nil {
+			issue(IssueUnparseable, "%v", err)
+			return
+		}
+	}
+
+	if answer == "" {
+		for _, msg := range errs {
+			issue(IssueInvalid, "%s", msg)
+		}
+
+		if len(entries) == 0 {
+			issue(IssueUnparseable, "no height/txhash entries found")
+			return
+		}
+	}
+
+	submission, ok := byChallenge[challenge]
+	if !ok {
+		byChallenge[challenge] = &TreasureSubmission{
+			Validator: validator,
+			Challenge: challenge,
+			Files:     []string{name},
+			Entries:   entries,
+			Answer:    answer,
+		}
+
+		return
+	}
+
+	submission.Files = append(submission.Files, name)
+
+	if !reflect.DeepEqual(submission.Entries, entries) || submission.Answer != answer {
+		submission.Conflict = true
+		issue(IssueConflict, "entries differ from %s for challenge %d", submission.Files[0], challenge)
+	}
+}
+
+// parseTreasureAnswer - Reads the text of a file without any height or txhash line, the
+// answer is empty when the file has such lines or no text
+func parseTreasureAnswer(path string) (string, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+
+	for _, line := range strings.Split(string(bz), "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		if entryLineRegex.MatchString(line) {
+			return "", nil
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// parseTreasureFile - Reads the height/txhash pairs of a file. A height without txhash,
+// or the other way around, and malformed values are returned as errors
+func parseTreasureFile(path string) ([]TreasureEntry, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer file.Close()
+
+	var (
+		entries []TreasureEntry
+		errs    []string
+		current *TreasureEntry
+		lineNo  int
+	)
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+
+		switch {
+		case current.TxHash == "":
+			errs = append(errs, fmt.Sprintf("height %d has no txhash", current.Height))
+		case current.Height == 0:
+			errs = append(errs, fmt.Sprintf("txhash %s has no height", current.TxHash))
+		default:
+			entries = append(entries, *current)
+		}
+
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		lineNo++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		match := entryLineRegex.FindStringSubmatch(line)
+		if match == nil {
+			errs = append(errs, fmt.Sprintf("line %d: unexpected %q", lineNo, line))
+			continue
+		}
+
+		key, value := strings.ToLower(match[1]), match[2]
+
+		if key == "height" {
+			flush()
+
+			height, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || height <= 0 {
+				errs = append(errs, fmt.Sprintf("line %d: invalid height %q", lineNo, value))
+				continue
+			}
+
+			current = &TreasureEntry{Height: height}
+
+			continue
+		}
+
+		if err := ValidateTxHash(value); err != nil {
+			errs = append(errs, fmt.Sprintf("line %d: %v", lineNo, err))
+			current = nil
+			continue
+		}
+
+		if current == nil || current.TxHash != "" {
+			flush()
+			current = &TreasureEntry{}
+		}
+
+		current.TxHash = strings.ToUpper(value)
+	}
+
+	flush()
+
+	return entries, errs, scanner.Err()
+}
@@ -0,0 +1,277 @@
+package challenges
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	hashA = strings.Repeat("a", 64)
+	hashB = strings.Repeat("B", 64)
+)
+
+// writeHunt - Writes the files of a treasure hunt directory, keyed by <validator>/<file>
+func writeHunt(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "hunt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestParseTreasureFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		entries []TreasureEntry
+		errs    []string
+	}{
+		{
+			name:    "height and txhash",
+			content: "height: 700656\ntxhash: " + hashA + "\n",
+			entries: []TreasureEntry{{Height: 700656, TxHash: strings.ToUpper(hashA)}},
+		},
+		{
+			name:    "spelling variants and blank lines",
+			content: "Height = 10\n\n  tx hash: " + hashA + "\nHEIGHT:11\nhash=" + hashB + "\n",
+			entries: []TreasureEntry{
+				{Height: 10, TxHash: strings.ToUpper(hashA)},
+				{Height: 11, TxHash: hashB},
+			},
+		},
+		{
+			name:    "height without txhash",
+			content: "height: 10\nheight: 11\ntxhash: " + hashA + "\n",
+			entries: []TreasureEntry{{Height: 11, TxHash: strings.ToUpper(hashA)}},
+			errs:    []string{"height 10 has no txhash"},
+		},
+		{
+			name:    "txhash without height",
+			content: "txhash: " + hashA + "\n",
+			errs:    []string{"txhash " + strings.ToUpper(hashA) + " has no height"},
+		},
+		{
+			name:    "malformed values",
+			content: "height: -1\ntxhash: 1234\nsomething else\n",
+			errs: []string{
+				`line 1: invalid height "-1"`,
+				`line 2: invalid tx hash "1234"`,
+				`line 3: unexpected "something else"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeHunt(t, map[string]string{"file.txt": tt.content})
+			defer os.RemoveAll(dir)
+
+			entries, errs, err := parseTreasureFile(filepath.Join(dir, "file.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(entries, tt.entries) {
+				t.Errorf("entries = %+v, want %+v", entries, tt.entries)
+			}
+
+			if !reflect.DeepEqual(errs, tt.errs) {
+				t.Errorf("errors = %q, want %q", errs, tt.errs)
+			}
+		})
+	}
+}
+
+func TestParseTreasureHunt(t *testing.T) {
+	entry := "height: 10\ntxhash: " + hashA + "\n"
+
+	dir := writeHunt(t, map[string]string{
+		//Same challenge under different names, identical entries
+		"val1/1.txt":          entry,
+		"val1/Challenge1.txt": entry,
+		"val1/challenge-5":    entry,
+		//Same challenge with differing entries
+		"val2/challenge2.txt": entry,
+		"val2/2.txt":          "height: 11\ntxhash: " + hashB + "\n",
+		"val2/notes.md":       entry,
+		"val2/3.txt":          "height: 12\nnothing here\n",
+		"readme.txt":          "not a validator directory",
+	})
+	defer os.RemoveAll(dir)
+
+	hunt, err := ParseTreasureHunt(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type key struct {
+		validator string
+		challenge int
+		files     int
+		conflict  bool
+	}
+
+	var got []key
+	for _, s := range hunt.Submissions {
+		got = append(got, key{s.Validator, s.Challenge, len(s.Files), s.Conflict})
+	}
+
+	want := []key{{"val1", 1, 2, false}, {"val1", 5, 1, false}, {"val2", 2, 2, true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("submissions = %+v, want %+v", got, want)
+	}
+
+	kinds := make(map[string]string)
+	for _, issue := range hunt.Issues {
+		kinds[issue.Validator+"/"+issue.File] += issue.Kind + " "
+	}
+
+	//Files are read by name, the conflict is reported on challenge2.txt read after 2.txt
+	wantKinds := map[string]string{
+		"val2/notes.md":       IssueUnparseable + " ",
+		"val2/3.txt":          IssueInvalid + " " + IssueInvalid + " " + IssueUnparseable + " ",
+		"val2/challenge2.txt": IssueConflict + " ",
+	}
+
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Errorf("issues = %v, want %v", kinds, wantKinds)
+	}
+
+	//Conflicting submissions are not claimed
+	claims := TreasureHuntClaims(dir, hunt)
+	if len(claims) != 2 || claims[0].Challenge != "1" || claims[1].Challenge != "5" {
+		t.Fatalf("claims = %+v, want challenges 1 and 5 of val1", claims)
+	}
+
+	if claims[0].File != filepath.Join(dir, "val1", "1.txt") {
+		t.Errorf("claim file = %s, want the first file of the challenge", claims[0].File)
+	}
+}
+
+// TestTreasureHuntAnswers - The congo-1 challenge 5 was answered in text, novy/challenge5 and
+// witval/5.txt are claimed without tx and ranked on their commit
+func TestTreasureHuntAnswers(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := writeHunt(t, nil)
+	defer os.RemoveAll(repo)
+
+	//The submissions are a directory of the repo, as congo-1/treasure-hunt
+	dir := filepath.Join(repo, "treasure-hunt")
+
+	commit := func(files map[string]string, at time.Time) {
+		for name, content := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		date := at.Format(time.RFC3339)
+
+		for _, args := range [][]string{
+			{"add", "-A"},
+			{"-c", "user.name=test", "-c", "user.email=test@localhost", "commit", "-q", "-m", date},
+		} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = repo
+			cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+	}
+
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	start := time.Date(2019, 12, 23, 12, 0, 0, 0, time.UTC)
+
+	commit(map[string]string{
+		"witval/5.txt": "ooooh\u2026 so cryptic!\n",
+		"witval/4.txt": "height: 10\ntxhash: " + hashA + "\n",
+	}, start.Add(time.Hour))
+	commit(map[string]string{
+		"novy/challenge5": "Just remember, the true spirit of Christmas lies in your heart\n",
+		"novy/4.txt":      "height: 11\ntxhash: " + hashB + "\n",
+		//Differing answers are a conflict as any other differing files
+		"bob/5.txt":      "first answer\n",
+		"bob/challenge5": "second answer\n",
+	}, start.Add(10*time.Minute))
+
+	hunt, err := ParseTreasureHunt(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hunt.Issues) != 1 || hunt.Issues[0].Kind != IssueConflict || hunt.Issues[0].Validator != "bob" {
+		t.Errorf("issues = %+v, want the conflict of bob", hunt.Issues)
+	}
+
+	type claim struct {
+		Participant string
+		Challenge   string
+		TxHash      string
+		Answer      string
+	}
+
+	var got []claim
+	for _, c := range TreasureHuntClaims(dir, hunt) {
+		got = append(got, claim{c.Participant, c.Challenge, c.TxHash, c.Answer})
+	}
+
+	want := []claim{
+		{"novy", "4", hashB, ""},
+		{"novy", "5", "", "Just remember, the true spirit of Christmas lies in your heart"},
+		{"witval", "4", strings.ToUpper(hashA), ""},
+		{"witval", "5", "", "ooooh\u2026 so cryptic!"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("claims = %+v, want %+v", got, want)
+	}
+
+	//The answers are not looked up as txs, novy committed first
+	txs := make(txMap)
+
+	var ranked []ranking
+	for _, r := range rankings(ResolveClaims(TreasureHuntClaims(dir, hunt), txs, NewGitRepo(repo), DeadlineRule{})) {
+		if r.Challenge == "5" {
+			ranked = append(ranked, r)
+		}
+	}
+
+	wantRanked := []ranking{{"novy", "5", "", 1, true, false}, {"witval", "5", "", 2, true, false}}
+
+	if !reflect.DeepEqual(ranked, wantRanked) {
+		t.Errorf("challenge 5 rankings = %+v, want %+v", ranked, wantRanked)
+	}
+}
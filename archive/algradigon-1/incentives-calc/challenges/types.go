@@ -0,0 +1,275 @@
+package challenges
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+)
+
+// Submission is a participant's challenge submission file
+type Submission interface {
+	Operator() string
+	TxHashes() []string
+	Validate() []string
+}
+
+// Submission schemas by challenge, named after the challenge directories
+var schemas = map[string]func() Submission{
+	"phase-2": func() Submission { return &Phase2Submission{} },
+	"phase-3": func() Submission { return &Phase3Submission{} },
+	"phase-4": func() Submission { return &Phase4Submission{} },
+	"phase-6": func() Submission { return &Phase6Submission{} },
+}
+
+// Challenges where an operator can submit more than one file, every phase-6 attack is a separate claim
+var multipleSubmissions = map[string]bool{
+	"phase-6": true,
+}
+
+// Phase2Submission - ERC20 token contract challenge
+type Phase2Submission struct {
+	ValOprAddr      string           `json:"valOprAddr"`
+	CodeID          CodeID           `json:"codeId"`
+	ContractAddress string           `json:"contractAddress"`
+	Transfers       []string         `json:"transfers"`
+	Allowance       []string         `json:"allowance"`
+	EditContractTxs []EditContractTx `json:"editContractTxs"`
+}
+
+type EditContractTx struct {
+	TxHash      string `json:"txHash"`
+	Description string `json:"description"`
+}
+
+// Phase3Submission - Himalaya upgrade proposal vote and tweet
+type Phase3Submission struct {
+	ValOprAddr string `json:"valOprAddr"`
+	TweetURL   string `json:"tweetURL"`
+}
+
+// Phase4Submission - Escrow contract challenge
+type Phase4Submission struct {
+	ValOprAddr      string   `json:"valOprAddr"`
+	CodeID          CodeID   `json:"codeId"`
+	ContractAddress string   `json:"contractAddress"`
+	Approve         []string `json:"approve"`
+	Refund          []string `json:"refund"`
+}
+
+// Phase6Submission - Attack claim, the validators taken down, the attack window as heights or
+// RFC3339 times and the txs of the attack
+type Phase6Submission struct {
+	ValOprAddr  string   `json:"valOprAddr"`
+	Targets     []string `json:"targets"`
+	StartHeight int64    `json:"startHeight,omitempty"`
+	EndHeight   int64    `json:"endHeight,omitempty"`
+	StartTime   string   `json:"startTime,omitempty"`
+	EndTime     string   `json:"endTime,omitempty"`
+	Txs         []string `json:"txHashes"`
+	Description string   `json:"description"`
+}
+
+// CodeID accepts the code id both as a number and as a string, as the sample file uses a string
+type CodeID uint64
+
+func (c *CodeID) UnmarshalJSON(bz []byte) error {
+	value := strings.Trim(string(bz), `"`)
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid code id %s", bz)
+	}
+
+	*c = CodeID(id)
+
+	return nil
+}
+
+func (c CodeID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uint64(c))
+}
+
+var txHashRegex = regexp.MustCompile(`^[0-9A-Fa-f]{64}$`)
+
+// ValidateTxHash - Checks that the hash is a hex encoded sha256 tx hash
+func ValidateTxHash(hash string) error {
+	if !txHashRegex.MatchString(hash) {
+		return fmt.Errorf("invalid tx hash %q", hash)
+	}
+
+	return nil
+}
+
+func (s *Phase2Submission) Operator() string {
+	return s.ValOprAddr
+}
+
+func (s *Phase2Submission) TxHashes() []string {
+	hashes := append(append([]string{}, s.Transfers...), s.Allowance...)
+
+	for _, tx := range s.EditContractTxs {
+		hashes = append(hashes, tx.TxHash)
+	}
+
+	return hashes
+}
+
+func (s *Phase2Submission) Validate() []string {
+	var errs []string
+
+	errs = appendErr(errs, address.ValidateBech32(s.ValOprAddr, address.ValOperPrefix))
+	errs = appendErr(errs, address.ValidateBech32(s.ContractAddress, address.AccountPrefix))
+
+	if s.CodeID == 0 {
+		errs = append(errs, "codeId is missing")
+	}
+
+	if len(s.Transfers) == 0 {
+		errs = append(errs, "transfers are missing")
+	}
+
+	for _, tx := range s.EditContractTxs {
+		if strings.TrimSpace(tx.Description) == "" {
+			errs = append(errs, fmt.Sprintf("edit contract tx %s has no description", tx.TxHash))
+		}
+	}
+
+	return validateHashes(errs, s.TxHashes())
+}
+
+func (s *Phase3Submission) Operator() string {
+	return s.ValOprAddr
+}
+
+func (s *Phase3Submission) TxHashes() []string {
+	return nil
+}
+
+func (s *Phase3Submission) Validate() []string {
+	var errs []string
+
+	errs = appendErr(errs, address.ValidateBech32(s.ValOprAddr, address.ValOperPrefix))
+
+	if s.TweetURL != "" {
+		if u, err := url.Parse(s.TweetURL); err != nil || u.Host == "" || !strings.HasPrefix(u.Scheme, "http") {
+			errs = append(errs, fmt.Sprintf("invalid tweet url %q", s.TweetURL))
+		}
+	}
+
+	return errs
+}
+
+func (s *Phase4Submission) Operator() string {
+	return s.ValOprAddr
+}
+
+func (s *Phase4Submission) TxHashes() []string {
+	return append(append([]string{}, s.Approve...), s.Refund...)
+}
+
+func (s *Phase4Submission) Validate() []string {
+	var errs []string
+
+	errs = appendErr(errs, address.ValidateBech32(s.ValOprAddr, address.ValOperPrefix))
+	errs = appendErr(errs, address.ValidateBech32(s.ContractAddress, address.AccountPrefix))
+
+	if s.CodeID == 0 {
+		errs = append(errs, "codeId is missing")
+	}
+
+	if len(s.Approve) == 0 && len(s.Refund) == 0 {
+		errs = append(errs, "approve and refund txs are missing")
+	}
+
+	return validateHashes(errs, s.TxHashes())
+}
+
+func (s *Phase6Submission) Operator() string {
+	return s.ValOprAddr
+}
+
+func (s *Phase6Submission) TxHashes() []string {
+	return s.Txs
+}
+
+func (s *Phase6Submission) Validate() []string {
+	var errs []string
+
+	errs = appendErr(errs, address.ValidateBech32(s.ValOprAddr, address.ValOperPrefix))
+
+	if len(s.Targets) == 0 {
+		errs = append(errs, "targets are missing")
+	}
+
+	for _, target := range s.Targets {
+		errs = appendErr(errs, address.ValidateBech32(target, address.ValOperPrefix))
+
+		if target == s.ValOprAddr {
+			errs = append(errs, "the attacker can't be a target")
+		}
+	}
+
+	byHeight := s.StartHeight > 0 || s.EndHeight > 0
+	byTime := s.StartTime != "" || s.EndTime != ""
+
+	switch {
+	case byHeight && byTime:
+		errs = append(errs, "the attack window is either heights or times, not both")
+	case byHeight:
+		if s.StartHeight < 1 || s.EndHeight < s.StartHeight {
+			errs = append(errs, fmt.Sprintf("invalid attack window %d - %d", s.StartHeight, s.EndHeight))
+		}
+	case byTime:
+		start, err := time.Parse(time.RFC3339, s.StartTime)
+		errs = appendErr(errs, err)
+
+		end, err := time.Parse(time.RFC3339, s.EndTime)
+		errs = appendErr(errs, err)
+
+		if !end.After(start) {
+			errs = append(errs, fmt.Sprintf("invalid attack window %s - %s", s.StartTime, s.EndTime))
+		}
+	default:
+		errs = append(errs, "the attack window is missing, set startHeight and endHeight or startTime and endTime")
+	}
+
+	if len(s.Txs) == 0 {
+		errs = append(errs, "txHashes are missing")
+	}
+
+	if strings.TrimSpace(s.Description) == "" {
+		errs = append(errs, "description is missing")
+	}
+
+	return validateHashes(errs, s.Txs)
+}
+
+func validateHashes(errs []string, hashes []string) []string {
+	seen := make(map[string]bool)
+
+	for _, hash := range hashes {
+		errs = appendErr(errs, ValidateTxHash(hash))
+
+		if seen[strings.ToUpper(hash)] {
+			errs = append(errs, fmt.Sprintf("tx hash %s is listed more than once", hash))
+		}
+
+		seen[strings.ToUpper(hash)] = true
+	}
+
+	return errs
+}
+
+func appendErr(errs []string, err error) []string {
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	return errs
+}
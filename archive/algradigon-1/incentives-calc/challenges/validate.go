@@ -0,0 +1,151 @@
+package challenges
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileReport holds the validation errors of a single submission file
+type FileReport struct {
+	File       string     `json:"file"`
+	Submission Submission `json:"submission,omitempty"`
+	Errors     []string   `json:"errors"`
+}
+
+// Schemas - Returns the names of the challenges having a submission schema
+func Schemas() []string {
+	var names []string
+
+	for name := range schemas {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadSubmission - Decodes a submission file using the schema of the challenge,
+// unknown fields are rejected to catch typos in field names
+func LoadSubmission(challenge, path string) (Submission, error) {
+	newSubmission, ok := schemas[challenge]
+	if !ok {
+		return nil, fmt.Errorf("unknown challenge %q, expected one of %s", challenge, strings.Join(Schemas(), ", "))
+	}
+
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	submission := newSubmission()
+
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(submission); err != nil {
+		return nil, err
+	}
+
+	return submission, nil
+}
+
+// ValidateDir - Validates all the submission files of a challenge directory. Tx hashes
+// and operators used by more than one submission are reported in every file using them
+func ValidateDir(challenge, dir string) ([]FileReport, error) {
+	if _, ok := schemas[challenge]; !ok {
+		return nil, fmt.Errorf("unknown challenge %q, expected one of %s", challenge, strings.Join(Schemas(), ", "))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []FileReport
+
+	hashFiles := make(map[string][]string)
+	operatorFiles := make(map[string][]string)
+
+	for _, file := range files {
+		if filepath.Base(file) == "sample.json" {
+			continue
+		}
+
+		report := FileReport{File: filepath.Base(file)}
+
+		submission, err := LoadSubmission(challenge, file)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			reports = append(reports, report)
+			continue
+		}
+
+		report.Submission = submission
+		report.Errors = submission.Validate()
+
+		for _, hash := range uniqueHashes(submission.TxHashes()) {
+			hashFiles[hash] = append(hashFiles[hash], report.File)
+		}
+
+		operatorFiles[submission.Operator()] = append(operatorFiles[submission.Operator()], report.File)
+
+		reports = append(reports, report)
+	}
+
+	for i, report := range reports {
+		if report.Submission == nil {
+			continue
+		}
+
+		for _, hash := range report.Submission.TxHashes() {
+			if others := except(hashFiles[strings.ToUpper(hash)], report.File); len(others) > 0 {
+				reports[i].Errors = append(reports[i].Errors,
+					fmt.Sprintf("tx hash %s is also used by %s", hash, strings.Join(others, ", ")))
+			}
+		}
+
+		if others := except(operatorFiles[report.Submission.Operator()], report.File); len(others) > 0 && !multipleSubmissions[challenge] {
+			reports[i].Errors = append(reports[i].Errors,
+				fmt.Sprintf("operator %s also submitted %s", report.Submission.Operator(), strings.Join(others, ", ")))
+		}
+	}
+
+	return reports, nil
+}
+
+// uniqueHashes - Upper cases the hashes, a hash repeated in the same file is counted once
+func uniqueHashes(hashes []string) []string {
+	var unique []string
+
+	seen := make(map[string]bool)
+
+	for _, hash := range hashes {
+		hash = strings.ToUpper(hash)
+
+		if !seen[hash] {
+			unique = append(unique, hash)
+		}
+
+		seen[hash] = true
+	}
+
+	return unique
+}
+
+func except(files []string, file string) []string {
+	var others []string
+
+	for _, f := range files {
+		if f != file {
+			others = append(others, f)
+		}
+	}
+
+	return others
+}
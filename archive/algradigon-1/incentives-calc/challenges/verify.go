@@ -0,0 +1,522 @@
+package challenges
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/contracts"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// Phase2Rules - Points of the ERC20 challenge, see kontraua/PLAN.md. The transfer points need
+// transfers to at least MinTransfers distinct validators, the rank bonus goes to the first validators
+// done with the deployment, source, transfers and edit contract tasks by height
+type Phase2Rules struct {
+	Deployment   int64
+	SourceBuild  int64
+	Transfers    int64
+	MinTransfers int
+	EditContract int64
+	Allowance    int64
+	RankBonus    []int64
+}
+
+// DefaultPhase2Rules - The phase-2 points of kontraua/PLAN.md, its rank bonus tiers are the ones of
+// phase-4
+var DefaultPhase2Rules = Phase2Rules{
+	Deployment: 100, SourceBuild: 50, Transfers: 50, MinTransfers: 5, EditContract: 50, Allowance: 100,
+	RankBonus: contracts.DefaultEscrowRules.RankBonus,
+}
+
+// TxCheck is the outcome of checking a single tx of a submission
+type TxCheck struct {
+	Hash   string   `json:"hash"`
+	Kind   string   `json:"kind"`
+	Height int64    `json:"height"`
+	Signer string   `json:"signer"`
+	Errors []string `json:"errors"`
+}
+
+func (c TxCheck) Valid() bool {
+	return len(c.Errors) == 0
+}
+
+type Award struct {
+	Category string `json:"category"`
+	Points   int64  `json:"points"`
+}
+
+// Verification holds the checked txs and the points earned by a submission
+type Verification struct {
+	File     string    `json:"file"`
+	Operator string    `json:"operator"`
+	Checks   []TxCheck `json:"checks"`
+	Awards   []Award   `json:"awards"`
+	Total    int64     `json:"total"`
+	Errors   []string  `json:"errors"`
+
+	//completed is the height the phase-2 tasks were all done at, ranked by RankPhase2
+	completed int64
+	//escrow is the phase-4 score of the submitted escrow, ranked by RankEscrows
+	escrow *contracts.EscrowScore
+}
+
+func (v *Verification) award(category string, points int64) {
+	v.Awards = append(v.Awards, Award{Category: category, Points: points})
+	v.Total += points
+}
+
+// Verifier resolves the tx hashes of submissions against indexed transactions, the contracts
+// index gives the uploaded codes and the validators behind the accounts. Txs outside of the start
+// and end heights are rejected when the heights are set. The escrow challenge is scored by the same
+// rules as contracts escrow
+type Verifier struct {
+	txs         db.TxSource
+	index       *contracts.Index
+	StartHeight int64
+	EndHeight   int64
+	Phase2      Phase2Rules
+	Phase4      contracts.EscrowRules
+}
+
+func NewVerifier(txs db.TxSource, index *contracts.Index, startHeight, endHeight int64) *Verifier {
+	return &Verifier{
+		txs:         txs,
+		index:       index,
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+		Phase2:      DefaultPhase2Rules,
+		Phase4:      contracts.DefaultEscrowRules,
+	}
+}
+
+// txExpect describes the message a submitted tx must contain
+type txExpect struct {
+	msgType   string
+	contract  string
+	actions   []string
+	codeID    string
+	signer    string
+	notSigner string
+}
+
+// Verify - Checks the txs of a submission and calculates its points
+func (v *Verifier) Verify(file string, submission Submission) Verification {
+	result := Verification{File: file, Operator: submission.Operator()}
+
+	operatorAcc, err := address.ValOperToAccount(submission.Operator())
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	switch s := submission.(type) {
+	case *Phase2Submission:
+		v.verifyPhase2(&result, s, operatorAcc)
+	case *Phase3Submission:
+		result.Errors = append(result.Errors, "phase-3 submissions are a proposal vote and a tweet, not tx proofs")
+	case *Phase4Submission:
+		v.verifyPhase4(&result, s, operatorAcc)
+	default:
+		result.Errors = append(result.Errors, "submission has no tx proofs to verify")
+	}
+
+	return result
+}
+
+func (v *Verifier) verifyPhase2(result *Verification, s *Phase2Submission, operatorAcc string) {
+	//done holds the height every ranked task was done at
+	var done []int64
+
+	deployment := v.checkInstantiate(s.ContractAddress, s.CodeID, operatorAcc)
+	result.Checks = append(result.Checks, deployment)
+
+	if deployment.Valid() {
+		result.award("deployment", v.Phase2.Deployment)
+		done = append(done, deployment.Height)
+	}
+
+	source := v.checkStoreCode(s.CodeID, operatorAcc)
+	result.Checks = append(result.Checks, source)
+
+	if source.Valid() {
+		result.award("source url and build tag", v.Phase2.SourceBuild)
+		done = append(done, source.Height)
+	}
+
+	//The transfer and allowance rules are the ones of contracts erc20, applied to the submitted txs
+	var transfers []TxCheck
+
+	for _, hash := range s.Transfers {
+		transfers = append(transfers, v.checkTx(hash, "transfer", txExpect{
+			msgType: db.MSG_EXECUTE_CONTRACT, contract: s.ContractAddress,
+			actions: []string{"transfer"}, signer: operatorAcc,
+		}))
+	}
+
+	matches, skipped := v.index.MatchTransfers(operatorAcc, v.executions(s.ContractAddress, transfers))
+
+	for _, check := range transfers {
+		if reason, ok := skipped[check.Hash]; ok {
+			check.Errors = append(check.Errors, reason)
+		}
+
+		result.Checks = append(result.Checks, check)
+	}
+
+	if len(matches) >= v.Phase2.MinTransfers {
+		result.award("transfers", v.Phase2.Transfers)
+
+		//The matches are ordered by height, the task is done with the last required one
+		height := int64(0)
+		if v.Phase2.MinTransfers > 0 {
+			height = matches[v.Phase2.MinTransfers-1].Height
+		}
+
+		done = append(done, height)
+	}
+
+	edited := int64(0)
+
+	for _, tx := range s.EditContractTxs {
+		check := v.checkTx(tx.TxHash, "edit contract", txExpect{
+			msgType: db.MSG_EXECUTE_CONTRACT, contract: s.ContractAddress, signer: operatorAcc,
+		})
+		result.Checks = append(result.Checks, check)
+
+		if check.Valid() && (edited == 0 || check.Height < edited) {
+			edited = check.Height
+		}
+	}
+
+	if edited > 0 {
+		result.award("edit contract", v.Phase2.EditContract)
+		done = append(done, edited)
+	}
+
+	//The four ranked tasks are done at the height of the last one
+	if len(done) == 4 {
+		for _, height := range done {
+			if height > result.completed {
+				result.completed = height
+			}
+		}
+	}
+
+	//Allowance bonus needs an approve by the operator followed by a transferFrom of its spender
+	var allowance []TxCheck
+
+	for _, hash := range s.Allowance {
+		tx, _ := v.txs.QueryTxByHash(hash)
+
+		expect := txExpect{
+			msgType: db.MSG_EXECUTE_CONTRACT, contract: s.ContractAddress,
+			actions: []string{"approve"}, signer: operatorAcc,
+		}
+		kind := "approve"
+
+		if tx != nil && hasAction(tx, "transferfrom") {
+			expect.actions = []string{"transferfrom"}
+			expect.signer, expect.notSigner = "", operatorAcc
+			kind = "transferFrom"
+		}
+
+		check := v.checkTx(hash, kind, expect)
+		result.Checks = append(result.Checks, check)
+		allowance = append(allowance, check)
+	}
+
+	if contracts.FindAllowance(operatorAcc, v.executions(s.ContractAddress, allowance)) != nil {
+		result.award("allowance", v.Phase2.Allowance)
+	}
+}
+
+// executions - Returns the executions of the contract in the valid checked txs ordered by height
+func (v *Verifier) executions(contract string, checks []TxCheck) []contracts.Execution {
+	var executions []contracts.Execution
+
+	for _, check := range checks {
+		if !check.Valid() {
+			continue
+		}
+
+		tx, _ := v.txs.QueryTxByHash(check.Hash)
+		if tx == nil {
+			continue
+		}
+
+		for _, e := range contracts.Executions(*tx) {
+			if e.Contract == contract {
+				executions = append(executions, e)
+			}
+		}
+	}
+
+	sort.SliceStable(executions, func(i, j int) bool { return executions[i].Height < executions[j].Height })
+
+	return executions
+}
+
+// verifyPhase4 - Checks the submitted approve and refund txs against the lifecycle of the escrow and
+// scores it on the valid ones, the rank bonus is awarded by RankEscrows
+func (v *Verifier) verifyPhase4(result *Verification, s *Phase4Submission, operatorAcc string) {
+	deployment := v.checkInstantiate(s.ContractAddress, s.CodeID, operatorAcc)
+	result.Checks = append(result.Checks, deployment)
+
+	lifecycle, ok := v.index.Escrow(s.ContractAddress)
+	if !ok {
+		result.Errors = append(result.Errors, fmt.Sprintf("contract %s is not an escrow", s.ContractAddress))
+		return
+	}
+
+	valid := []string{}
+
+	for _, action := range []string{"approve", "refund"} {
+		hashes := s.Approve
+		if action == "refund" {
+			hashes = s.Refund
+		}
+
+		for _, hash := range hashes {
+			check := v.checkTx(hash, action,
+				txExpect{msgType: db.MSG_EXECUTE_CONTRACT, contract: s.ContractAddress, actions: []string{action}})
+
+			//The lifecycle checks the escrow state, the approve by the arbiter and the released funds
+			check.Errors = append(check.Errors, lifecycle.CheckEvent(hash, action)...)
+			result.Checks = append(result.Checks, check)
+
+			if check.Valid() {
+				valid = append(valid, hash)
+			}
+		}
+	}
+
+	if !deployment.Valid() {
+		return
+	}
+
+	score := v.Phase4.Score(lifecycle, valid)
+
+	result.escrow = &score
+
+	if score.Deployment > 0 {
+		result.award("deployment", score.Deployment)
+	}
+
+	if score.Execution > 0 {
+		result.award("execution", score.Execution)
+	}
+}
+
+// RankPhase2 - Awards the rank bonus of the ERC20 challenge to the verified phase-2 submissions,
+// ranked by the height their operator was done with the ranked tasks. The allowance is a bonus of
+// its own and is not ranked
+func (v *Verifier) RankPhase2(results []Verification) {
+	best := make(map[string]int)
+
+	var ranked []int
+
+	for i, result := range results {
+		if result.completed == 0 {
+			continue
+		}
+
+		j, ok := best[result.Operator]
+		if !ok {
+			best[result.Operator] = len(ranked)
+			ranked = append(ranked, i)
+
+			continue
+		}
+
+		if result.completed < results[ranked[j]].completed {
+			ranked[j] = i
+		}
+	}
+
+	sort.SliceStable(ranked, func(a, b int) bool { return results[ranked[a]].completed < results[ranked[b]].completed })
+
+	for rank, i := range ranked {
+		if rank < len(v.Phase2.RankBonus) && v.Phase2.RankBonus[rank] > 0 {
+			results[i].award(fmt.Sprintf("rank bonus #%d", rank+1), v.Phase2.RankBonus[rank])
+		}
+	}
+}
+
+// RankEscrows - Awards the rank bonus of the escrow challenge to the verified phase-4 submissions,
+// ranked by completion height over all of them
+func (v *Verifier) RankEscrows(results []Verification) {
+	var scores []contracts.EscrowScore
+
+	for _, result := range results {
+		if result.escrow != nil {
+			scores = append(scores, *result.escrow)
+		}
+	}
+
+	for _, score := range v.Phase4.Rank(scores) {
+		if score.Bonus == 0 {
+			continue
+		}
+
+		for i := range results {
+			if e := results[i].escrow; e != nil && e.Contract == score.Contract && e.Completed == score.Completed {
+				results[i].award(fmt.Sprintf("rank bonus #%d", score.Rank), score.Bonus)
+				break
+			}
+		}
+	}
+}
+
+// checkInstantiate - Checks that the contract was instantiated from the code id by the operator
+func (v *Verifier) checkInstantiate(contract string, codeID CodeID, operatorAcc string) TxCheck {
+	check := TxCheck{Kind: "instantiate"}
+
+	tx, err := v.txs.QueryInstantiateTx(contract)
+	if err != nil {
+		check.Errors = append(check.Errors, fmt.Sprintf("lookup failed: %v", err))
+		return check
+	}
+
+	if tx == nil {
+		check.Errors = append(check.Errors, fmt.Sprintf("no instantiate tx found for contract %s", contract))
+		return check
+	}
+
+	check.Hash = tx.Hash
+
+	v.checkResult(&check, tx)
+
+	v.checkMsg(&check, tx, txExpect{
+		msgType: db.MSG_INSTANTIATE_CONTRACT, codeID: strconv.FormatUint(uint64(codeID), 10), signer: operatorAcc,
+	})
+
+	return check
+}
+
+// checkStoreCode - Checks that the code was uploaded by the operator with a source url and a builder
+// image with its tag
+func (v *Verifier) checkStoreCode(codeID CodeID, operatorAcc string) TxCheck {
+	id := strconv.FormatUint(uint64(codeID), 10)
+
+	for _, code := range v.index.Codes {
+		if code.CodeID != id {
+			continue
+		}
+
+		check := v.checkTx(code.TxHash, "store code", txExpect{msgType: db.MSG_STORE_CODE, signer: operatorAcc})
+
+		check.Errors = append(check.Errors, code.Issues...)
+
+		return check
+	}
+
+	return TxCheck{Kind: "store code", Errors: []string{fmt.Sprintf("no store code tx found for code id %s", id)}}
+}
+
+// checkTx - Resolves a tx hash and checks it against the expected message
+func (v *Verifier) checkTx(hash, kind string, expect txExpect) TxCheck {
+	check := TxCheck{Hash: hash, Kind: kind}
+
+	tx, err := v.txs.QueryTxByHash(hash)
+	if err != nil {
+		check.Errors = append(check.Errors, fmt.Sprintf("lookup failed: %v", err))
+		return check
+	}
+
+	if tx == nil {
+		check.Errors = append(check.Errors, "tx not found")
+		return check
+	}
+
+	v.checkResult(&check, tx)
+	v.checkMsg(&check, tx, expect)
+
+	return check
+}
+
+// checkResult - Checks that the tx succeeded within the challenge window
+func (v *Verifier) checkResult(check *TxCheck, tx *db.Transaction) {
+	check.Height = tx.Height
+
+	if tx.Code != 0 {
+		check.Errors = append(check.Errors, fmt.Sprintf("tx failed with code %d", tx.Code))
+	}
+
+	if v.StartHeight > 0 && tx.Height < v.StartHeight {
+		check.Errors = append(check.Errors, fmt.Sprintf("tx height %d is before the challenge start %d", tx.Height, v.StartHeight))
+	}
+
+	if v.EndHeight > 0 && tx.Height > v.EndHeight {
+		check.Errors = append(check.Errors, fmt.Sprintf("tx height %d is after the challenge end %d", tx.Height, v.EndHeight))
+	}
+}
+
+// checkMsg - Finds the expected message in the tx and checks its signer
+func (v *Verifier) checkMsg(check *TxCheck, tx *db.Transaction, expect txExpect) {
+	for i, msg := range tx.Msgs {
+		if msg.Type != expect.msgType || (expect.contract != "" && msg.Value.Contract != expect.contract) {
+			continue
+		}
+
+		if len(expect.actions) > 0 && !contains(expect.actions, msg.Value.ExecuteAction()) {
+			continue
+		}
+
+		if expect.codeID != "" && msg.Value.CodeID != expect.codeID {
+			continue
+		}
+
+		check.Signer = msg.Value.Sender
+		if check.Signer == "" && i < len(tx.Signers) {
+			check.Signer = tx.Signers[i]
+		}
+
+		if expect.signer != "" && check.Signer != expect.signer {
+			check.Errors = append(check.Errors, fmt.Sprintf("signed by %s, expected %s", check.Signer, expect.signer))
+		}
+
+		if expect.notSigner != "" && check.Signer == expect.notSigner {
+			check.Errors = append(check.Errors, fmt.Sprintf("signed by %s, expected a different account", check.Signer))
+		}
+
+		return
+	}
+
+	desc := expect.msgType
+	if len(expect.actions) > 0 {
+		desc = fmt.Sprintf("%s %v", desc, expect.actions)
+	}
+
+	if expect.contract != "" {
+		desc = fmt.Sprintf("%s on contract %s", desc, expect.contract)
+	}
+
+	if expect.codeID != "" {
+		desc = fmt.Sprintf("%s of code id %s", desc, expect.codeID)
+	}
+
+	check.Errors = append(check.Errors, "no matching message: "+desc)
+}
+
+func hasAction(tx *db.Transaction, action string) bool {
+	for _, msg := range tx.Msgs {
+		if msg.Type == db.MSG_EXECUTE_CONTRACT && msg.Value.ExecuteAction() == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,39 @@
+package challenges
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRankPhase2(t *testing.T) {
+	results := []Verification{
+		{File: "a.json", Operator: "xrn:valoper1a", completed: 30},
+		{File: "b.json", Operator: "xrn:valoper1b", completed: 10},
+		{File: "c.json", Operator: "xrn:valoper1c"},
+		{File: "a2.json", Operator: "xrn:valoper1a", completed: 20},
+		{File: "d.json", Operator: "xrn:valoper1d", completed: 40},
+	}
+
+	v := NewVerifier(nil, nil, 0, 0)
+	v.Phase2.RankBonus = []int64{100, 50}
+
+	v.RankPhase2(results)
+
+	//A validator is ranked once on its first completed submission, c did not complete the tasks
+	got := make(map[string][]Award)
+	for _, result := range results {
+		got[result.File] = result.Awards
+	}
+
+	want := map[string][]Award{
+		"a.json":  nil,
+		"b.json":  {{Category: "rank bonus #1", Points: 100}},
+		"c.json":  nil,
+		"a2.json": {{Category: "rank bonus #2", Points: 50}},
+		"d.json":  nil,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("awards = %+v, want %+v", got, want)
+	}
+}
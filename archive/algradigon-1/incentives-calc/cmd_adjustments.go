@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+const adjustmentsUsage = `Usage:
+  adjustments validate <adjustments.json>`
+
+// runAdjustments - Validates an adjustments ledger before it is configured as adjustments_file
+func runAdjustments(args []string) {
+	if len(args) != 2 || args[0] != "validate" {
+		log.Fatal(adjustmentsUsage)
+	}
+
+	ledger, err := src.LoadAdjustments(args[1])
+	if err != nil {
+		log.Fatalf("Invalid adjustments ledger: %v", err)
+	}
+
+	var total float64
+
+	for _, adj := range ledger {
+		fmt.Println(adj.Operator, adj)
+		total += adj.Points
+	}
+
+	fmt.Printf("OK %d adjustments, %g points\n", len(ledger), total)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+const anomaliesUsage = `Usage:
+  anomalies [--json] [--spike <factor>] [--stall <duration>] [--baseline <blocks>] [--top <n>] --start <block> --end <block>`
+
+// runAnomalies - Lists the block time spikes, stalls and multi-round heights with the validators
+// which dropped out, the headers of the range have to be ingested with headers ingest
+func runAnomalies(args []string) {
+	fs := flag.NewFlagSet("anomalies", flag.ExitOnError)
+	start := fs.Int64("start", -1, "start flag: Start Block Number")
+	end := fs.Int64("end", -1, "end flag: End Block Number")
+	spike := fs.Float64("spike", 3, "spike flag: Block time over the median block time from which a height is a spike")
+	stall := fs.Duration("stall", time.Minute, "stall flag: Block time from which a height is a stall")
+	baseline := fs.Int64("baseline", 20, "baseline flag: Blocks before a window whose regular signers are checked in it")
+	top := fs.Int("top", 10, "top flag: Dropped validators listed per window, 0 for all")
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args)
+
+	if *start < 0 || *end < 1 || *spike <= 1 || *baseline < 1 {
+		log.Fatal(anomaliesUsage)
+	}
+
+	cfg := db.ReadConfig()
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	options := src.AnomalyOptions{SpikeFactor: *spike, Stall: *stall, Baseline: *baseline}
+
+	report, err := src.New(session, cfg).DetectAnomalies(*start, *end, options)
+	if err != nil {
+		log.Fatalf("Error while detecting anomalies: %v", err)
+	}
+
+	if report.MissingHeights > 0 {
+		fmt.Fprintf(os.Stderr, "%d heights have no ingested header and are skipped\n", report.MissingHeights)
+	}
+
+	if *asJSON {
+		printJSON(report)
+		return
+	}
+
+	fmt.Printf("%d heights, median block time %.2fs, %d anomaly windows\n",
+		report.Heights, report.MedianIntervalSeconds, len(report.Windows))
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Heights \t Start Time \t Kinds \t Max Block Time \t Max Round \t Dropped Validators")
+
+	for _, window := range report.Windows {
+		var dropped []string
+
+		for i, d := range window.Dropped {
+			if *top > 0 && i == *top {
+				dropped = append(dropped, fmt.Sprintf("+%d more", len(window.Dropped)-i))
+				break
+			}
+
+			name := d.Moniker
+			if name == "" {
+				name = d.Address
+			}
+
+			dropped = append(dropped, fmt.Sprintf("%s (%d/%d)", name, d.Missed, d.Commits))
+		}
+
+		fmt.Fprintln(w, " "+strconv.FormatInt(window.StartHeight, 10)+" - "+strconv.FormatInt(window.EndHeight, 10)+
+			"\t "+window.StartTime.UTC().Format("2006-01-02 15:04:05")+"\t "+strings.Join(window.Kinds, ", ")+
+			"\t "+fmt.Sprintf("%.1fs", window.MaxIntervalSeconds)+"\t "+strconv.FormatInt(window.MaxRound, 10)+
+			"\t "+strings.Join(dropped, ", "))
+	}
+
+	w.Flush()
+}
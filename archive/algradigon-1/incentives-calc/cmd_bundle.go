@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/regen-friends/testnets/util/uptime/bundle"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+const bundleUsage = `Usage:
+  bundle create --start <block> --end <block> [--out <bundle.json>] [--key <key file>]
+  bundle verify [--json] [--pubkey <hex public key>] <bundle.json>
+  bundle keygen <key file>`
+
+// runBundle - Creates, signs and verifies reproducible result bundles
+func runBundle(args []string) {
+	if len(args) < 1 {
+		log.Fatal(bundleUsage)
+	}
+
+	fs := flag.NewFlagSet("bundle "+args[0], flag.ExitOnError)
+	start := fs.Int64("start", -1, "start flag: Start Block Number")
+	end := fs.Int64("end", -1, "end flag: End Block Number")
+	out := fs.String("out", "bundle.json", "out flag: Bundle file to write")
+	keyFile := fs.String("key", "", "key flag: Sign the bundle with the ed25519 private key of this file")
+	publicKey := fs.String("pubkey", "", "pubkey flag: Require a signature by this hex encoded public key")
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args[1:])
+
+	switch {
+	case args[0] == "create" && *start >= 0 && *end > 0:
+		runBundleCreate(*start, *end, *out, *keyFile)
+	case args[0] == "verify" && fs.NArg() == 1:
+		os.Exit(runBundleVerify(fs.Arg(0), *publicKey, *asJSON))
+	case args[0] == "keygen" && fs.NArg() == 1:
+		runBundleKeygen(fs.Arg(0))
+	default:
+		log.Fatal(bundleUsage)
+	}
+}
+
+func runBundleCreate(start, end int64, out, keyFile string) {
+	cfg := db.ReadConfig()
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	b, err := bundle.Create(session, cfg, start, end)
+	if err != nil {
+		log.Fatalf("Error while creating bundle: %v", err)
+	}
+
+	if keyFile != "" {
+		if err := b.Sign(readPrivateKey(keyFile)); err != nil {
+			log.Fatalf("Error while signing bundle: %v", err)
+		}
+	}
+
+	if err := b.Save(out); err != nil {
+		log.Fatalf("Error while writing bundle: %v", err)
+	}
+
+	fmt.Printf("Bundle of blocks %d - %d written to %s\n", start, end, out)
+	fmt.Println("Data hash  ", b.DataHash)
+	fmt.Println("Output hash", b.OutputHash)
+
+	if b.Signature != nil {
+		fmt.Println("Signed by  ", b.Signature.PublicKey)
+	}
+}
+
+func runBundleVerify(path, publicKey string, asJSON bool) int {
+	b, err := bundle.Load(path)
+	if err != nil {
+		log.Fatalf("Error while reading bundle: %v", err)
+	}
+
+	cfg := db.ReadConfig()
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	report := bundle.Verify(b, session, cfg, publicKey)
+
+	if asJSON {
+		printJSON(report)
+	} else {
+		for _, msg := range report.Warnings {
+			fmt.Println("WARN", msg)
+		}
+
+		for _, msg := range report.Errors {
+			fmt.Println("FAIL", msg)
+		}
+
+		for _, msg := range report.Mismatches {
+			fmt.Println("MISMATCH", msg)
+		}
+
+		if report.Passed() {
+			fmt.Printf("OK %d validators reproduced, signed: %v\n", len(b.Results.Validators), report.Signed)
+		}
+	}
+
+	if !report.Passed() {
+		return 1
+	}
+
+	return 0
+}
+
+func runBundleKeygen(path string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatalf("Error while generating key: %v", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(hex.EncodeToString(privateKey)+"\n"), 0600); err != nil {
+		log.Fatalf("Error while writing key: %v", err)
+	}
+
+	fmt.Println("Public key", hex.EncodeToString(publicKey))
+}
+
+// readPrivateKey - Reads a hex encoded ed25519 private key written by bundle keygen
+func readPrivateKey(path string) ed25519.PrivateKey {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Error while reading key: %v", err)
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		log.Fatalf("%s is not a hex encoded ed25519 private key", path)
+	}
+
+	return ed25519.PrivateKey(key)
+}
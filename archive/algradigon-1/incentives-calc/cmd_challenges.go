@@ -0,0 +1,510 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/challenges"
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/contracts"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+const challengesUsage = `Usage:
+  challenges validate [--json] [--challenge <phase-2|phase-3|phase-4|phase-6>] <submissions dir>
+  challenges verify [--json] [--challenge <phase-2|phase-4>] [--txs <tx dump.json>]
+                    [--start-height <height>] [--end-height <height>] [--rank-bonus <spec>] <submissions dir>
+  challenges treasure-hunt [--json] <treasure hunt dir>
+  challenges resolve [--json] [--challenge <schema|treasure-hunt>] [--only <challenge>] [--txs <tx dump.json>]
+                     [--cutoff <RFC3339 time>] [--first-only] [--winners <n>] [--rank-last] <submissions dir>
+  challenges attack [--json] [--baseline <blocks>] [--min-baseline <ratio>] [--min-drop <ratio>]
+                    [--tx-margin <blocks>] [--points <points>] <claims dir>`
+
+func runChallenges(args []string) {
+	if len(args) < 1 {
+		log.Fatal(challengesUsage)
+	}
+
+	switch args[0] {
+	case "validate":
+		os.Exit(runChallengesValidate(args[1:]))
+	case "verify":
+		runChallengesVerify(args[1:])
+	case "treasure-hunt":
+		runTreasureHunt(args[1:])
+	case "resolve":
+		runChallengesResolve(args[1:])
+	case "attack":
+		runChallengesAttack(args[1:])
+	default:
+		log.Fatal(challengesUsage)
+	}
+}
+
+// runChallengesValidate - Validates the submissions of a challenge directory,
+// returns exit code 1 if any submission has errors
+func runChallengesValidate(args []string) int {
+	fs := flag.NewFlagSet("challenges validate", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	challenge := fs.String("challenge", "", "challenge flag: Submission schema, defaults to the directory name")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		log.Fatal(challengesUsage)
+	}
+
+	dir := fs.Arg(0)
+
+	if *challenge == "" {
+		*challenge = filepath.Base(filepath.Clean(dir))
+	}
+
+	reports, err := challenges.ValidateDir(*challenge, dir)
+	if err != nil {
+		log.Fatalf("Error while validating submissions: %v", err)
+	}
+
+	code := 0
+
+	for _, report := range reports {
+		if len(report.Errors) > 0 {
+			code = 1
+		}
+	}
+
+	if *asJSON {
+		printJSON(reports)
+		return code
+	}
+
+	for _, report := range reports {
+		if len(report.Errors) == 0 {
+			fmt.Printf("[OK]    %s\n", report.File)
+			continue
+		}
+
+		fmt.Printf("[ERROR] %s\n", report.File)
+
+		for _, msg := range report.Errors {
+			fmt.Printf("        %s\n", msg)
+		}
+	}
+
+	fmt.Printf("\n%d submissions validated\n", len(reports))
+
+	return code
+}
+
+// runChallengesVerify - Verifies the tx proofs of the submissions against the indexed
+// transactions, or a local tx dump, and prints the points of every submission
+func runChallengesVerify(args []string) {
+	fs := flag.NewFlagSet("challenges verify", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	challenge := fs.String("challenge", "", "challenge flag: Submission schema, defaults to the directory name")
+	txDump := fs.String("txs", "", "txs flag: JSON tx dump used instead of the database")
+	startHeight := fs.Int64("start-height", 0, "start-height flag: First height of the challenge window")
+	endHeight := fs.Int64("end-height", 0, "end-height flag: Last height of the challenge window")
+	rankBonus := fs.String("rank-bonus", contracts.DEFAULT_RANK_BONUS,
+		"rank-bonus flag: Phase-2 and phase-4 rank bonus as points x validators tiers, ex: 100x5,50x5,25x10")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		log.Fatal(challengesUsage)
+	}
+
+	dir := fs.Arg(0)
+
+	if *challenge == "" {
+		*challenge = filepath.Base(filepath.Clean(dir))
+	}
+
+	//The other challenges have no tx proofs, phase-3 is scored from the votes and upgrades
+	if *challenge != "phase-2" && *challenge != "phase-4" {
+		log.Fatalf("Challenge %s can not be verified, only phase-2 and phase-4 submissions have tx proofs", *challenge)
+	}
+
+	//The tx proofs and the contract index are read over the same source
+	txs, session := openTxSource(*txDump)
+	if session != nil {
+		defer session.Terminate()
+	}
+
+	reports, err := challenges.ValidateDir(*challenge, dir)
+	if err != nil {
+		log.Fatalf("Error while reading submissions: %v", err)
+	}
+
+	verifier := challenges.NewVerifier(txs, loadContractIndex(txs, session), *startHeight, *endHeight)
+	verifier.Phase4 = escrowRules(*rankBonus)
+	verifier.Phase2.RankBonus = verifier.Phase4.RankBonus
+
+	var results []challenges.Verification
+
+	for _, report := range reports {
+		if report.Submission == nil {
+			results = append(results, challenges.Verification{File: report.File, Errors: report.Errors})
+			continue
+		}
+
+		results = append(results, verifier.Verify(report.File, report.Submission))
+	}
+
+	verifier.RankPhase2(results)
+	verifier.RankEscrows(results)
+
+	if *asJSON {
+		printJSON(results)
+		return
+	}
+
+	for _, result := range results {
+		fmt.Printf("%s (%s): %d points\n", result.File, result.Operator, result.Total)
+
+		for _, msg := range result.Errors {
+			fmt.Printf("    error: %s\n", msg)
+		}
+
+		for _, award := range result.Awards {
+			fmt.Printf("    + %d %s\n", award.Points, award.Category)
+		}
+
+		for _, check := range result.Checks {
+			status := "ok"
+			if !check.Valid() {
+				status = "invalid"
+			}
+
+			fmt.Printf("    [%s] %s %s at %d\n", status, check.Kind, check.Hash, check.Height)
+
+			for _, msg := range check.Errors {
+				fmt.Printf("        %s\n", msg)
+			}
+		}
+	}
+}
+
+// runTreasureHunt - Prints the normalised treasure hunt submissions and the files
+// which were conflicting or could not be parsed
+func runTreasureHunt(args []string) {
+	fs := flag.NewFlagSet("challenges treasure-hunt", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		log.Fatal(challengesUsage)
+	}
+
+	hunt, err := challenges.ParseTreasureHunt(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Error while reading treasure hunt submissions: %v", err)
+	}
+
+	if *asJSON {
+		printJSON(hunt)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Validator \t Challenge \t Height \t Tx Hash \t Files")
+
+	for _, submission := range hunt.Submissions {
+		files := strings.Join(submission.Files, ", ")
+		if submission.Conflict {
+			files += " (conflict)"
+		}
+
+		if submission.Answer != "" {
+			fmt.Fprintln(w, " "+submission.Validator+"\t "+strconv.Itoa(submission.Challenge)+
+				"\t -\t answer "+strconv.Quote(submission.Answer)+"\t "+files)
+		}
+
+		for _, entry := range submission.Entries {
+			fmt.Fprintln(w, " "+submission.Validator+"\t "+strconv.Itoa(submission.Challenge)+
+				"\t "+strconv.FormatInt(entry.Height, 10)+"\t "+entry.TxHash+"\t "+files)
+		}
+	}
+
+	w.Flush()
+
+	if len(hunt.Issues) == 0 {
+		return
+	}
+
+	fmt.Println("\nIssues:")
+
+	for _, issue := range hunt.Issues {
+		fmt.Printf(" [%s] %s/%s: %s\n", issue.Kind, issue.Validator, issue.File, issue.Message)
+	}
+}
+
+// runChallengesResolve - Ranks the submitted txs of a challenge with a deadline and/or a
+// first-come-first-served rule and prints the winners with the evidence used
+func runChallengesResolve(args []string) {
+	fs := flag.NewFlagSet("challenges resolve", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	challenge := fs.String("challenge", "", "challenge flag: Submission schema or treasure-hunt, defaults to the directory name")
+	only := fs.String("only", "", "only flag: Resolve a single challenge, ex: 5 for the final treasure hunt challenge")
+	txDump := fs.String("txs", "", "txs flag: JSON tx dump used instead of the database")
+	cutoff := fs.String("cutoff", "", "cutoff flag: Submissions committed or sent after this time (RFC3339) are excluded")
+	firstOnly := fs.Bool("first-only", false, "first-only flag: Only the first participant wins")
+	winners := fs.Int("winners", 0, "winners flag: Number of winners, all participants when 0")
+	rankLast := fs.Bool("rank-last", false, "rank-last flag: Rank participants by their last tx instead of the first")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		log.Fatal(challengesUsage)
+	}
+
+	dir := fs.Arg(0)
+
+	if *challenge == "" {
+		*challenge = filepath.Base(filepath.Clean(dir))
+	}
+
+	rule := challenges.DeadlineRule{FirstOnly: *firstOnly, Winners: *winners, RankLast: *rankLast}
+
+	if *cutoff != "" {
+		cutoffTime, err := time.Parse(time.RFC3339, *cutoff)
+		if err != nil {
+			log.Fatalf("Invalid --cutoff time %q: %v", *cutoff, err)
+		}
+
+		rule.Cutoff = cutoffTime
+	}
+
+	var claims []challenges.Claim
+
+	if *challenge == "treasure-hunt" {
+		hunt, err := challenges.ParseTreasureHunt(dir)
+		if err != nil {
+			log.Fatalf("Error while reading treasure hunt submissions: %v", err)
+		}
+
+		claims = challenges.TreasureHuntClaims(dir, hunt)
+	} else {
+		reports, err := challenges.ValidateDir(*challenge, dir)
+		if err != nil {
+			log.Fatalf("Error while reading submissions: %v", err)
+		}
+
+		claims = challenges.SubmissionClaims(*challenge, dir, reports)
+	}
+
+	if *only != "" {
+		var filtered []challenges.Claim
+
+		for _, claim := range claims {
+			if claim.Challenge == *only {
+				filtered = append(filtered, claim)
+			}
+		}
+
+		claims = filtered
+	}
+
+	txs, closeTxs := openClaimTxSource(*txDump)
+	defer closeTxs()
+
+	resolutions := challenges.ResolveClaims(claims, txs, challenges.NewGitRepo(dir), rule)
+
+	if *asJSON {
+		printJSON(resolutions)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Challenge \t Rank \t Winner \t Participant \t Tx Hash \t Evidence \t Excluded")
+
+	for _, res := range resolutions {
+		evidence := "-"
+
+		switch res.Evidence.Source {
+		case challenges.EvidenceChain:
+			evidence = "height " + strconv.FormatInt(res.Evidence.Height, 10)
+		case challenges.EvidenceGit:
+			evidence = "commit " + res.Evidence.CommitTime.UTC().Format(time.RFC3339)
+		}
+
+		rank, winner := "-", ""
+		if res.Rank > 0 {
+			rank = strconv.Itoa(res.Rank)
+		}
+
+		if res.Winner {
+			winner = "yes"
+		}
+
+		claimed := res.Claim.TxHash
+		if claimed == "" {
+			claimed = "answer " + strconv.Quote(res.Claim.Answer)
+		}
+
+		fmt.Fprintln(w, " "+res.Claim.Challenge+"\t "+rank+"\t "+winner+"\t "+res.Claim.Participant+
+			"\t "+claimed+"\t "+evidence+"\t "+res.Excluded)
+	}
+
+	w.Flush()
+}
+
+// runChallengesAttack - Evaluates the Phase-6 attack claims of a directory against the indexed
+// blocks and transactions, claims with a time window need the headers ingested with headers ingest
+func runChallengesAttack(args []string) {
+	rules := challenges.DefaultAttackRules
+
+	fs := flag.NewFlagSet("challenges attack", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	fs.Int64Var(&rules.Baseline, "baseline", 0, "baseline flag: Commits before the window compared with it, defaults to the window length")
+	fs.Float64Var(&rules.MinBaseline, "min-baseline", rules.MinBaseline, "min-baseline flag: Signed ratio a target needs in the baseline")
+	fs.Float64Var(&rules.MinDrop, "min-drop", rules.MinDrop, "min-drop flag: Drop of the signed ratio from which a target is down")
+	fs.Int64Var(&rules.TxMargin, "tx-margin", rules.TxMargin, "tx-margin flag: Blocks before the window the attack txs may start")
+	fs.Int64Var(&rules.Points, "points", rules.Points, "points flag: Points of a proven attack")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 || rules.MinDrop <= 0 || rules.MinDrop > 1 {
+		log.Fatal(challengesUsage)
+	}
+
+	reports, err := challenges.ValidateDir("phase-6", fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Error while reading claims: %v", err)
+	}
+
+	session, err := db.Connect(db.DialInfo(db.ReadConfig()))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	evaluator := challenges.NewAttackEvaluator(session)
+	evaluator.Rules = rules
+
+	var verdicts []challenges.AttackVerdict
+
+	for _, report := range reports {
+		if len(report.Errors) > 0 {
+			verdict := challenges.AttackVerdict{File: report.File, Verdict: challenges.VERDICT_INVALID, Errors: report.Errors}
+			if report.Submission != nil {
+				verdict.Operator = report.Submission.Operator()
+			}
+
+			verdicts = append(verdicts, verdict)
+
+			continue
+		}
+
+		verdicts = append(verdicts, evaluator.Evaluate(report.File, report.Submission.(*challenges.Phase6Submission)))
+	}
+
+	if *asJSON {
+		printJSON(verdicts)
+		return
+	}
+
+	for _, verdict := range verdicts {
+		fmt.Printf("%s (%s): %s, %d points\n", verdict.File, verdict.Operator, verdict.Verdict, verdict.Points)
+
+		for _, msg := range verdict.Errors {
+			fmt.Printf("    error: %s\n", msg)
+		}
+
+		if verdict.Verdict == challenges.VERDICT_INVALID {
+			continue
+		}
+
+		fmt.Printf("    window %d - %d\n", verdict.StartHeight, verdict.EndHeight)
+
+		for _, msg := range verdict.Reasons {
+			fmt.Printf("    %s\n", msg)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.Debug)
+		fmt.Fprintln(w, "    Target\tMoniker\tBaseline\tWindow\tDrop\tLongest missed\tDown\t")
+
+		for _, t := range verdict.Targets {
+			if len(t.Errors) > 0 {
+				fmt.Fprintf(w, "    %s\t%s\t%s\t\t\t\t\t\n", t.Operator, t.Moniker, strings.Join(t.Errors, "; "))
+				continue
+			}
+
+			fmt.Fprintf(w, "    %s\t%s\t%d/%d\t%d/%d\t%.2f\t%d\t%v\t\n", t.Operator, t.Moniker, t.BaselineSigned,
+				t.BaselineCommits, t.WindowSigned, t.WindowCommits, t.Drop, t.LongestMissed, t.Down)
+		}
+
+		w.Flush()
+
+		for _, check := range verdict.Txs {
+			status := "ok"
+			if !check.Valid() {
+				status = "invalid"
+			}
+
+			fmt.Printf("    [%s] %s at %d\n", status, check.Hash, check.Height)
+
+			for _, msg := range check.Errors {
+				fmt.Printf("        %s\n", msg)
+			}
+		}
+	}
+}
+
+// openClaimTxSource - Opens the tx dump when given, the database when it is configured and reachable,
+// no source (nil) otherwise so that the claims are ranked on their commit times only
+func openClaimTxSource(txDump string) (db.TxSource, func()) {
+	if txDump != "" {
+		dump, _ := openTxSource(txDump)
+		return dump, func() {}
+	}
+
+	cfg, err := config.Read()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "No tx source, resolving on commit times: %v\n", err)
+		return nil, func() {}
+	}
+
+	info := db.DialInfo(cfg)
+	if info.Timeout == 0 {
+		info.Timeout = 10 * time.Second
+	}
+
+	session, err := db.Connect(info)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "No tx source, resolving on commit times: %v\n", err)
+		return nil, func() {}
+	}
+
+	return session, session.Terminate
+}
+
+// txStore is the tx dump or the database session the tx proofs and the wasm txs are read from
+type txStore interface {
+	db.TxSource
+	contracts.TxQuery
+}
+
+// openTxSource - Opens the tx dump when given, a database session of config.toml otherwise. The
+// session is returned for the queries a tx dump can't answer and terminating it, nil with a tx dump
+func openTxSource(txDump string) (txStore, db.DB) {
+	if txDump != "" {
+		dump, err := db.LoadTxDump(txDump)
+		if err != nil {
+			log.Fatalf("Error while reading tx dump: %v", err)
+		}
+
+		return dump, nil
+	}
+
+	session, err := db.Connect(db.DialInfo(db.ReadConfig()))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	return session, session
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/regen-friends/testnets/util/uptime/challenges"
+	"github.com/regen-friends/testnets/util/uptime/contracts"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+const contractsUsage = `Usage:
+  contracts codes [--json] [--txs <tx dump.json>] [--operator <valoper>] [--issues]
+  contracts list [--json] [--txs <tx dump.json>] [--operator <valoper>] [--issues]
+  contracts erc20 [--json] [--txs <tx dump.json>] [--operator <valoper>] [--min-recipients <n>]
+  contracts escrow [--json] [--txs <tx dump.json>] [--operator <valoper>] [--submissions <phase-4 dir>] [--rank-bonus <spec>]`
+
+// runContracts - Lists the uploaded codes and instantiated contracts with their creator validators
+// in deployment order, with the issues of the naming, source and builder rules
+func runContracts(args []string) {
+	if len(args) < 1 || (args[0] != "codes" && args[0] != "list" && args[0] != "erc20" && args[0] != "escrow") {
+		log.Fatal(contractsUsage)
+	}
+
+	fs := flag.NewFlagSet("contracts "+args[0], flag.ExitOnError)
+	txDump := fs.String("txs", "", "txs flag: JSON tx dump used instead of the database")
+	operator := fs.String("operator", "", "operator flag: Only list the codes and contracts of a validator")
+	issues := fs.Bool("issues", false, "issues flag: Only list the codes and contracts with issues")
+	minRecipients := fs.Int("min-recipients", contracts.DefaultERC20Rules.MinRecipients,
+		"min-recipients flag: Validators an ERC20 creator has to transfer tokens to")
+	submissions := fs.String("submissions", "", "submissions flag: Phase-4 submissions checked against the escrows")
+	rankBonus := fs.String("rank-bonus", contracts.DEFAULT_RANK_BONUS,
+		"rank-bonus flag: Escrow rank bonus as points x validators tiers, ex: 100x5,50x5,25x10")
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args[1:])
+
+	txs, session := openTxSource(*txDump)
+	if session != nil {
+		defer session.Terminate()
+	}
+
+	index := loadContractIndex(txs, session)
+
+	if args[0] == "erc20" {
+		printERC20Checks(index.CheckERC20(contracts.ERC20Rules{MinRecipients: *minRecipients}), *operator, *asJSON)
+		return
+	}
+
+	if args[0] == "escrow" {
+		printEscrows(index.Escrows(), escrowRules(*rankBonus), *operator, *submissions, *asJSON)
+		return
+	}
+
+	codes, list := index.Codes, index.Contracts
+	if *operator != "" {
+		codes, list = index.ByOperator(*operator)
+	}
+
+	if args[0] == "codes" {
+		var selected []contracts.Code
+
+		for _, c := range codes {
+			if len(c.Issues) > 0 || !*issues {
+				selected = append(selected, c)
+			}
+		}
+
+		if *asJSON {
+			printJSON(selected)
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+		fmt.Fprintln(w, " Order \t Code ID \t Height \t Operator Addr \t Moniker \t Source \t Builder \t Issues")
+
+		for _, c := range selected {
+			fmt.Fprintln(w, " "+strconv.Itoa(c.Order)+"\t "+c.CodeID+"\t "+strconv.FormatInt(c.Height, 10)+
+				"\t "+c.Operator.OperatorAddr+"\t "+c.Operator.Moniker+"\t "+c.Source+"\t "+c.Builder+
+				"\t "+strings.Join(c.Issues, "; "))
+		}
+
+		w.Flush()
+
+		return
+	}
+
+	var selected []contracts.Contract
+
+	for _, c := range list {
+		if len(c.Issues) > 0 || !*issues {
+			selected = append(selected, c)
+		}
+	}
+
+	if *asJSON {
+		printJSON(selected)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Order \t Contract \t Code ID \t Height \t Operator Addr \t Moniker \t Label \t Executions \t Executors \t Issues")
+
+	for _, c := range selected {
+		fmt.Fprintln(w, " "+strconv.Itoa(c.Order)+"\t "+c.Address+"\t "+c.CodeID+"\t "+strconv.FormatInt(c.Height, 10)+
+			"\t "+c.Operator.OperatorAddr+"\t "+c.Operator.Moniker+"\t "+c.Label+"\t "+strconv.FormatInt(c.Executions, 10)+
+			"\t "+strconv.Itoa(c.Executors)+"\t "+strings.Join(c.Issues, "; "))
+	}
+
+	w.Flush()
+}
+
+// printERC20Checks - Prints pass or fail of the transfer and allowance rules with the matched txs
+func printERC20Checks(checks []contracts.ERC20Check, operator string, asJSON bool) {
+	var selected []contracts.ERC20Check
+
+	for _, c := range checks {
+		if operator == "" || c.Operator.OperatorAddr == operator {
+			selected = append(selected, c)
+		}
+	}
+
+	if asJSON {
+		printJSON(selected)
+		return
+	}
+
+	status := func(pass bool) string {
+		if pass {
+			return "pass"
+		}
+
+		return "fail"
+	}
+
+	for _, c := range selected {
+		fmt.Printf("%s (%s) %s: transfers %s (%d validators), allowance %s\n", c.Operator.Moniker, c.Operator.OperatorAddr,
+			c.Contract, status(c.TransfersPass), c.Recipients, status(c.AllowancePass))
+
+		for _, t := range c.Transfers {
+			fmt.Printf("    transfer %s at %d to %s (%s)\n", t.TxHash, t.Height, t.Operator.Moniker, t.Recipient)
+		}
+
+		if a := c.Allowance; a != nil {
+			fmt.Printf("    approve %s at %d, transferFrom %s at %d by %s\n", a.ApproveTxHash, a.ApproveHeight,
+				a.TransferFromTxHash, a.TransferFromHeight, a.TransferFromSigner)
+		}
+
+		for _, reason := range c.Reasons {
+			fmt.Printf("    %s\n", reason)
+		}
+	}
+}
+
+// escrowReport is the output of the escrow command
+type escrowReport struct {
+	Escrows     []contracts.EscrowLifecycle `json:"escrows"`
+	Scores      []contracts.EscrowScore     `json:"scores"`
+	Submissions []escrowSubmission          `json:"submissions,omitempty"`
+}
+
+type escrowSubmission struct {
+	File     string   `json:"file"`
+	Operator string   `json:"operator"`
+	Contract string   `json:"contract"`
+	Issues   []string `json:"issues"`
+}
+
+// escrowRules - Returns the default escrow rules with the given rank bonus
+func escrowRules(rankBonus string) contracts.EscrowRules {
+	bonus, err := contracts.ParseRankBonus(rankBonus)
+	if err != nil {
+		log.Fatalf("Error while reading rank bonus: %v", err)
+	}
+
+	rules := contracts.DefaultEscrowRules
+	rules.RankBonus = bonus
+
+	return rules
+}
+
+// printEscrows - Prints the lifecycle of the escrows with their transitions and the phase-4 points of
+// the validators, submissions are checked against the escrow of their contract address
+func printEscrows(escrows []contracts.EscrowLifecycle, rules contracts.EscrowRules, operator, submissionsDir string,
+	asJSON bool) {
+	report := escrowReport{Scores: contracts.ScoreEscrows(escrows, rules)}
+
+	for _, l := range escrows {
+		if operator == "" || l.Operator.OperatorAddr == operator {
+			report.Escrows = append(report.Escrows, l)
+		}
+	}
+
+	if operator != "" {
+		var scores []contracts.EscrowScore
+
+		for _, s := range report.Scores {
+			if s.Operator.OperatorAddr == operator {
+				scores = append(scores, s)
+			}
+		}
+
+		report.Scores = scores
+	}
+
+	if submissionsDir != "" {
+		report.Submissions = checkEscrowSubmissions(escrows, submissionsDir, operator)
+	}
+
+	if asJSON {
+		printJSON(report)
+		return
+	}
+
+	for _, l := range report.Escrows {
+		fmt.Printf("%s (%s) %s: %s, arbiter %s, recipient %s, end height %d, end time %d, balance %s\n",
+			l.Operator.Moniker, l.Operator.OperatorAddr, l.Contract, l.State, l.Arbiter, l.Recipient, l.EndHeight,
+			l.EndTime, coinsString(l.Balance))
+		fmt.Printf("    instantiate %s at %d with %s\n", l.TxHash, l.Height, coinsString(l.Funds))
+
+		for _, issue := range l.Issues {
+			fmt.Printf("        %s\n", issue)
+		}
+
+		for _, e := range l.Events {
+			status := "ok"
+			if !e.Valid() {
+				status = "invalid"
+			}
+
+			fmt.Printf("    [%s] %s %s at %d by %s of %s -> %s\n", status, e.Action, e.TxHash, e.Height, e.Sender,
+				coinsString(e.Amount), e.State)
+
+			for _, issue := range e.Issues {
+				fmt.Printf("        %s\n", issue)
+			}
+		}
+	}
+
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Rank \t Operator Addr \t Moniker \t Contract \t Completed \t Deployment \t Execution \t Bonus \t Total")
+
+	for _, s := range report.Scores {
+		fmt.Fprintln(w, " "+strconv.Itoa(s.Rank)+"\t "+s.Operator.OperatorAddr+"\t "+s.Operator.Moniker+"\t "+s.Contract+
+			"\t "+strconv.FormatInt(s.Completed, 10)+"\t "+strconv.FormatInt(s.Deployment, 10)+
+			"\t "+strconv.FormatInt(s.Execution, 10)+"\t "+strconv.FormatInt(s.Bonus, 10)+"\t "+strconv.FormatInt(s.Total, 10))
+	}
+
+	w.Flush()
+
+	for _, s := range report.Submissions {
+		if len(s.Issues) == 0 {
+			fmt.Printf("[OK]    %s\n", s.File)
+			continue
+		}
+
+		fmt.Printf("[ERROR] %s\n", s.File)
+
+		for _, issue := range s.Issues {
+			fmt.Printf("        %s\n", issue)
+		}
+	}
+}
+
+// checkEscrowSubmissions - Checks the approve and refund hashes of the phase-4 submissions against
+// the escrow lifecycles
+func checkEscrowSubmissions(escrows []contracts.EscrowLifecycle, dir, operator string) []escrowSubmission {
+	reports, err := challenges.ValidateDir("phase-4", dir)
+	if err != nil {
+		log.Fatalf("Error while reading submissions: %v", err)
+	}
+
+	var results []escrowSubmission
+
+	for _, report := range reports {
+		s, ok := report.Submission.(*challenges.Phase4Submission)
+		if !ok {
+			results = append(results, escrowSubmission{File: report.File, Issues: report.Errors})
+			continue
+		}
+
+		if operator != "" && s.ValOprAddr != operator {
+			continue
+		}
+
+		result := escrowSubmission{File: report.File, Operator: s.ValOprAddr, Contract: s.ContractAddress, Issues: report.Errors}
+
+		found := false
+
+		for _, l := range escrows {
+			if l.Contract == s.ContractAddress {
+				result.Issues = append(result.Issues, contracts.CheckEscrowSubmission(l, s.ValOprAddr, s.Approve, s.Refund)...)
+				found = true
+			}
+		}
+
+		if !found {
+			result.Issues = append(result.Issues, fmt.Sprintf("contract %s is not an indexed escrow", s.ContractAddress))
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// coinsString - Formats coins like 5000utree,10uxrn
+func coinsString(coins []db.Coin) string {
+	var list []string
+
+	for _, coin := range coins {
+		list = append(list, coin.Amount+coin.Denom)
+	}
+
+	if len(list) == 0 {
+		return "nothing"
+	}
+
+	return strings.Join(list, ",")
+}
+
+// loadContractIndex - Indexes the wasm txs, with the operators of the validators collection when
+// a database session is given, of the create validator txs of a tx dump otherwise
+func loadContractIndex(txs contracts.TxQuery, session db.DB) *contracts.Index {
+	index := contracts.NewIndex()
+
+	if session != nil {
+		validators, err := session.QueryValidators()
+		if err != nil {
+			log.Fatalf("Error while fetching validators: %v", err)
+		}
+
+		index.AddValidators(validators)
+	}
+
+	if err := index.Load(txs); err != nil {
+		log.Fatalf("Error while indexing contracts: %v", err)
+	}
+
+	return index
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/distribution"
+)
+
+const distributionUsage = `Usage:
+  distribution [--json] [--accounts <genesis accounts.json>] [--csv <reconciliation.csv>] <distribution.json>`
+
+// runDistribution - Converts the final standings into token allocations of a pool and writes them
+// as genesis accounts with a reconciliation CSV
+func runDistribution(args []string) {
+	fs := flag.NewFlagSet("distribution", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	accountsFile := fs.String("accounts", "", "accounts flag: Write the allocations as genesis accounts JSON")
+	csvFile := fs.String("csv", "", "csv flag: Write the reconciliation CSV of the allocations")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		log.Fatal(distributionUsage)
+	}
+
+	config, err := distribution.Load(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Error while reading distribution: %v", err)
+	}
+
+	standings, err := config.LoadStandings()
+	if err != nil {
+		log.Fatalf("Error while reading standings: %v", err)
+	}
+
+	d, err := distribution.New(config, standings)
+	if err != nil {
+		log.Fatalf("Error while distributing the pool: %v", err)
+	}
+
+	if *accountsFile != "" {
+		bz, err := json.MarshalIndent(d.GenesisAccounts(), "", "  ")
+		if err != nil {
+			log.Fatalf("Error while encoding genesis accounts: %v", err)
+		}
+
+		if err := ioutil.WriteFile(*accountsFile, append(bz, '\n'), 0644); err != nil {
+			log.Fatal("Cannot write to file", err)
+		}
+	}
+
+	if *csvFile != "" {
+		exportDistribution(*csvFile, d)
+	}
+
+	if *asJSON {
+		printJSON(d)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Rank \t Moniker \t Address \t Points \t Amount \t Bound")
+
+	for _, a := range d.Allocations {
+		fmt.Fprintln(w, " "+strconv.Itoa(a.Rank)+"\t "+a.Moniker+"\t "+a.Address+"\t "+fmt.Sprintf("%f", a.Points)+
+			"\t "+a.Amount+d.Denom+"\t "+a.Bound)
+	}
+
+	w.Flush()
+
+	for _, e := range d.Excluded {
+		fmt.Printf("excluded %d %s (%s): %s\n", e.Rank, e.Moniker, e.Identity, e.Reason)
+	}
+
+	fmt.Printf("\n%s%s %s to %d participants for %f points\n", d.Pool, d.Denom, d.Formula, len(d.Allocations), d.Points)
+}
+
+// exportDistribution - Export the allocations and the excluded participants with a total row, the
+// amount of the total row is the pool
+func exportDistribution(path string, d *distribution.Distribution) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal("Cannot write to file", err)
+	}
+
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	vestingEnd := ""
+	if d.Vesting != nil {
+		vestingEnd = d.Vesting.EndTime.UTC().Format(time.RFC3339)
+	}
+
+	_ = writer.Write([]string{"Rank", "Identity", "Moniker", "Operator", "Address", "Points", "Share", "Amount", "Denom",
+		"Bound", "Vesting End", "Excluded"})
+
+	for _, a := range d.Allocations {
+		if err := writer.Write([]string{strconv.Itoa(a.Rank), a.Identity, a.Moniker, a.Operator, a.Address,
+			fmt.Sprintf("%f", a.Points), fmt.Sprintf("%f", a.Points/d.Points), a.Amount, d.Denom, a.Bound, vestingEnd,
+			""}); err != nil {
+			log.Fatal("Cannot write to file", err)
+		}
+	}
+
+	for _, e := range d.Excluded {
+		if err := writer.Write([]string{strconv.Itoa(e.Rank), e.Identity, e.Moniker, "", "", fmt.Sprintf("%f", e.Points),
+			"", "0", d.Denom, "", "", e.Reason}); err != nil {
+			log.Fatal("Cannot write to file", err)
+		}
+	}
+
+	_ = writer.Write([]string{"Total", "", "", "", "", fmt.Sprintf("%f", d.Points), "", d.Pool, d.Denom, "", "", ""})
+}
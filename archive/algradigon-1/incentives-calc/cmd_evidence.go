@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/rpc"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+const evidenceUsage = `Usage:
+  evidence ingest --rpc <url> --start <block> [--end <block>]
+  evidence list [--json] --start <block> --end <block>`
+
+// runEvidence - Ingests the double-sign evidence included in blocks and lists it by offender
+func runEvidence(args []string) {
+	if len(args) < 1 || (args[0] != "ingest" && args[0] != "list") {
+		log.Fatal(evidenceUsage)
+	}
+
+	fs := flag.NewFlagSet("evidence "+args[0], flag.ExitOnError)
+	rpcURL := fs.String("rpc", "", "rpc flag: Tendermint RPC url of a node keeping the history, e.g. http://localhost:26657")
+	start := fs.Int64("start", -1, "start flag: Start Block Number")
+	end := fs.Int64("end", -1, "end flag: End Block Number")
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args[1:])
+
+	if *start < 1 || (args[0] == "ingest" && *rpcURL == "") || (args[0] == "list" && *end < 1) {
+		log.Fatal(evidenceUsage)
+	}
+
+	cfg := db.ReadConfig()
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	handler := src.New(session, cfg)
+
+	if args[0] == "ingest" {
+		if *end < 1 {
+			latest, err := session.QueryLatestHeight()
+			if err != nil {
+				log.Fatalf("Error while fetching the latest block: %v", err)
+			}
+
+			*end = latest
+		}
+
+		ingested, err := handler.IngestEvidence(rpc.NewClient(*rpcURL), *start, *end)
+		if err != nil {
+			log.Fatalf("Error while ingesting evidence after %d pieces: %v", ingested, err)
+		}
+
+		fmt.Printf("Ingested %d pieces of evidence (%d - %d)\n", ingested, *start, *end)
+
+		return
+	}
+
+	evidence, err := handler.EvidenceRecords(*start, *end, handler.LoadResolver())
+	if err != nil {
+		log.Fatalf("Error while fetching evidence: %v", err)
+	}
+
+	if *asJSON {
+		printJSON(evidence)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Height \t Vote Height \t Vote Round \t Type \t Hex Address \t Operator Addr \t Moniker")
+
+	for _, e := range evidence {
+		fmt.Fprintln(w, " "+strconv.FormatInt(e.Height, 10)+"\t "+strconv.FormatInt(e.VoteHeight, 10)+
+			"\t "+strconv.FormatInt(e.VoteRound, 10)+"\t "+e.Type+"\t "+e.Address+"\t "+e.OperatorAddr+"\t "+e.Moniker)
+	}
+
+	w.Flush()
+
+	fmt.Printf("%d pieces of evidence in %d - %d\n", len(evidence), *start, *end)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/genesis"
+)
+
+const genesisUsage = `Usage:
+  genesis inspect [--json] <genesis.json>
+  genesis diff [--json] <genesis-a.json> <genesis-b.json>
+  genesis lint [--json] [--ref <RFC3339 time>] [--checksum <file>] <genesis.json>`
+
+// Exit codes of genesis lint
+const (
+	lintPassed  = 0
+	lintFailed  = 1
+	lintInvalid = 2
+)
+
+func runGenesis(args []string) {
+	if len(args) < 1 {
+		log.Fatal(genesisUsage)
+	}
+
+	if args[0] == "lint" {
+		os.Exit(runGenesisLint(args[1:]))
+	}
+
+	fs := flag.NewFlagSet("genesis "+args[0], flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args[1:])
+
+	switch {
+	case args[0] == "inspect" && fs.NArg() == 1:
+		summary := loadSummary(fs.Arg(0))
+
+		if *asJSON {
+			printJSON(summary)
+			return
+		}
+
+		printSummary(summary)
+	case args[0] == "diff" && fs.NArg() == 2:
+		changes := genesis.Diff(loadSummary(fs.Arg(0)), loadSummary(fs.Arg(1)))
+
+		if *asJSON {
+			printJSON(changes)
+			return
+		}
+
+		printChanges(fs.Arg(0), fs.Arg(1), changes)
+	default:
+		log.Fatal(genesisUsage)
+	}
+}
+
+// runGenesisLint - Lints a genesis and returns the exit code, 1 if any check
+// failed and 2 if the genesis or the flags are invalid
+func runGenesisLint(args []string) int {
+	fs := flag.NewFlagSet("genesis lint", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	ref := fs.String("ref", "", "ref flag: Time the genesis time should be after (RFC3339), defaults to now")
+	checksum := fs.String("checksum", "", "checksum flag: sha256 checksum file, defaults to <genesis>.sha256 when it exists")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, genesisUsage)
+		return lintInvalid
+	}
+
+	path := fs.Arg(0)
+	opts := genesis.LintOptions{Reference: time.Now(), ChecksumFile: *checksum}
+
+	//The default checksum file is only checked when it exists
+	if opts.ChecksumFile == "" {
+		if _, err := os.Stat(path + ".sha256"); err == nil {
+			opts.ChecksumFile = path + ".sha256"
+		}
+	}
+
+	if *ref != "" {
+		reference, err := time.Parse(time.RFC3339, *ref)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --ref time %q: %v\n", *ref, err)
+			return lintInvalid
+		}
+
+		opts.Reference = reference
+	}
+
+	results, err := genesis.Lint(path, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error while reading genesis %s: %v\n", path, err)
+		return lintInvalid
+	}
+
+	code := lintPassed
+
+	for _, result := range results {
+		if !result.Passed() {
+			code = lintFailed
+		}
+	}
+
+	if *asJSON {
+		printJSON(results)
+		return code
+	}
+
+	for _, result := range results {
+		status := "PASS"
+		if !result.Passed() {
+			status = "FAIL"
+		}
+
+		fmt.Printf("[%s] %s\n", status, result.Name)
+
+		for _, msg := range result.Errors {
+			fmt.Printf("       %s\n", msg)
+		}
+	}
+
+	return code
+}
+
+func loadSummary(path string) *genesis.Summary {
+	gen, err := genesis.Load(path)
+	if err != nil {
+		log.Fatalf("Error while reading genesis %s: %v", path, err)
+	}
+
+	summary, err := genesis.Summarize(gen)
+	if err != nil {
+		log.Fatalf("Error while summarizing genesis %s: %v", path, err)
+	}
+
+	return summary
+}
+
+func printSummary(s *genesis.Summary) {
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+
+	fmt.Fprintf(w, "Chain ID\t%s\n", s.ChainID)
+	fmt.Fprintf(w, "Genesis Time\t%s\n", s.GenesisTime)
+	fmt.Fprintf(w, "Accounts\t%d\n", s.Accounts)
+	fmt.Fprintf(w, "Validators\t%d\n", s.Validators)
+
+	for _, denom := range s.Denoms() {
+		fmt.Fprintf(w, "Supply (%s)\t%s\n", denom, s.Supply[denom])
+	}
+
+	fmt.Fprintln(w, "\nParams\t")
+
+	for _, key := range s.ParamKeys() {
+		fmt.Fprintf(w, " %s\t%s\n", key, s.Params[key])
+	}
+
+	w.Flush()
+}
+
+func printChanges(pathA, pathB string, changes []genesis.Change) {
+	if len(changes) == 0 {
+		fmt.Println("No differences found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Key \t "+pathA+" \t "+pathB)
+
+	for _, change := range changes {
+		fmt.Fprintln(w, " "+orMissing(change.Key)+"\t "+orMissing(change.A)+"\t "+orMissing(change.B))
+	}
+
+	w.Flush()
+}
+
+func orMissing(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(v); err != nil {
+		log.Fatalf("Error while encoding output: %v", err)
+	}
+}
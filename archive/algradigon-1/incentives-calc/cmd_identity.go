@@ -0,0 +1,191 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/regen-friends/testnets/util/uptime/identity"
+)
+
+const identityUsage = `Usage:
+  identity records [--json] <chain dir>...
+  identity suggest [--json] [--registry <identities.json>] [--min <score>] <chain dir>...
+  identity confirm [--registry <identities.json>] --id <participant> [--name <name>] <chain-id>/<address>...
+  identity reject [--registry <identities.json>] <chain-id>/<address> <chain-id>/<address>
+  identity list [--json] [--registry <identities.json>]`
+
+// runIdentity - Links the validators of the different chains to participants
+func runIdentity(args []string) {
+	if len(args) == 0 {
+		log.Fatal(identityUsage)
+	}
+
+	switch args[0] {
+	case "records":
+		runIdentityRecords(args[1:])
+	case "suggest":
+		runIdentitySuggest(args[1:])
+	case "confirm":
+		runIdentityConfirm(args[1:])
+	case "reject":
+		runIdentityReject(args[1:])
+	case "list":
+		runIdentityList(args[1:])
+	default:
+		log.Fatal(identityUsage)
+	}
+}
+
+// runIdentityRecords - Prints the validators read from the genesis and gentxs of the chains
+func runIdentityRecords(args []string) {
+	fs := flag.NewFlagSet("identity records", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args)
+
+	records := collectRecords(fs.Args())
+
+	if *asJSON {
+		printJSON(records)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Ref \t Moniker \t Keybase \t Consensus Address \t Node ID")
+
+	for _, r := range records {
+		fmt.Fprintln(w, " "+r.Ref()+"\t "+r.Moniker+"\t "+r.Keybase+"\t "+r.ConsAddress+"\t "+r.NodeID)
+	}
+
+	w.Flush()
+}
+
+// runIdentitySuggest - Prints the likely matches between chains which aren't confirmed or rejected yet
+func runIdentitySuggest(args []string) {
+	fs := flag.NewFlagSet("identity suggest", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	registryFile := fs.String("registry", "identities.json", "registry flag: Confirmed identities file")
+	minScore := fs.Float64("min", 0.7, "min flag: Minimum match score between 0 and 1")
+	_ = fs.Parse(args)
+
+	registry := loadIdentityRegistry(*registryFile)
+	suggestions := identity.Suggest(collectRecords(fs.Args()), registry, *minScore)
+
+	if *asJSON {
+		printJSON(suggestions)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Score \t A \t Moniker A \t B \t Moniker B \t Reasons")
+
+	for _, s := range suggestions {
+		fmt.Fprintln(w, " "+fmt.Sprintf("%.2f", s.Score)+"\t "+s.A.Ref()+"\t "+s.A.Moniker+"\t "+
+			s.B.Ref()+"\t "+s.B.Moniker+"\t "+strings.Join(s.Reasons, ", "))
+	}
+
+	w.Flush()
+}
+
+// runIdentityConfirm - Records validators as members of a participant
+func runIdentityConfirm(args []string) {
+	fs := flag.NewFlagSet("identity confirm", flag.ExitOnError)
+	registryFile := fs.String("registry", "identities.json", "registry flag: Confirmed identities file")
+	id := fs.String("id", "", "id flag: Participant id, created when new")
+	name := fs.String("name", "", "name flag: Display name of the participant")
+	_ = fs.Parse(args)
+
+	if *id == "" || fs.NArg() == 0 {
+		log.Fatal(identityUsage)
+	}
+
+	registry := loadIdentityRegistry(*registryFile)
+
+	if err := registry.Confirm(*id, *name, fs.Args()); err != nil {
+		log.Fatalf("Cannot confirm %s: %v", *id, err)
+	}
+
+	saveIdentityRegistry(*registryFile, registry)
+	fmt.Printf("Confirmed %d members for %s\n", fs.NArg(), *id)
+}
+
+// runIdentityReject - Records that two validators are different participants
+func runIdentityReject(args []string) {
+	fs := flag.NewFlagSet("identity reject", flag.ExitOnError)
+	registryFile := fs.String("registry", "identities.json", "registry flag: Confirmed identities file")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		log.Fatal(identityUsage)
+	}
+
+	registry := loadIdentityRegistry(*registryFile)
+
+	if err := registry.Reject(fs.Arg(0), fs.Arg(1)); err != nil {
+		log.Fatalf("Cannot reject: %v", err)
+	}
+
+	saveIdentityRegistry(*registryFile, registry)
+	fmt.Printf("Rejected %s - %s\n", fs.Arg(0), fs.Arg(1))
+}
+
+// runIdentityList - Prints the confirmed participants
+func runIdentityList(args []string) {
+	fs := flag.NewFlagSet("identity list", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	registryFile := fs.String("registry", "identities.json", "registry flag: Confirmed identities file")
+	_ = fs.Parse(args)
+
+	registry := loadIdentityRegistry(*registryFile)
+
+	if *asJSON {
+		printJSON(registry)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Participant \t Name \t Members")
+
+	for _, p := range registry.Participants {
+		fmt.Fprintln(w, " "+p.ID+"\t "+p.Name+"\t "+strings.Join(p.Members, " "))
+	}
+
+	w.Flush()
+}
+
+func collectRecords(dirs []string) []identity.Record {
+	if len(dirs) == 0 {
+		log.Fatal(identityUsage)
+	}
+
+	var records []identity.Record
+
+	for _, dir := range dirs {
+		chainRecords, err := identity.CollectChain(dir)
+		if err != nil {
+			log.Fatalf("Error while reading %s: %v", dir, err)
+		}
+
+		records = append(records, chainRecords...)
+	}
+
+	return records
+}
+
+func loadIdentityRegistry(path string) *identity.Registry {
+	registry, err := identity.LoadRegistry(path)
+	if err != nil {
+		log.Fatalf("Error while reading identity registry: %v", err)
+	}
+
+	return registry
+}
+
+func saveIdentityRegistry(path string, registry *identity.Registry) {
+	if err := registry.Save(path); err != nil {
+		log.Fatalf("Cannot write identity registry: %v", err)
+	}
+}
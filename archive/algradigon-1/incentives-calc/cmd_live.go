@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+const liveUsage = `Usage:
+  live init [--run <name>] [--reset] --start <block>
+  live update [--run <name>] [--interval <blocks>] [--follow <duration>]
+  live standings [--run <name>] [--height <block>] [--json]`
+
+// runLive - Incremental scoring, the accumulators of a run are kept in the database
+// and updated with the heights ingested since the last update
+func runLive(args []string) {
+	if len(args) < 1 {
+		log.Fatal(liveUsage)
+	}
+
+	fs := flag.NewFlagSet("live "+args[0], flag.ExitOnError)
+	run := fs.String("run", "live", "run flag: Name of the scoring run")
+	start := fs.Int64("start", -1, "start flag: Start Block Number of the run")
+	reset := fs.Bool("reset", false, "reset flag: Replace an existing run")
+	interval := fs.Int64("interval", 1000, "interval flag: Blocks in between stored checkpoints")
+	follow := fs.Duration("follow", 0, "follow flag: Keep consuming new heights, polling at this interval")
+	height := fs.Int64("height", 0, "height flag: Standings at this height instead of the latest consumed one")
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args[1:])
+
+	cfg := db.ReadConfig()
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	handler := src.New(session, cfg)
+
+	switch args[0] {
+	case "init":
+		if *start < 1 {
+			log.Fatal(liveUsage)
+		}
+
+		existing, err := session.LoadScoreState(*run)
+		if err != nil {
+			log.Fatalf("Error while loading run %s: %v", *run, err)
+		}
+
+		if existing != nil && !*reset {
+			log.Fatalf("Run %s already exists at height %d, use --reset to replace it", *run, existing.Height)
+		}
+
+		if err := session.SaveScoreState(src.NewScoreState(cfg, *run, *start)); err != nil {
+			log.Fatalf("Error while saving run %s: %v", *run, err)
+		}
+
+		fmt.Printf("Run %s starts at height %d\n", *run, *start)
+	case "update":
+		for {
+			state, consumed, err := handler.UpdateLiveScores(*run, *interval)
+			if err != nil {
+				log.Fatalf("Error while updating run %s: %v", *run, err)
+			}
+
+			fmt.Printf("Run %s consumed %d blocks, at height %d\n", *run, consumed, state.Height)
+
+			if *follow <= 0 {
+				return
+			}
+
+			time.Sleep(*follow)
+		}
+	case "standings":
+		results, err := handler.LiveStandings(*run, *height)
+		if err != nil {
+			log.Fatalf("Error while scoring run %s: %v", *run, err)
+		}
+
+		if *asJSON {
+			printJSON(results)
+			return
+		}
+
+		fmt.Println("Standings of blocks", results.StartBlock, "to", results.EndBlock)
+
+		src.PrintResults(results)
+	default:
+		log.Fatal(liveUsage)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/rpc"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+const powerUsage = `Usage:
+  power ingest --rpc <url> [--start <block>] [--end <block>]
+  power uptime [--json] --start <block> --end <block>
+  power blocks [--json] [--csv <file>] [--below <ratio>] --start <block> --end <block>`
+
+// runPower - Voting power per height, the power weighted uptime and the signed power of the blocks
+func runPower(args []string) {
+	if len(args) < 1 {
+		log.Fatal(powerUsage)
+	}
+
+	fs := flag.NewFlagSet("power "+args[0], flag.ExitOnError)
+	rpcURL := fs.String("rpc", "", "rpc flag: Tendermint RPC url of a node keeping the history, e.g. http://localhost:26657")
+	start := fs.Int64("start", -1, "start flag: Start Block Number")
+	end := fs.Int64("end", -1, "end flag: End Block Number")
+	below := fs.Float64("below", 0.75, "below flag: Only list the blocks signed by less than this ratio of the voting power")
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	csvFile := fs.String("csv", "", "csv flag: Export the blocks to a CSV file")
+	_ = fs.Parse(args[1:])
+
+	cfg := db.ReadConfig()
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	handler := src.New(session, cfg)
+
+	switch args[0] {
+	case "ingest":
+		if *rpcURL == "" {
+			log.Fatal(powerUsage)
+		}
+
+		//Continue after the latest ingested height up to the latest block by default
+		if *start < 1 {
+			latest, err := session.QueryLatestPowerHeight()
+			if err != nil {
+				log.Fatalf("Error while fetching the latest voting power: %v", err)
+			}
+
+			*start = latest + 1
+		}
+
+		if *end < 1 {
+			latest, err := session.QueryLatestHeight()
+			if err != nil {
+				log.Fatalf("Error while fetching the latest block: %v", err)
+			}
+
+			*end = latest
+		}
+
+		ingested, err := handler.IngestVotingPower(rpc.NewClient(*rpcURL), *start, *end)
+		if err != nil {
+			log.Fatalf("Error while ingesting voting power after %d heights: %v", ingested, err)
+		}
+
+		fmt.Printf("Ingested the voting power of %d heights (%d - %d)\n", ingested, *start, *end)
+	case "uptime", "blocks":
+		if *start < 0 || *end < 1 {
+			log.Fatal(powerUsage)
+		}
+
+		report, err := handler.PowerReport(*start, *end)
+		if err != nil {
+			log.Fatalf("Error while calculating the voting power report: %v", err)
+		}
+
+		if report.MissingHeights > 0 {
+			fmt.Fprintf(os.Stderr, "%d heights have no block or no ingested voting power and are skipped\n", report.MissingHeights)
+		}
+
+		if args[0] == "uptime" {
+			printWeightedUptime(report, *asJSON)
+			return
+		}
+
+		blocks := report.Below(*below)
+
+		if *csvFile != "" {
+			exportBlockSigning(*csvFile, report.Blocks)
+		}
+
+		if *asJSON {
+			printJSON(blocks)
+			return
+		}
+
+		printBlockSigning(report, blocks, *below)
+	default:
+		log.Fatal(powerUsage)
+	}
+}
+
+func printWeightedUptime(report *src.PowerReport, asJSON bool) {
+	if asJSON {
+		printJSON(report.Validators)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Operator Addr \t Moniker \t Signed Blocks \t Active Blocks \t Power Uptime \t Signed Power Share")
+
+	for _, u := range report.Validators {
+		fmt.Fprintln(w, " "+u.OperatorAddr+"\t "+u.Moniker+"\t "+strconv.FormatInt(u.SignedBlocks, 10)+
+			"\t "+strconv.FormatInt(u.ActiveBlocks, 10)+"\t "+fmt.Sprintf("%.4f", u.PowerUptime)+
+			"\t "+fmt.Sprintf("%.4f", u.Share))
+	}
+
+	w.Flush()
+}
+
+// printBlockSigning - Prints the blocks with the validators which didn't sign, the margin is
+// the signed power above the 2/3 needed to commit
+func printBlockSigning(report *src.PowerReport, blocks []src.BlockSigning, below float64) {
+	monikers := make(map[string]string, len(report.Validators))
+
+	for _, u := range report.Validators {
+		monikers[u.Address] = u.Moniker
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Height \t Signed Power \t Total Power \t Signed \t Margin \t Missing")
+
+	for _, b := range blocks {
+		var missing []string
+
+		for _, v := range b.Missing {
+			name := monikers[v.Address]
+			if name == "" {
+				name = v.Address
+			}
+
+			missing = append(missing, fmt.Sprintf("%s (%d)", name, v.VotingPower))
+		}
+
+		fmt.Fprintln(w, " "+strconv.FormatInt(b.Height, 10)+"\t "+strconv.FormatInt(b.SignedPower, 10)+
+			"\t "+strconv.FormatInt(b.TotalPower, 10)+"\t "+fmt.Sprintf("%.2f%%", b.SignedRatio*100)+
+			"\t "+fmt.Sprintf("%+.2f%%", (b.SignedRatio-src.TwoThirds)*100)+"\t "+strings.Join(missing, ", "))
+	}
+
+	w.Flush()
+
+	fmt.Printf("%d of %d blocks signed by less than %.2f%% of the voting power\n", len(blocks), len(report.Blocks), below*100)
+}
+
+// exportBlockSigning - Export the signed power of every block of the window
+func exportBlockSigning(path string, blocks []src.BlockSigning) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal("Cannot write to file", err)
+	}
+
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	_ = writer.Write([]string{"Height", "Signed Power", "Total Power", "Signed Ratio", "Missing"})
+
+	for _, b := range blocks {
+		var missing []string
+
+		for _, v := range b.Missing {
+			missing = append(missing, v.Address+":"+strconv.FormatInt(v.VotingPower, 10))
+		}
+
+		record := []string{strconv.FormatInt(b.Height, 10), strconv.FormatInt(b.SignedPower, 10),
+			strconv.FormatInt(b.TotalPower, 10), fmt.Sprintf("%f", b.SignedRatio), strings.Join(missing, " ")}
+
+		if err := writer.Write(record); err != nil {
+			log.Fatal("Cannot write to file", err)
+		}
+	}
+}
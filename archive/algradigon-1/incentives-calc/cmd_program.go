@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/regen-friends/testnets/util/uptime/program"
+)
+
+const programUsage = `Usage:
+  program [--json] [--csv <file>] <program.json>`
+
+// runProgram - Runs every phase of a testnet program and prints the cumulative standings
+func runProgram(args []string) {
+	fs := flag.NewFlagSet("program", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	csvFile := fs.String("csv", "", "csv flag: Export the standings to a CSV file")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		log.Fatal(programUsage)
+	}
+
+	p, err := program.Load(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Error while reading program: %v", err)
+	}
+
+	standings, err := program.Run(p)
+	if err != nil {
+		log.Fatalf("Error while running program %s: %v", p.Name, err)
+	}
+
+	if *csvFile != "" {
+		exportStandings(*csvFile, standings)
+	}
+
+	if *asJSON {
+		printJSON(standings)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+
+	header := " Rank \t Moniker \t Identity"
+	for _, phase := range standings.Phases {
+		header += " \t " + phase.Name + " (" + phase.ChainID + ")"
+	}
+
+	fmt.Fprintln(w, header+" \t Total")
+
+	for _, s := range standings.Standings {
+		row := " " + strconv.Itoa(s.Rank) + "\t " + s.Moniker + "\t " + s.Identity
+		for _, subtotal := range s.Subtotals {
+			row += "\t " + fmt.Sprintf("%f", subtotal)
+		}
+
+		fmt.Fprintln(w, row+"\t "+fmt.Sprintf("%f", s.Total))
+	}
+
+	w.Flush()
+}
+
+// exportStandings - Export the standings with a column per phase and the operator addresses used
+func exportStandings(path string, standings *program.Standings) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal("Cannot write to file", err)
+	}
+
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{"Rank", "Moniker", "Identity"}
+	for _, phase := range standings.Phases {
+		header = append(header, phase.Name)
+	}
+
+	_ = writer.Write(append(header, "Total", "Operators"))
+
+	for _, s := range standings.Standings {
+		record := []string{strconv.Itoa(s.Rank), s.Moniker, s.Identity}
+		for _, subtotal := range s.Subtotals {
+			record = append(record, fmt.Sprintf("%f", subtotal))
+		}
+
+		var operators []string
+		for _, op := range s.Operators {
+			operators = append(operators, op.ChainID+":"+op.Operator)
+		}
+
+		if err := writer.Write(append(record, fmt.Sprintf("%f", s.Total), strings.Join(operators, " "))); err != nil {
+			log.Fatal("Cannot write to file", err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/rpc"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+const headersUsage = `Usage:
+  headers ingest --rpc <url> [--start <block>] [--end <block>]`
+
+const proposersUsage = `Usage:
+  proposers [--json] [--flagged] [--min-ratio <ratio>] [--min-expected <blocks>] --start <block> --end <block>`
+
+// runHeaders - Ingests the block headers (proposer) which are not part of the blocks collection
+func runHeaders(args []string) {
+	if len(args) < 1 || args[0] != "ingest" {
+		log.Fatal(headersUsage)
+	}
+
+	fs := flag.NewFlagSet("headers ingest", flag.ExitOnError)
+	rpcURL := fs.String("rpc", "", "rpc flag: Tendermint RPC url of a node keeping the history, e.g. http://localhost:26657")
+	start := fs.Int64("start", -1, "start flag: Start Block Number")
+	end := fs.Int64("end", -1, "end flag: End Block Number")
+	_ = fs.Parse(args[1:])
+
+	if *rpcURL == "" {
+		log.Fatal(headersUsage)
+	}
+
+	cfg := db.ReadConfig()
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	//Continue after the latest ingested header up to the latest block by default
+	if *start < 1 {
+		latest, err := session.QueryLatestHeaderHeight()
+		if err != nil {
+			log.Fatalf("Error while fetching the latest header: %v", err)
+		}
+
+		*start = latest + 1
+	}
+
+	if *end < 1 {
+		latest, err := session.QueryLatestHeight()
+		if err != nil {
+			log.Fatalf("Error while fetching the latest block: %v", err)
+		}
+
+		*end = latest
+	}
+
+	ingested, err := src.New(session, cfg).IngestBlockHeaders(rpc.NewClient(*rpcURL), *start, *end)
+	if err != nil {
+		log.Fatalf("Error while ingesting headers after %d heights: %v", ingested, err)
+	}
+
+	fmt.Printf("Ingested the headers of %d heights (%d - %d)\n", ingested, *start, *end)
+}
+
+// runProposers - Compares the blocks proposed by the validators with their voting power
+func runProposers(args []string) {
+	fs := flag.NewFlagSet("proposers", flag.ExitOnError)
+	start := fs.Int64("start", -1, "start flag: Start Block Number")
+	end := fs.Int64("end", -1, "end flag: End Block Number")
+	minRatio := fs.Float64("min-ratio", 0.5, "min-ratio flag: Flag validators proposing less than this ratio of their expected blocks")
+	minExpected := fs.Float64("min-expected", 10, "min-expected flag: Only flag validators expected to propose at least this many blocks")
+	flagged := fs.Bool("flagged", false, "flagged flag: Only list the flagged validators")
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args)
+
+	if *start < 0 || *end < 1 {
+		log.Fatal(proposersUsage)
+	}
+
+	cfg := db.ReadConfig()
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	report, err := src.New(session, cfg).ProposerFairness(*start, *end, *minRatio, *minExpected)
+	if err != nil {
+		log.Fatalf("Error while calculating proposer fairness: %v", err)
+	}
+
+	if report.MissingHeights > 0 {
+		fmt.Fprintf(os.Stderr, "%d heights have no ingested header or voting power and are skipped\n", report.MissingHeights)
+	}
+
+	var validators []src.ProposerStats
+
+	for _, s := range report.Validators {
+		if s.Flagged || !*flagged {
+			validators = append(validators, s)
+		}
+	}
+
+	if *asJSON {
+		printJSON(validators)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Operator Addr \t Moniker \t Address \t Proposed \t Expected \t Ratio \t Flagged")
+
+	for _, s := range validators {
+		note := ""
+		if s.Flagged {
+			note = "missing proposals"
+		}
+
+		fmt.Fprintln(w, " "+s.OperatorAddr+"\t "+s.Moniker+"\t "+s.Address+"\t "+strconv.FormatInt(s.Proposed, 10)+
+			"\t "+fmt.Sprintf("%.1f", s.Expected)+"\t "+fmt.Sprintf("%.2f", s.Ratio)+"\t "+note)
+	}
+
+	w.Flush()
+}
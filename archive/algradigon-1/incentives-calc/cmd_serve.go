@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/leaderboard"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+const serveUsage = `Usage:
+  serve [--addr <host:port>] --start <block> --end <block>
+  serve [--addr <host:port>] --results <result.json>`
+
+// runServe - Serves the leaderboard of a fresh calculation or of an exported result.json
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "addr flag: Address to listen on")
+	resultsFile := fs.String("results", "", "results flag: Serve the result.json of a previous run instead of calculating")
+	startBlock := fs.Int64("start", -1, "start flag: Start Block Number")
+	endBlock := fs.Int64("end", -1, "end flag: End Block Number")
+	_ = fs.Parse(args)
+
+	var results *src.Results
+
+	switch {
+	case *resultsFile != "":
+		loaded, err := src.LoadResults(*resultsFile)
+		if err != nil {
+			log.Fatalf("Error while reading results: %v", err)
+		}
+
+		results = loaded
+	case *startBlock >= 0 && *endBlock > 0:
+		cfg := db.ReadConfig()
+
+		session, err := db.Connect(db.DialInfo(cfg))
+		if err != nil {
+			log.Fatalf("ERR_DB_CONN: %s", err)
+		}
+
+		results = src.New(session, cfg).ScoreValidators(*startBlock, *endBlock)
+		session.Terminate()
+	default:
+		log.Fatal(serveUsage)
+	}
+
+	fmt.Printf("Serving %d validators on %s\n", len(results.Validators), *addr)
+
+	log.Fatal(http.ListenAndServe(*addr, leaderboard.NewServer(results)))
+}
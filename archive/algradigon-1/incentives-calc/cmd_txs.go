@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/rpc"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+const txsUsage = `Usage:
+  txs ingest --lcd <url> --start <block> [--end <block>]
+  txs profile [--json] [--csv <file prefix>] [--txs <tx dump.json>] [--top <n>] [--bucket <duration>]
+              --start <block> --end <block>`
+
+// runTxs - Ingests the txs with their gas and fee, and profiles the tx load of a range
+func runTxs(args []string) {
+	if len(args) < 1 {
+		log.Fatal(txsUsage)
+	}
+
+	switch args[0] {
+	case "ingest":
+		runTxsIngest(args[1:])
+	case "profile":
+		runTxsProfile(args[1:])
+	default:
+		log.Fatal(txsUsage)
+	}
+}
+
+func runTxsIngest(args []string) {
+	fs := flag.NewFlagSet("txs ingest", flag.ExitOnError)
+	lcdURL := fs.String("lcd", "", "lcd flag: REST server url of a node keeping the history, e.g. http://localhost:1317")
+	start := fs.Int64("start", -1, "start flag: Start Block Number")
+	end := fs.Int64("end", -1, "end flag: End Block Number")
+	_ = fs.Parse(args)
+
+	if *lcdURL == "" || *start < 1 {
+		log.Fatal(txsUsage)
+	}
+
+	cfg := db.ReadConfig()
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		log.Fatalf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	if *end < 1 {
+		latest, err := session.QueryLatestHeight()
+		if err != nil {
+			log.Fatalf("Error while fetching the latest block: %v", err)
+		}
+
+		*end = latest
+	}
+
+	ingested, err := src.New(session, cfg).IngestTransactions(rpc.NewLCD(*lcdURL), *start, *end)
+	if err != nil {
+		log.Fatalf("Error while ingesting txs after %d txs: %v", ingested, err)
+	}
+
+	fmt.Printf("Ingested %d txs (%d - %d)\n", ingested, *start, *end)
+}
+
+// runTxsProfile - Profiles the tx load by block, sender, message type and time bucket, from the
+// database or from a recorded tx dump
+func runTxsProfile(args []string) {
+	fs := flag.NewFlagSet("txs profile", flag.ExitOnError)
+	start := fs.Int64("start", -1, "start flag: Start Block Number")
+	end := fs.Int64("end", -1, "end flag: End Block Number")
+	top := fs.Int("top", 10, "top flag: Senders listed, 0 for all")
+	bucket := fs.Duration("bucket", time.Minute, "bucket flag: Length of the throughput buckets")
+	txDump := fs.String("txs", "", "txs flag: JSON tx dump used instead of the database")
+	csvPrefix := fs.String("csv", "", "csv flag: Export the blocks, senders, types and buckets to <prefix>-<table>.csv")
+	asJSON := fs.Bool("json", false, "json flag: Print the output as JSON")
+	_ = fs.Parse(args)
+
+	if *start < 0 || *end < 1 || *bucket <= 0 {
+		log.Fatal(txsUsage)
+	}
+
+	options := src.TxProfileOptions{Top: *top, Bucket: *bucket}
+
+	var profile *src.TxProfile
+
+	if *txDump != "" {
+		dump, err := db.LoadTxDump(*txDump)
+		if err != nil {
+			log.Fatalf("Error while reading tx dump: %v", err)
+		}
+
+		txs, _ := dump.QueryTxsByHeight(*start, *end)
+		profile = src.ProfileTransactions(txs, *start, *end, options)
+	} else {
+		cfg := db.ReadConfig()
+
+		session, err := db.Connect(db.DialInfo(cfg))
+		if err != nil {
+			log.Fatalf("ERR_DB_CONN: %s", err)
+		}
+
+		defer session.Terminate()
+
+		if profile, err = src.New(session, cfg).ProfileTxs(*start, *end, options); err != nil {
+			log.Fatalf("Error while profiling txs: %v", err)
+		}
+	}
+
+	if *csvPrefix != "" {
+		exportTxProfile(*csvPrefix, profile)
+	}
+
+	if *asJSON {
+		printJSON(profile)
+		return
+	}
+
+	fmt.Printf("%d txs in %d blocks, %d failed (%.2f%%), %d gas used, fees %s\n\n", profile.Total.Txs,
+		len(profile.Blocks), profile.Total.Failed, profile.Total.FailureRate*100, profile.Total.GasUsed, profile.Total.FeeString())
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Sender \t Txs \t Msgs \t Failed \t Failure Rate \t Gas Used \t Fees")
+
+	for _, s := range profile.Senders {
+		fmt.Fprintln(w, " "+s.Sender+"\t "+txStatsColumns(s.TxStats))
+	}
+
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Msg Type \t Txs \t Msgs \t Failed \t Failure Rate \t Gas Used \t Fees")
+
+	for _, t := range profile.MsgTypes {
+		fmt.Fprintln(w, " "+t.Type+"\t "+txStatsColumns(t.TxStats))
+	}
+
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Bucket \t Txs/s \t Gas/s \t Txs \t Msgs \t Failed \t Failure Rate \t Gas Used \t Fees")
+
+	for _, b := range profile.Buckets {
+		fmt.Fprintln(w, " "+b.Start.UTC().Format(time.RFC3339)+"\t "+fmt.Sprintf("%.2f", b.TxsPerSecond)+
+			"\t "+fmt.Sprintf("%.0f", b.GasPerSecond)+"\t "+txStatsColumns(b.TxStats))
+	}
+
+	w.Flush()
+}
+
+func txStatsColumns(s src.TxStats) string {
+	return strconv.FormatInt(s.Txs, 10) + "\t " + strconv.FormatInt(s.Msgs, 10) + "\t " + strconv.FormatInt(s.Failed, 10) +
+		"\t " + fmt.Sprintf("%.2f%%", s.FailureRate*100) + "\t " + strconv.FormatInt(s.GasUsed, 10) + "\t " + s.FeeString()
+}
+
+func txStatsRecord(s src.TxStats) []string {
+	return []string{strconv.FormatInt(s.Txs, 10), strconv.FormatInt(s.Msgs, 10), strconv.FormatInt(s.Failed, 10),
+		fmt.Sprintf("%f", s.FailureRate), strconv.FormatInt(s.GasWanted, 10), strconv.FormatInt(s.GasUsed, 10), s.FeeString()}
+}
+
+var txStatsHeader = []string{"Txs", "Msgs", "Failed", "Failure Rate", "Gas Wanted", "Gas Used", "Fees"}
+
+// exportTxProfile - Export every table of the profile to its own CSV file
+func exportTxProfile(prefix string, profile *src.TxProfile) {
+	var blocks, senders, types, buckets [][]string
+
+	for _, b := range profile.Blocks {
+		blocks = append(blocks, append([]string{strconv.FormatInt(b.Height, 10), b.Time.UTC().Format(time.RFC3339)},
+			txStatsRecord(b.TxStats)...))
+	}
+
+	for _, s := range profile.Senders {
+		senders = append(senders, append([]string{s.Sender}, txStatsRecord(s.TxStats)...))
+	}
+
+	for _, t := range profile.MsgTypes {
+		types = append(types, append([]string{t.Type}, txStatsRecord(t.TxStats)...))
+	}
+
+	for _, b := range profile.Buckets {
+		buckets = append(buckets, append([]string{b.Start.UTC().Format(time.RFC3339), b.End.UTC().Format(time.RFC3339),
+			fmt.Sprintf("%f", b.TxsPerSecond), fmt.Sprintf("%f", b.GasPerSecond)}, txStatsRecord(b.TxStats)...))
+	}
+
+	writeCsv(prefix+"-blocks.csv", append([]string{"Height", "Time"}, txStatsHeader...), blocks)
+	writeCsv(prefix+"-senders.csv", append([]string{"Sender"}, txStatsHeader...), senders)
+	writeCsv(prefix+"-types.csv", append([]string{"Msg Type"}, txStatsHeader...), types)
+	writeCsv(prefix+"-buckets.csv", append([]string{"Start", "End", "Txs/s", "Gas/s"}, txStatsHeader...), buckets)
+}
+
+func writeCsv(path string, header []string, records [][]string) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal("Cannot write to file", err)
+	}
+
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	_ = writer.Write(header)
+
+	for _, record := range records {
+		if err := writer.Write(record); err != nil {
+			log.Fatal("Cannot write to file", err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/regen-friends/testnets/util/uptime/upgrades"
+)
+
+const upgradesUsage = `Usage:
+  upgrades markdown [--name <upgrade>] <upgrades.json>
+  upgrades sync [--check] <upgrades.json> <file.md>...`
+
+func runUpgrades(args []string) {
+	if len(args) < 1 {
+		log.Fatal(upgradesUsage)
+	}
+
+	fs := flag.NewFlagSet("upgrades "+args[0], flag.ExitOnError)
+	name := fs.String("name", "", "name flag: Render the details of a single upgrade")
+	check := fs.Bool("check", false, "check flag: Only report the files which are out of date")
+	_ = fs.Parse(args[1:])
+
+	switch {
+	case args[0] == "markdown" && fs.NArg() == 1:
+		registry := loadRegistry(fs.Arg(0))
+
+		if *name == "" {
+			fmt.Print(registry.Overview())
+			return
+		}
+
+		upgrade, ok := registry.Find(*name)
+		if !ok {
+			log.Fatalf("Upgrade %s is not in %s", *name, fs.Arg(0))
+		}
+
+		fmt.Print(upgrade.Details())
+	case args[0] == "sync" && fs.NArg() >= 2:
+		os.Exit(runUpgradesSync(loadRegistry(fs.Arg(0)), fs.Args()[1:], *check))
+	default:
+		log.Fatal(upgradesUsage)
+	}
+}
+
+// runUpgradesSync - Regenerates the upgrade tables of the Markdown files, with check
+// the files are left untouched and 1 is returned when any of them is out of date
+func runUpgradesSync(registry *upgrades.Registry, files []string, check bool) int {
+	outdated := 0
+
+	for _, file := range files {
+		changed, err := registry.Sync(file, !check)
+		if err != nil {
+			log.Fatalf("Error while syncing %s: %v", file, err)
+		}
+
+		switch {
+		case changed && check:
+			fmt.Println("OUT OF DATE", file)
+			outdated++
+		case changed:
+			fmt.Println("UPDATED", file)
+		default:
+			fmt.Println("OK", file)
+		}
+	}
+
+	if check && outdated > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+func loadRegistry(path string) *upgrades.Registry {
+	registry, err := upgrades.Load(path)
+	if err != nil {
+		log.Fatalf("Error while reading upgrade registry: %v", err)
+	}
+
+	return registry
+}
@@ -0,0 +1,316 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/upgrades"
+	"github.com/spf13/viper"
+)
+
+// ENV_PREFIX - Secrets can be kept out of config.toml, UPTIME_PASSWORD overrides password
+const ENV_PREFIX = "uptime"
+
+// Keys which can be overridden from the environment
+var envKeys = []string{"mongo_uri", "username", "password"}
+
+// Bonuses are the point categories an offender can be disqualified from
+var Bonuses = []string{"uptime", "node", "upgrade1", "upgrade2", "proposal1", "proposal2", "genesis", "proposer"}
+
+// Config is the typed config.toml, every key is checked before the database is queried
+type Config struct {
+	MongoURI string `json:"mongoUri"`
+	Database string `json:"database"`
+	Username string `json:"username"`
+	Password string `json:"-"`
+	Source   string `json:"source"`
+	FailFast bool   `json:"failFast"`
+
+	UpgradesFile string          `json:"upgradesFile,omitempty"`
+	ElChoco      upgrades.Window `json:"elChoco"`
+	Amazonas     upgrades.Window `json:"amazonas"`
+
+	NodeRewards      int64 `json:"nodeRewards"`
+	MaxUptimeRewards int64 `json:"maxUptimeRewards"`
+	ProposerPoints   int64 `json:"proposerPoints,omitempty"`
+
+	DoubleSignDisqualify []string `json:"doubleSignDisqualify,omitempty"`
+
+	ElChocoVoteValidators  []string `json:"elChocoVoteValidators"`
+	AmazonasVoteValidators []string `json:"amazonasVoteValidators"`
+	GentxValidators        []string `json:"gentxValidators"`
+
+	GenesisFile     string `json:"genesisFile,omitempty"`
+	AdjustmentsFile string `json:"adjustmentsFile,omitempty"`
+}
+
+// Read - Reads config.toml from the working directory, unless a config file was set,
+// applies the environment overrides and loads the typed config
+func Read() (*Config, error) {
+	viper.SetConfigName("config") // name of config file (without extension)
+	viper.AddConfigPath(".")      // path to look for the config file in
+
+	return read(viper.GetViper())
+}
+
+// ReadFile - Reads a config file into a viper instance of its own, such as the config of a program
+// phase, the config of the working directory is left untouched
+func ReadFile(path string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+
+	return read(v)
+}
+
+func read(v *viper.Viper) (*Config, error) {
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("fatal error config file: %s", err)
+	}
+
+	v.SetEnvPrefix(ENV_PREFIX)
+
+	for _, key := range envKeys {
+		_ = v.BindEnv(key)
+	}
+
+	return load(v)
+}
+
+// Load - Converts the current viper settings into a Config, the errors of every key are
+// reported together. Whole numbers written as floats (100.0) are accepted for integer keys
+func Load() (*Config, error) {
+	return load(viper.GetViper())
+}
+
+func load(v *viper.Viper) (*Config, error) {
+	r := reader{v: v}
+
+	cfg := &Config{
+		MongoURI: r.requiredString("mongo_uri"),
+		Database: r.requiredString("database"),
+		Username: r.string("username"),
+		Password: r.string("password"),
+		Source:   r.string("source"),
+		FailFast: r.bool("failFast"),
+
+		UpgradesFile: r.string("upgrades_file"),
+
+		NodeRewards:      r.requiredInt64("node_rewards"),
+		MaxUptimeRewards: r.requiredInt64("max_uptime_rewards"),
+		ProposerPoints:   r.int64("proposer_points"),
+
+		DoubleSignDisqualify: r.names("double_sign_disqualify", Bonuses),
+
+		ElChocoVoteValidators:  r.operators("elchoco_vote_validators"),
+		AmazonasVoteValidators: r.operators("amazonas_vote_validators"),
+		GentxValidators:        r.operators("gentx_validators"),
+
+		GenesisFile:     r.string("genesis_file"),
+		AdjustmentsFile: r.string("adjustments_file"),
+	}
+
+	//The upgrade windows are only read from config.toml when there is no registry
+	if cfg.UpgradesFile == "" {
+		cfg.ElChoco = r.window("el_choco")
+		cfg.Amazonas = r.window("amazonas")
+	}
+
+	if cfg.NodeRewards < 0 {
+		r.errorf("node_rewards: must not be negative")
+	}
+
+	if cfg.MaxUptimeRewards < 0 {
+		r.errorf("max_uptime_rewards: must not be negative")
+	}
+
+	if cfg.ProposerPoints < 0 {
+		r.errorf("proposer_points: must not be negative")
+	}
+
+	if len(r.errs) > 0 {
+		return nil, errors.New(strings.Join(r.errs, "; "))
+	}
+
+	return cfg, nil
+}
+
+// reader collects the errors of the keys it reads from the viper instance
+type reader struct {
+	v    *viper.Viper
+	errs []string
+}
+
+func (r *reader) errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func (r *reader) string(key string) string {
+	switch value := r.v.Get(key).(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		r.errorf("%s: expected a string, got %v", key, value)
+		return ""
+	}
+}
+
+func (r *reader) requiredString(key string) string {
+	if r.v.Get(key) == nil {
+		r.errorf("%s: is required", key)
+		return ""
+	}
+
+	value := r.string(key)
+	if _, ok := r.v.Get(key).(string); ok && value == "" {
+		r.errorf("%s: is required", key)
+	}
+
+	return value
+}
+
+func (r *reader) bool(key string) bool {
+	switch value := r.v.Get(key).(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	default:
+		r.errorf("%s: expected true or false, got %v", key, value)
+		return false
+	}
+}
+
+func (r *reader) requiredInt64(key string) int64 {
+	if r.v.Get(key) == nil {
+		r.errorf("%s: is required", key)
+		return 0
+	}
+
+	return r.int64(key)
+}
+
+func (r *reader) int64(key string) int64 {
+	if r.v.Get(key) == nil {
+		return 0
+	}
+
+	switch value := r.v.Get(key).(type) {
+	case int64:
+		return value
+	case int:
+		return int64(value)
+	case float64:
+		if value == math.Trunc(value) {
+			return int64(value)
+		}
+	}
+
+	r.errorf("%s: expected a whole number, got %v", key, r.v.Get(key))
+
+	return 0
+}
+
+// window - Reads the <name>_startblock, <name>_endblock and <name>_reward_points_per_block keys
+func (r *reader) window(name string) upgrades.Window {
+	window := upgrades.Window{
+		StartBlock:     r.requiredInt64(name + "_startblock"),
+		EndBlock:       r.requiredInt64(name + "_endblock"),
+		PointsPerBlock: r.requiredInt64(name + "_reward_points_per_block"),
+	}
+
+	if window.EndBlock < window.StartBlock {
+		r.errorf("%s: end block %d is before start block %d", name, window.EndBlock, window.StartBlock)
+	}
+
+	if window.PointsPerBlock < 0 {
+		r.errorf("%s_reward_points_per_block: must not be negative", name)
+	}
+
+	return window
+}
+
+// operators - Reads a list of operator addresses, every address must be a valid valoper bech32
+func (r *reader) operators(key string) []string {
+	var values []interface{}
+
+	switch value := r.v.Get(key).(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		values = value
+	case []string:
+		for _, s := range value {
+			values = append(values, s)
+		}
+	default:
+		r.errorf("%s: expected a list of operator addresses, got %v", key, value)
+		return nil
+	}
+
+	var list []string
+
+	for i, value := range values {
+		operator, ok := value.(string)
+		if !ok {
+			r.errorf("%s[%d]: expected an operator address, got %v", key, i, value)
+			continue
+		}
+
+		if err := address.ValidateBech32(operator, address.ValOperPrefix); err != nil {
+			r.errorf("%s[%d]: %v", key, i, err)
+			continue
+		}
+
+		list = append(list, operator)
+	}
+
+	return list
+}
+
+// names - Reads a list of names, every name must be one of the allowed ones
+func (r *reader) names(key string, allowed []string) []string {
+	var values []interface{}
+
+	switch value := r.v.Get(key).(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		values = value
+	case []string:
+		for _, s := range value {
+			values = append(values, s)
+		}
+	default:
+		r.errorf("%s: expected a list of names, got %v", key, value)
+		return nil
+	}
+
+	var list []string
+
+	for i, value := range values {
+		name, ok := value.(string)
+		if !ok || !contains(allowed, name) {
+			r.errorf("%s[%d]: expected one of %s, got %v", key, i, strings.Join(allowed, ", "), value)
+			continue
+		}
+
+		list = append(list, name)
+	}
+
+	return list
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
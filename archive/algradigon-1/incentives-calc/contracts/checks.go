@@ -0,0 +1,64 @@
+package contracts
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// checkCode - Checks that the code was uploaded by a validator with a source url and a builder
+// image with its tag, e.g. cosmwasm/rust-optimizer:0.8.0
+func checkCode(code Code) []string {
+	var issues []string
+
+	if code.Operator.OperatorAddr == "" {
+		issues = append(issues, fmt.Sprintf("creator %s is not a validator account", code.Creator))
+	}
+
+	if code.Source == "" {
+		issues = append(issues, "no source url")
+	} else if u, err := url.Parse(code.Source); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		issues = append(issues, fmt.Sprintf("source %q is not a url", code.Source))
+	}
+
+	if code.Builder == "" {
+		issues = append(issues, "no builder")
+	} else if i := strings.LastIndex(code.Builder, ":"); i < 0 || i == len(code.Builder)-1 {
+		issues = append(issues, fmt.Sprintf("builder %q has no tag", code.Builder))
+	}
+
+	return issues
+}
+
+// checkContract - Checks that the contract was instantiated by a validator and is named after it,
+// the label or the token name has to contain the moniker
+func checkContract(contract Contract) []string {
+	if contract.Operator.OperatorAddr == "" {
+		return []string{fmt.Sprintf("creator %s is not a validator account", contract.Creator)}
+	}
+
+	moniker := normalize(contract.Operator.Moniker)
+
+	if moniker == "" {
+		return []string{"the validator has no moniker"}
+	}
+
+	if !strings.Contains(normalize(contract.Label), moniker) && !strings.Contains(normalize(contract.Name), moniker) {
+		return []string{fmt.Sprintf("label %q is not named after the validator %q", contract.Label, contract.Operator.Moniker)}
+	}
+
+	return nil
+}
+
+// normalize - Lower cases the letters and digits and drops the rest, so "Vitwit_Validator" matches
+// "ERC20 vitwit validator"
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return -1
+	}, name)
+}
@@ -0,0 +1,148 @@
+package contracts
+
+import "fmt"
+
+// ERC20Rules - Phase-2 rules of the ERC20 contracts, see kontraua/challenges/phase-2/instructions.md.
+// The creator has to transfer tokens to at least MinRecipients other validator accounts
+type ERC20Rules struct {
+	MinRecipients int
+}
+
+var DefaultERC20Rules = ERC20Rules{MinRecipients: 5}
+
+// TransferMatch is a transfer of the creator to a validator account
+type TransferMatch struct {
+	TxHash    string   `json:"txHash"`
+	Height    int64    `json:"height"`
+	Recipient string   `json:"recipient"`
+	Operator  Operator `json:"operator"`
+}
+
+// AllowanceMatch is an approve of the creator followed by a transferFrom signed by its spender
+type AllowanceMatch struct {
+	ApproveTxHash      string `json:"approveTxHash"`
+	ApproveHeight      int64  `json:"approveHeight"`
+	Spender            string `json:"spender"`
+	TransferFromTxHash string `json:"transferFromTxHash"`
+	TransferFromHeight int64  `json:"transferFromHeight"`
+	TransferFromSigner string `json:"transferFromSigner"`
+}
+
+// ERC20Check is the outcome of the rules for a contract, the transfers are the first transfer to
+// every distinct validator
+type ERC20Check struct {
+	Contract      string          `json:"contract"`
+	Operator      Operator        `json:"operator"`
+	Transfers     []TransferMatch `json:"transfers"`
+	Recipients    int             `json:"recipients"`
+	TransfersPass bool            `json:"transfersPass"`
+	Allowance     *AllowanceMatch `json:"allowance"`
+	AllowancePass bool            `json:"allowancePass"`
+	Reasons       []string        `json:"reasons"`
+}
+
+// Operator - Returns the validator behind an account address
+func (idx *Index) Operator(account string) (Operator, bool) {
+	operator, ok := idx.operators[account]
+	return operator, ok
+}
+
+// CheckERC20 - Checks the rules for every ERC20 contract of the index in deployment order
+func (idx *Index) CheckERC20(rules ERC20Rules) []ERC20Check {
+	var checks []ERC20Check
+
+	for _, contract := range idx.Contracts {
+		if contract.Kind == KIND_ERC20 {
+			checks = append(checks, idx.checkERC20(contract, rules))
+		}
+	}
+
+	return checks
+}
+
+func (idx *Index) checkERC20(contract Contract, rules ERC20Rules) ERC20Check {
+	check := ERC20Check{Contract: contract.Address, Operator: contract.Operator}
+
+	executions := idx.ContractExecutions(contract.Address)
+
+	check.Transfers, _ = idx.MatchTransfers(contract.Creator, executions)
+	check.Recipients = len(check.Transfers)
+	check.TransfersPass = check.Recipients >= rules.MinRecipients
+
+	if !check.TransfersPass {
+		check.Reasons = append(check.Reasons, fmt.Sprintf("transfers to %d of %d validators", check.Recipients, rules.MinRecipients))
+	}
+
+	check.Allowance = FindAllowance(contract.Creator, executions)
+	check.AllowancePass = check.Allowance != nil
+
+	if !check.AllowancePass {
+		check.Reasons = append(check.Reasons, "no approve of the creator followed by a transferFrom of its spender")
+	}
+
+	return check
+}
+
+// MatchTransfers - Returns the first transfer of the creator to every distinct other validator, the
+// phase-2 transfer rule. The other transfers of the creator are returned by tx hash with the reason
+// they don't count. The executions are ordered by height
+func (idx *Index) MatchTransfers(creator string, executions []Execution) ([]TransferMatch, map[string]string) {
+	var matches []TransferMatch
+
+	skipped := make(map[string]string)
+	recipients := make(map[string]bool)
+	own, _ := idx.Operator(creator)
+
+	for _, e := range executions {
+		if e.Action != "transfer" || e.Sender != creator {
+			continue
+		}
+
+		recipient := e.Arg("recipient")
+		operator, ok := idx.Operator(recipient)
+
+		switch {
+		case !ok:
+			skipped[e.TxHash] = fmt.Sprintf("recipient %s is not a validator account", recipient)
+		case recipient == creator || (own.OperatorAddr != "" && operator.OperatorAddr == own.OperatorAddr):
+			skipped[e.TxHash] = "transferred to the creator's own validator"
+		case recipients[operator.OperatorAddr]:
+			skipped[e.TxHash] = fmt.Sprintf("validator %s already received a transfer", operator.OperatorAddr)
+		default:
+			recipients[operator.OperatorAddr] = true
+
+			matches = append(matches, TransferMatch{
+				TxHash: e.TxHash, Height: e.Height, Recipient: recipient, Operator: operator,
+			})
+		}
+	}
+
+	return matches, skipped
+}
+
+// FindAllowance - Finds the first approve of the owner followed by a transferFrom of the owner's
+// tokens signed by the spender of that approve, the phase-2 allowance rule. The executions are
+// ordered by height
+func FindAllowance(owner string, executions []Execution) *AllowanceMatch {
+	var approves []Execution
+
+	for _, e := range executions {
+		switch {
+		case e.Action == "approve" && e.Sender == owner:
+			approves = append(approves, e)
+		case e.Action == "transferfrom" && e.Sender != owner && e.Arg("owner") == owner:
+			for _, approve := range approves {
+				if approve.Arg("spender") != e.Sender {
+					continue
+				}
+
+				return &AllowanceMatch{
+					ApproveTxHash: approve.TxHash, ApproveHeight: approve.Height, Spender: approve.Arg("spender"),
+					TransferFromTxHash: e.TxHash, TransferFromHeight: e.Height, TransferFromSigner: e.Sender,
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package contracts
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// executeMsg - An execute tx of a single action with its fields
+func executeMsg(hash string, height int64, sender, contract, action string, args map[string]interface{}) db.Transaction {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	return wasmTx(hash, height, db.MSG_EXECUTE_CONTRACT, db.MsgValue{
+		Sender: sender, Contract: contract, Msg: map[string]interface{}{action: args},
+	})
+}
+
+// erc20Index - An ERC20 contract c1 of xrn:1acc1 and the validators xrn:1acc1 to xrn:1acc6
+func erc20Index(executes []db.Transaction) *Index {
+	var validators []db.Transaction
+
+	for i := 1; i <= 6; i++ {
+		validators = append(validators, wasmTx(fmt.Sprintf("V%d", i), 1, db.MSG_CREATE_VALIDATOR, db.MsgValue{
+			DelegatorAddress: fmt.Sprintf("xrn:1acc%d", i), ValidatorAddress: fmt.Sprintf("xrn:valoper%d", i),
+			Description: db.Description{Moniker: fmt.Sprintf("val%d", i)},
+		}))
+	}
+
+	idx := NewIndex()
+	idx.AddCreateValidatorTxs(validators)
+	idx.AddTxs([]db.Transaction{
+		wasmTx("I1", 10, db.MSG_INSTANTIATE_CONTRACT, db.MsgValue{
+			Sender: "xrn:1acc1", Label: "val1 token", InitMsg: map[string]interface{}{"symbol": "VAL", "decimals": float64(6)},
+		}, db.Attribute{Key: "contract_address", Value: "c1"}),
+	}, db.MSG_INSTANTIATE_CONTRACT)
+	idx.AddTxs(executes, db.MSG_EXECUTE_CONTRACT)
+	idx.finish()
+
+	return idx
+}
+
+func transfer(hash string, height int64, sender, recipient string) db.Transaction {
+	return executeMsg(hash, height, sender, "c1", "transfer", map[string]interface{}{"recipient": recipient, "amount": "1"})
+}
+
+func TestCheckERC20(t *testing.T) {
+	idx := erc20Index([]db.Transaction{
+		transfer("T1", 20, "xrn:1acc1", "xrn:1acc2"),
+		transfer("T2", 21, "xrn:1acc1", "xrn:1acc2"),
+		transfer("T3", 22, "xrn:1acc1", "xrn:1acc1"),
+		transfer("T4", 23, "xrn:1acc1", stranger),
+		transfer("T5", 24, "xrn:1acc2", "xrn:1acc6"),
+		transfer("T6", 25, "xrn:1acc1", "xrn:1acc3"),
+		transfer("T7", 26, "xrn:1acc1", "xrn:1acc4"),
+		transfer("T8", 27, "xrn:1acc1", "xrn:1acc5"),
+		executeMsg("F1", 28, "xrn:1acc2", "c1", "transfer_from", map[string]interface{}{"owner": "xrn:1acc1"}),
+		executeMsg("A1", 29, "xrn:1acc1", "c1", "approve", map[string]interface{}{"spender": "xrn:1acc3"}),
+		executeMsg("F2", 30, "xrn:1acc3", "c1", "transfer_from", map[string]interface{}{"owner": "xrn:1acc1"}),
+	})
+
+	tests := []struct {
+		minRecipients int
+		transfersPass bool
+		reasons       []string
+	}{
+		{minRecipients: 4, transfersPass: true},
+		{minRecipients: 5, reasons: []string{"transfers to 4 of 5 validators"}},
+	}
+
+	for _, tt := range tests {
+		checks := idx.CheckERC20(ERC20Rules{MinRecipients: tt.minRecipients})
+		if len(checks) != 1 {
+			t.Fatalf("checks = %+v, want one ERC20 contract", checks)
+		}
+
+		check := checks[0]
+
+		var transfers []string
+		for _, m := range check.Transfers {
+			transfers = append(transfers, m.TxHash)
+		}
+
+		//The first transfer to every other validator of the creator counts
+		if !reflect.DeepEqual(transfers, []string{"T1", "T6", "T7", "T8"}) || check.Recipients != 4 {
+			t.Errorf("transfers = %v, want T1, T6, T7 and T8", transfers)
+		}
+
+		if check.TransfersPass != tt.transfersPass || !reflect.DeepEqual(check.Reasons, tt.reasons) {
+			t.Errorf("min %d recipients: pass = %v reasons %q", tt.minRecipients, check.TransfersPass, check.Reasons)
+		}
+
+		if !check.AllowancePass || check.Allowance.ApproveTxHash != "A1" || check.Allowance.TransferFromTxHash != "F2" {
+			t.Errorf("allowance = %+v, want A1 then F2", check.Allowance)
+		}
+	}
+}
+
+func TestMatchTransfers(t *testing.T) {
+	executions := []Execution{
+		{TxHash: "T1", Height: 20, Sender: "xrn:1acc1", Action: "transfer", Args: map[string]interface{}{"recipient": "xrn:1acc2"}},
+		{TxHash: "T2", Height: 21, Sender: "xrn:1acc1", Action: "transfer", Args: map[string]interface{}{"recipient": "xrn:1acc2"}},
+		{TxHash: "T3", Height: 22, Sender: "xrn:1acc1", Action: "transfer", Args: map[string]interface{}{"recipient": "xrn:1acc1"}},
+		{TxHash: "T4", Height: 23, Sender: "xrn:1acc1", Action: "transfer", Args: map[string]interface{}{"recipient": stranger}},
+		{TxHash: "T5", Height: 24, Sender: "xrn:1acc2", Action: "transfer", Args: map[string]interface{}{"recipient": "xrn:1acc3"}},
+		{TxHash: "T6", Height: 25, Sender: "xrn:1acc1", Action: "transfer", Args: map[string]interface{}{"recipient": "xrn:1acc3"}},
+	}
+
+	matches, skipped := erc20Index(nil).MatchTransfers("xrn:1acc1", executions)
+
+	var transfers []string
+	for _, m := range matches {
+		transfers = append(transfers, m.TxHash+" "+m.Operator.OperatorAddr)
+	}
+
+	if !reflect.DeepEqual(transfers, []string{"T1 xrn:valoper2", "T6 xrn:valoper3"}) {
+		t.Errorf("transfers = %q, want T1 and T6", transfers)
+	}
+
+	//Transfers of other senders are not the creator's, they are neither matched nor skipped
+	want := map[string]string{
+		"T2": "validator xrn:valoper2 already received a transfer",
+		"T3": "transferred to the creator's own validator",
+		"T4": "recipient xrn:1stranger is not a validator account",
+	}
+
+	if !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %v, want %v", skipped, want)
+	}
+}
+
+func TestFindAllowance(t *testing.T) {
+	const owner = "xrn:1owner"
+
+	approve := Execution{TxHash: "A", Height: 2, Sender: owner, Action: "approve", Args: map[string]interface{}{"spender": "xrn:1spender"}}
+
+	transferFrom := func(hash string, height int64, signer, from string) Execution {
+		return Execution{TxHash: hash, Height: height, Sender: signer, Action: "transferfrom", Args: map[string]interface{}{"owner": from}}
+	}
+
+	tests := []struct {
+		name       string
+		executions []Execution
+		want       string
+	}{
+		{name: "approve then transferFrom", executions: []Execution{approve, transferFrom("F", 3, "xrn:1spender", owner)}, want: "A/F"},
+		{name: "transferFrom before the approve", executions: []Execution{transferFrom("F", 1, "xrn:1spender", owner), approve}},
+		{name: "transferFrom signed by the owner", executions: []Execution{approve, transferFrom("F", 3, owner, owner)}},
+		{name: "transferFrom of another owner", executions: []Execution{approve, transferFrom("F", 3, "xrn:1spender", "xrn:1other")}},
+		{name: "transferFrom signed by another than the spender", executions: []Execution{approve, transferFrom("F", 3, "xrn:1other", owner)}},
+		{name: "no approve", executions: []Execution{transferFrom("F", 3, "xrn:1spender", owner)}},
+	}
+
+	for _, tt := range tests {
+		got := ""
+		if m := FindAllowance(owner, tt.executions); m != nil {
+			got = m.ApproveTxHash + "/" + m.TransferFromTxHash
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: allowance = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
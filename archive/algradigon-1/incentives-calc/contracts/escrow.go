@@ -0,0 +1,491 @@
+package contracts
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/genesis"
+)
+
+// EscrowRules - Points of the phase-4 escrow challenge, see kontraua/PLAN.md. A validator earns the
+// deployment points for an escrow instantiated by its account and the execution points for a valid
+// approve or refund of it, the rank bonus goes to the first validators completing both by height
+type EscrowRules struct {
+	Deployment int64
+	Execution  int64
+	RankBonus  []int64
+}
+
+// DEFAULT_RANK_BONUS - The phase-4 bonus of kontraua/PLAN.md: "A total of 1000 bonus points are shared
+// among first 20 validators to complete these tasks. First 5 - 100 each, 6 to 10: 50 each, 11 to 20:
+// 25 each", as points x validators
+const DEFAULT_RANK_BONUS = "100x5,50x5,25x10"
+
+// DefaultEscrowRules - 50 points for deploying, 50 for executing and the default rank bonus
+var DefaultEscrowRules = EscrowRules{
+	Deployment: 50,
+	Execution:  50,
+	RankBonus:  mustParseRankBonus(DEFAULT_RANK_BONUS),
+}
+
+// ParseRankBonus - Parses a rank bonus of comma separated points x validators tiers, such as
+// 100x5,50x5 for 100 points to the first 5 and 50 points to the next 5
+func ParseRankBonus(spec string) ([]int64, error) {
+	var bonus []int64
+
+	if strings.TrimSpace(spec) == "" {
+		return bonus, nil
+	}
+
+	for _, tier := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(tier), "x")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("rank bonus tier %q is not <points>x<validators>", tier)
+		}
+
+		points, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || points < 0 {
+			return nil, fmt.Errorf("rank bonus tier %q has invalid points", tier)
+		}
+
+		count, err := strconv.Atoi(parts[1])
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("rank bonus tier %q has an invalid number of validators", tier)
+		}
+
+		for i := 0; i < count; i++ {
+			bonus = append(bonus, points)
+		}
+	}
+
+	return bonus, nil
+}
+
+func mustParseRankBonus(spec string) []int64 {
+	bonus, err := ParseRankBonus(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	return bonus
+}
+
+// states of an escrow, an approve without quantity or of the whole balance settles it
+const (
+	ESCROW_OPEN     = "open"
+	ESCROW_APPROVED = "approved"
+	ESCROW_REFUNDED = "refunded"
+)
+
+// EscrowEvent is an approve or refund of an escrow with the state it moved the escrow to, invalid
+// transitions have the issues set and leave the state unchanged
+type EscrowEvent struct {
+	TxHash string    `json:"txHash"`
+	Height int64     `json:"height"`
+	Action string    `json:"action"`
+	Sender string    `json:"sender"`
+	Amount []db.Coin `json:"amount"`
+	State  string    `json:"state"`
+	Issues []string  `json:"issues"`
+}
+
+// Valid - Returns whether the event is an allowed transition of the escrow
+func (e EscrowEvent) Valid() bool {
+	return len(e.Issues) == 0
+}
+
+// EscrowLifecycle is an escrow rebuilt from its instantiate and execute messages. An escrow expires
+// after its end height or end time (unix seconds), zero means unset. Only the arbiter can approve
+// before it expires, anyone can refund the balance to the source after it expired
+type EscrowLifecycle struct {
+	Contract  string        `json:"contract"`
+	Operator  Operator      `json:"operator"`
+	Source    string        `json:"source"`
+	Arbiter   string        `json:"arbiter"`
+	Recipient string        `json:"recipient"`
+	EndHeight int64         `json:"endHeight"`
+	EndTime   int64         `json:"endTime"`
+	TxHash    string        `json:"txHash"`
+	Height    int64         `json:"height"`
+	Funds     []db.Coin     `json:"funds"`
+	Balance   []db.Coin     `json:"balance"`
+	Events    []EscrowEvent `json:"events"`
+	State     string        `json:"state"`
+	Deployed  bool          `json:"deployed"`
+	Executed  bool          `json:"executed"`
+	Completed int64         `json:"completed,omitempty"`
+	Issues    []string      `json:"issues"`
+}
+
+// Event - Returns the event of a tx hash
+func (l EscrowLifecycle) Event(hash string) (EscrowEvent, bool) {
+	for _, e := range l.Events {
+		if strings.EqualFold(e.TxHash, hash) {
+			return e, true
+		}
+	}
+
+	return EscrowEvent{}, false
+}
+
+// EscrowScore is the phase-4 outcome of a validator, scored on its escrow completed first
+type EscrowScore struct {
+	Operator   Operator `json:"operator"`
+	Contract   string   `json:"contract"`
+	Deployment int64    `json:"deployment"`
+	Execution  int64    `json:"execution"`
+	Completed  int64    `json:"completed,omitempty"`
+	Rank       int      `json:"rank,omitempty"`
+	Bonus      int64    `json:"bonus"`
+	Total      int64    `json:"total"`
+}
+
+// Escrow - Returns the lifecycle of an escrow contract of the index
+func (idx *Index) Escrow(contract string) (EscrowLifecycle, bool) {
+	for _, c := range idx.Contracts {
+		if c.Kind == KIND_ESCROW && c.Address == contract {
+			return idx.escrowLifecycle(c), true
+		}
+	}
+
+	return EscrowLifecycle{}, false
+}
+
+// Escrows - Rebuilds the lifecycle of every escrow contract of the index in deployment order
+func (idx *Index) Escrows() []EscrowLifecycle {
+	var escrows []EscrowLifecycle
+
+	for _, contract := range idx.Contracts {
+		if contract.Kind == KIND_ESCROW {
+			escrows = append(escrows, idx.escrowLifecycle(contract))
+		}
+	}
+
+	return escrows
+}
+
+func (idx *Index) escrowLifecycle(contract Contract) EscrowLifecycle {
+	l := EscrowLifecycle{
+		Contract: contract.Address, Operator: contract.Operator, Source: contract.Creator,
+		TxHash: contract.TxHash, Height: contract.Height, Funds: contract.initFunds, State: ESCROW_OPEN,
+	}
+
+	l.Arbiter, _ = contract.initMsg["arbiter"].(string)
+	l.Recipient, _ = contract.initMsg["recipient"].(string)
+	l.EndHeight = intValue(contract.initMsg["end_height"])
+	l.EndTime = intValue(contract.initMsg["end_time"])
+
+	if contract.Operator.OperatorAddr == "" {
+		l.Issues = append(l.Issues, fmt.Sprintf("creator %s is not a validator account", contract.Creator))
+	}
+
+	if l.Arbiter == "" || l.Recipient == "" {
+		l.Issues = append(l.Issues, "no arbiter or recipient")
+	}
+
+	l.Deployed = len(l.Issues) == 0
+
+	if l.EndHeight == 0 && l.EndTime == 0 {
+		l.Issues = append(l.Issues, "no end height or time, the escrow can not be refunded")
+	}
+
+	balance := newCoins(contract.initFunds)
+
+	if balance.empty() {
+		l.Issues = append(l.Issues, "instantiated without funds")
+	}
+
+	for _, e := range idx.ContractExecutions(contract.Address) {
+		event := EscrowEvent{TxHash: e.TxHash, Height: e.Height, Action: e.Action, Sender: e.Sender}
+
+		expired := (l.EndHeight > 0 && e.Height > l.EndHeight) || (l.EndTime > 0 && e.Time.Unix() > l.EndTime)
+
+		switch e.Action {
+		case "approve":
+			amount := balance
+			if quantity, ok := e.Args["quantity"]; ok && quantity != nil {
+				amount = newCoins(coinsValue(quantity))
+			}
+
+			event.Amount = amount.list()
+
+			if e.Sender != l.Arbiter {
+				event.Issues = append(event.Issues, fmt.Sprintf("approved by %s, only the arbiter %s can approve", e.Sender, l.Arbiter))
+			}
+
+			if expired {
+				event.Issues = append(event.Issues, "approved after the escrow expired")
+			}
+
+			if balance.empty() {
+				event.Issues = append(event.Issues, "approved an escrow without balance")
+			} else if !balance.covers(amount) {
+				event.Issues = append(event.Issues, "approved more than the escrow balance")
+			}
+
+			if event.Valid() {
+				balance.sub(amount)
+				if balance.empty() {
+					l.State = ESCROW_APPROVED
+				}
+			}
+		case "refund":
+			event.Amount = balance.list()
+
+			if !expired {
+				event.Issues = append(event.Issues, "refunded before the escrow expired")
+			}
+
+			if balance.empty() {
+				event.Issues = append(event.Issues, "refunded an escrow without balance")
+			}
+
+			if event.Valid() {
+				balance = coins{}
+				l.State = ESCROW_REFUNDED
+			}
+		default:
+			event.Issues = append(event.Issues, fmt.Sprintf("unknown escrow action %q", e.Action))
+		}
+
+		if event.Valid() && l.Completed == 0 {
+			l.Executed, l.Completed = true, e.Height
+		}
+
+		event.State = l.State
+		l.Events = append(l.Events, event)
+	}
+
+	l.Balance = balance.list()
+
+	return l
+}
+
+// Score - Scores an escrow, the deployment points when it was deployed by a validator and the
+// execution points when it had a valid approve or refund, completed at the height of the first one.
+// When hashes are given only the events of those txs count, as for a phase-4 submission
+func (r EscrowRules) Score(l EscrowLifecycle, hashes []string) EscrowScore {
+	score := EscrowScore{Operator: l.Operator, Contract: l.Contract}
+
+	if l.Deployed {
+		score.Deployment = r.Deployment
+
+		for _, e := range l.Events {
+			if !e.Valid() || (hashes != nil && !containsHash(hashes, e.TxHash)) {
+				continue
+			}
+
+			score.Execution, score.Completed = r.Execution, e.Height
+
+			break
+		}
+	}
+
+	score.Total = score.Deployment + score.Execution
+
+	return score
+}
+
+// Rank - Keeps the best score of every validator, its escrow completed first or deployed first when
+// none was executed, and ranks the validators with a completed escrow by completion height. Scores
+// without points are dropped
+func (r EscrowRules) Rank(scores []EscrowScore) []EscrowScore {
+	best := make(map[string]int)
+
+	var ranked []EscrowScore
+
+	for _, score := range scores {
+		if score.Deployment == 0 && score.Execution == 0 {
+			continue
+		}
+
+		i, ok := best[score.Operator.OperatorAddr]
+		if !ok {
+			best[score.Operator.OperatorAddr] = len(ranked)
+			ranked = append(ranked, score)
+
+			continue
+		}
+
+		current := ranked[i]
+
+		if score.Completed > 0 && (current.Completed == 0 || score.Completed < current.Completed) {
+			ranked[i] = score
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if (ranked[i].Completed > 0) != (ranked[j].Completed > 0) {
+			return ranked[i].Completed > 0
+		}
+
+		return ranked[i].Completed < ranked[j].Completed
+	})
+
+	for i := range ranked {
+		ranked[i].Rank, ranked[i].Bonus = 0, 0
+
+		if ranked[i].Completed > 0 {
+			ranked[i].Rank = i + 1
+
+			if i < len(r.RankBonus) {
+				ranked[i].Bonus = r.RankBonus[i]
+			}
+		}
+
+		ranked[i].Total = ranked[i].Deployment + ranked[i].Execution + ranked[i].Bonus
+	}
+
+	return ranked
+}
+
+// ScoreEscrows - Scores and ranks every validator on its escrows
+func ScoreEscrows(escrows []EscrowLifecycle, rules EscrowRules) []EscrowScore {
+	var scores []EscrowScore
+
+	for _, l := range escrows {
+		scores = append(scores, rules.Score(l, nil))
+	}
+
+	return rules.Rank(scores)
+}
+
+// CheckEvent - Checks that the tx is a valid event of the escrow with the given action
+func (l EscrowLifecycle) CheckEvent(hash, action string) []string {
+	event, ok := l.Event(hash)
+
+	switch {
+	case !ok:
+		return []string{fmt.Sprintf("%s %s is not an execution of the escrow", action, hash)}
+	case event.Action != action:
+		return []string{fmt.Sprintf("%s %s is a %s", action, hash, event.Action)}
+	case !event.Valid():
+		return []string{fmt.Sprintf("%s %s is invalid: %s", action, hash, strings.Join(event.Issues, "; "))}
+	}
+
+	return nil
+}
+
+// CheckEscrowSubmission - Checks the approve and refund hashes of a phase-4 submission against the
+// lifecycle of its escrow
+func CheckEscrowSubmission(l EscrowLifecycle, operator string, approve, refund []string) []string {
+	var issues []string
+
+	if l.Operator.OperatorAddr != operator {
+		issues = append(issues, fmt.Sprintf("escrow %s was not deployed by %s", l.Contract, operator))
+	}
+
+	for _, hash := range approve {
+		issues = append(issues, l.CheckEvent(hash, "approve")...)
+	}
+
+	for _, hash := range refund {
+		issues = append(issues, l.CheckEvent(hash, "refund")...)
+	}
+
+	return issues
+}
+
+func containsHash(hashes []string, hash string) bool {
+	for _, h := range hashes {
+		if strings.EqualFold(h, hash) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// coins is a balance by denom
+type coins map[string]*big.Int
+
+// newCoins - Sums the coins by denom, coins after an invalid amount are dropped
+func newCoins(list []db.Coin) coins {
+	c := coins{}
+	_ = genesis.SumCoins(c, list)
+
+	return c
+}
+
+func (c coins) empty() bool {
+	for _, amount := range c {
+		if amount.Sign() > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c coins) covers(other coins) bool {
+	for denom, amount := range other {
+		if c[denom] == nil || c[denom].Cmp(amount) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c coins) sub(other coins) {
+	for denom, amount := range other {
+		if c[denom] != nil {
+			c[denom].Sub(c[denom], amount)
+		}
+	}
+}
+
+// list - Returns the non zero coins ordered by denom
+func (c coins) list() []db.Coin {
+	var list []db.Coin
+
+	for denom, amount := range c {
+		if amount.Sign() > 0 {
+			list = append(list, db.Coin{Denom: denom, Amount: amount.String()})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Denom < list[j].Denom })
+
+	return list
+}
+
+// coinsValue - Reads a list of coins of a message, e.g. the quantity of an approve
+func coinsValue(value interface{}) []db.Coin {
+	items, _ := value.([]interface{})
+
+	var list []db.Coin
+
+	for _, item := range items {
+		fields, _ := item.(map[string]interface{})
+		denom, _ := fields["denom"].(string)
+		amount, _ := fields["amount"].(string)
+
+		list = append(list, db.Coin{Denom: denom, Amount: amount})
+	}
+
+	return list
+}
+
+// intValue - Reads a number of a message, decoded from JSON or BSON, or given as a string
+func intValue(value interface{}) int64 {
+	switch v := value.(type) {
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+
+	return 0
+}
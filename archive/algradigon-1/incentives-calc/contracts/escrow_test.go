@@ -0,0 +1,322 @@
+package contracts
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+const (
+	arbiter   = "xrn:1arbiter"
+	recipient = "xrn:1recipient"
+)
+
+var escrowStart = time.Date(2020, 4, 20, 10, 0, 0, 0, time.UTC)
+
+// escrowTx - A tx of a single message at the height, a minute per height after the start
+func escrowTx(hash string, height int64, msgType string, value db.MsgValue) db.Transaction {
+	return db.Transaction{
+		Hash: hash, Height: height, Time: escrowStart.Add(time.Duration(height) * time.Minute),
+		Msgs: []db.Msg{{Type: msgType, Value: value}},
+	}
+}
+
+// instantiateEscrow - Instantiates an escrow of 10utree expiring after height 100, the contract
+// address is the one emitted by the chain
+func instantiateEscrow(hash string, height int64, creator, contract string) db.Transaction {
+	tx := escrowTx(hash, height, db.MSG_INSTANTIATE_CONTRACT, db.MsgValue{
+		Sender: creator, CodeID: "1",
+		InitMsg: map[string]interface{}{
+			"arbiter": arbiter, "recipient": recipient, "end_height": float64(100),
+		},
+		InitFunds: []db.Coin{{Denom: "utree", Amount: "10"}},
+	})
+
+	tx.Logs = []db.TxLog{{Events: []db.Event{{
+		Type: "message", Attributes: []db.Attribute{{Key: "contract_address", Value: contract}},
+	}}}}
+
+	return tx
+}
+
+func executeEscrow(hash string, height int64, sender, contract, action string, args map[string]interface{}) db.Transaction {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	return escrowTx(hash, height, db.MSG_EXECUTE_CONTRACT, db.MsgValue{
+		Sender: sender, Contract: contract, Msg: map[string]interface{}{action: args},
+	})
+}
+
+func quantity(amount string) map[string]interface{} {
+	return map[string]interface{}{
+		"quantity": []interface{}{map[string]interface{}{"denom": "utree", "amount": amount}},
+	}
+}
+
+// escrowIndex - Indexes the escrow txs with the validators val1 to val5, their accounts are
+// xrn:1acc1 to xrn:1acc5
+func escrowIndex(instantiates, executes []db.Transaction) *Index {
+	var validators []db.Transaction
+
+	for i := 1; i <= 5; i++ {
+		validators = append(validators, escrowTx(fmt.Sprintf("V%d", i), 1, db.MSG_CREATE_VALIDATOR, db.MsgValue{
+			DelegatorAddress: fmt.Sprintf("xrn:1acc%d", i),
+			ValidatorAddress: fmt.Sprintf("xrn:valoper%d", i),
+			Description:      db.Description{Moniker: fmt.Sprintf("val%d", i)},
+		}))
+	}
+
+	idx := NewIndex()
+	idx.AddCreateValidatorTxs(validators)
+	idx.AddTxs(instantiates, db.MSG_INSTANTIATE_CONTRACT)
+	idx.AddTxs(executes, db.MSG_EXECUTE_CONTRACT)
+	idx.finish()
+
+	return idx
+}
+
+type transition struct {
+	Hash  string
+	State string
+	Valid bool
+}
+
+func TestEscrowLifecycle(t *testing.T) {
+	tests := []struct {
+		name        string
+		creator     string
+		executes    []db.Transaction
+		transitions []transition
+		state       string
+		balance     string
+		deployed    bool
+		completed   int64
+	}{
+		{
+			name:     "deployed only",
+			creator:  "xrn:1acc1",
+			state:    ESCROW_OPEN,
+			balance:  "10utree",
+			deployed: true,
+		},
+		{
+			name:    "approved by the arbiter",
+			creator: "xrn:1acc1",
+			executes: []db.Transaction{
+				executeEscrow("A1", 20, stranger, "c1", "approve", nil),
+				executeEscrow("A2", 30, arbiter, "c1", "approve", nil),
+			},
+			transitions: []transition{{"A1", ESCROW_OPEN, false}, {"A2", ESCROW_APPROVED, true}},
+			state:       ESCROW_APPROVED,
+			deployed:    true,
+			completed:   30,
+		},
+		{
+			name:    "approved in parts",
+			creator: "xrn:1acc1",
+			executes: []db.Transaction{
+				executeEscrow("A1", 20, arbiter, "c1", "approve", quantity("4")),
+				executeEscrow("A2", 21, arbiter, "c1", "approve", quantity("7")),
+				executeEscrow("A3", 22, arbiter, "c1", "approve", quantity("6")),
+				executeEscrow("A4", 23, arbiter, "c1", "approve", nil),
+			},
+			transitions: []transition{
+				{"A1", ESCROW_OPEN, true}, {"A2", ESCROW_OPEN, false}, {"A3", ESCROW_APPROVED, true},
+				{"A4", ESCROW_APPROVED, false},
+			},
+			state:     ESCROW_APPROVED,
+			deployed:  true,
+			completed: 20,
+		},
+		{
+			name:    "refunded after expiring",
+			creator: "xrn:1acc1",
+			executes: []db.Transaction{
+				executeEscrow("R1", 50, stranger, "c1", "refund", nil),
+				executeEscrow("A1", 101, arbiter, "c1", "approve", nil),
+				executeEscrow("R2", 102, stranger, "c1", "refund", nil),
+				executeEscrow("R3", 103, stranger, "c1", "refund", nil),
+				executeEscrow("X1", 104, stranger, "c1", "destroy", nil),
+			},
+			transitions: []transition{
+				{"R1", ESCROW_OPEN, false}, {"A1", ESCROW_OPEN, false}, {"R2", ESCROW_REFUNDED, true},
+				{"R3", ESCROW_REFUNDED, false}, {"X1", ESCROW_REFUNDED, false},
+			},
+			state:     ESCROW_REFUNDED,
+			deployed:  true,
+			completed: 102,
+		},
+		{
+			name:     "created by an account without a validator",
+			creator:  stranger,
+			executes: []db.Transaction{executeEscrow("A1", 20, arbiter, "c1", "approve", nil)},
+			transitions: []transition{
+				{"A1", ESCROW_APPROVED, true},
+			},
+			state:     ESCROW_APPROVED,
+			completed: 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := escrowIndex([]db.Transaction{instantiateEscrow("I1", 10, tt.creator, "c1")}, tt.executes)
+
+			l, ok := idx.Escrow("c1")
+			if !ok {
+				t.Fatal("escrow c1 not indexed")
+			}
+
+			var transitions []transition
+			for _, e := range l.Events {
+				transitions = append(transitions, transition{e.TxHash, e.State, e.Valid()})
+			}
+
+			if !reflect.DeepEqual(transitions, tt.transitions) {
+				t.Errorf("transitions = %+v, want %+v", transitions, tt.transitions)
+			}
+
+			if l.State != tt.state || coinsString(l.Balance) != tt.balance {
+				t.Errorf("state = %s with %s, want %s with %s", l.State, coinsString(l.Balance), tt.state, tt.balance)
+			}
+
+			if l.Deployed != tt.deployed || l.Completed != tt.completed {
+				t.Errorf("deployed = %v completed = %d, want %v %d", l.Deployed, l.Completed, tt.deployed, tt.completed)
+			}
+		})
+	}
+}
+
+func coinsString(list []db.Coin) string {
+	s := ""
+	for _, c := range list {
+		s += c.Amount + c.Denom
+	}
+
+	return s
+}
+
+func TestEscrowSubmission(t *testing.T) {
+	idx := escrowIndex([]db.Transaction{instantiateEscrow("I1", 10, "xrn:1acc1", "c1")}, []db.Transaction{
+		executeEscrow("A1", 20, stranger, "c1", "approve", nil),
+		executeEscrow("A2", 30, arbiter, "c1", "approve", nil),
+	})
+
+	l, _ := idx.Escrow("c1")
+
+	if issues := CheckEscrowSubmission(l, "xrn:valoper1", []string{"a2"}, nil); len(issues) != 0 {
+		t.Errorf("valid submission has issues %v", issues)
+	}
+
+	issues := CheckEscrowSubmission(l, "xrn:valoper2", []string{"A1", "Z9"}, []string{"A2"})
+
+	want := []string{
+		"escrow c1 was not deployed by xrn:valoper2",
+		"approve A1 is invalid: approved by xrn:1stranger, only the arbiter xrn:1arbiter can approve",
+		"approve Z9 is not an execution of the escrow",
+		"refund A2 is a approve",
+	}
+
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("issues =\n%q\nwant\n%q", issues, want)
+	}
+
+	//A submission only scores the execution of its own valid txs
+	rules := DefaultEscrowRules
+
+	if score := rules.Score(l, []string{"A1"}); score.Deployment != 50 || score.Execution != 0 {
+		t.Errorf("score of an invalid approve = %+v", score)
+	}
+
+	if score := rules.Score(l, []string{}); score.Execution != 0 {
+		t.Errorf("score without txs = %+v", score)
+	}
+
+	if score := rules.Score(l, []string{"A2"}); score.Total != 100 || score.Completed != 30 {
+		t.Errorf("score of a valid approve = %+v", score)
+	}
+}
+
+func TestScoreEscrows(t *testing.T) {
+	idx := escrowIndex(
+		[]db.Transaction{
+			instantiateEscrow("I1", 10, "xrn:1acc1", "c1"),
+			instantiateEscrow("I2", 11, "xrn:1acc2", "c2"),
+			instantiateEscrow("I3", 12, "xrn:1acc3", "c3"),
+			instantiateEscrow("I4", 13, "xrn:1acc4", "c4"),
+			instantiateEscrow("I5", 14, "xrn:1acc1", "c5"),
+			instantiateEscrow("I6", 15, stranger, "c6"),
+		},
+		[]db.Transaction{
+			executeEscrow("E1", 40, arbiter, "c1", "approve", nil),
+			executeEscrow("E2", 30, arbiter, "c2", "approve", nil),
+			executeEscrow("E3", 35, arbiter, "c3", "approve", nil),
+			executeEscrow("E5", 20, arbiter, "c5", "approve", nil),
+			executeEscrow("E6", 10, arbiter, "c6", "approve", nil),
+		},
+	)
+
+	rules := DefaultEscrowRules
+	rules.RankBonus = []int64{100, 50}
+
+	type ranked struct {
+		Contract string
+		Rank     int
+		Bonus    int64
+		Total    int64
+	}
+
+	var got []ranked
+	for _, s := range ScoreEscrows(idx.Escrows(), rules) {
+		got = append(got, ranked{s.Contract, s.Rank, s.Bonus, s.Total})
+	}
+
+	//val1 is ranked on c5 completed first, val4 did not execute and the stranger is not a validator
+	want := []ranked{{"c5", 1, 100, 200}, {"c2", 2, 50, 150}, {"c3", 3, 0, 100}, {"c4", 0, 0, 50}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scores = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseRankBonus(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []int64
+		wantErr bool
+	}{
+		{spec: "100x2, 50x1", want: []int64{100, 100, 50}},
+		{spec: ""},
+		{spec: "100", wantErr: true},
+		{spec: "ax2", wantErr: true},
+		{spec: "100x0", wantErr: true},
+		{spec: "-5x1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRankBonus(tt.spec)
+
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRankBonus(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRankBonus(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	//kontraua/PLAN.md shares 1000 points among the first 20 validators
+	total := int64(0)
+	for _, bonus := range DefaultEscrowRules.RankBonus {
+		total += bonus
+	}
+
+	if len(DefaultEscrowRules.RankBonus) != 20 || total != 1000 {
+		t.Errorf("default rank bonus is %d points for %d validators", total, len(DefaultEscrowRules.RankBonus))
+	}
+}
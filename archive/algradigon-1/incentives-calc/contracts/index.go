@@ -0,0 +1,337 @@
+package contracts
+
+import (
+	"sort"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// TxQuery is used for reading the wasm txs, both the database and a tx dump satisfy it
+type TxQuery interface {
+	QueryTxsByMsgType(msgType string) ([]db.Transaction, error)
+}
+
+// Operator is the validator behind an account address
+type Operator struct {
+	OperatorAddr string `json:"operatorAddr"`
+	Moniker      string `json:"moniker"`
+}
+
+// Code is an uploaded contract code, the order is its rank in upload order starting at 1
+type Code struct {
+	CodeID   string    `json:"codeId"`
+	Creator  string    `json:"creator"`
+	Operator Operator  `json:"operator"`
+	TxHash   string    `json:"txHash"`
+	Height   int64     `json:"height"`
+	Time     time.Time `json:"time"`
+	Source   string    `json:"source"`
+	Builder  string    `json:"builder"`
+	Order    int       `json:"order"`
+	Issues   []string  `json:"issues"`
+}
+
+// kinds of contracts, told apart by their init message
+const (
+	KIND_ERC20  = "erc20"
+	KIND_ESCROW = "escrow"
+)
+
+// Contract is an instantiated contract with the executions of its messages, the order is its
+// rank in instantiation order starting at 1
+type Contract struct {
+	Address    string           `json:"address"`
+	CodeID     string           `json:"codeId"`
+	Kind       string           `json:"kind,omitempty"`
+	Creator    string           `json:"creator"`
+	Operator   Operator         `json:"operator"`
+	Label      string           `json:"label"`
+	Name       string           `json:"name,omitempty"`
+	TxHash     string           `json:"txHash"`
+	Height     int64            `json:"height"`
+	Time       time.Time        `json:"time"`
+	Order      int              `json:"order"`
+	Executions int64            `json:"executions"`
+	Executors  int              `json:"executors"`
+	Actions    map[string]int64 `json:"actions"`
+	Issues     []string         `json:"issues"`
+
+	initMsg   map[string]interface{}
+	initFunds []db.Coin
+	executors map[string]bool
+}
+
+// Execution is an execute message of an indexed contract, the action is the normalized message
+// name and args its fields
+type Execution struct {
+	Contract string                 `json:"contract"`
+	TxHash   string                 `json:"txHash"`
+	Height   int64                  `json:"height"`
+	Time     time.Time              `json:"time"`
+	Sender   string                 `json:"sender"`
+	Action   string                 `json:"action"`
+	Args     map[string]interface{} `json:"args"`
+}
+
+// Arg - Returns a string field of the message, such as the recipient of a transfer
+func (e Execution) Arg(name string) string {
+	value, _ := e.Args[name].(string)
+	return value
+}
+
+// Index links the uploaded codes and instantiated contracts to the validators which created them,
+// only successful txs are indexed
+type Index struct {
+	Codes      []Code      `json:"codes"`
+	Contracts  []Contract  `json:"contracts"`
+	Executions []Execution `json:"executions"`
+
+	operators map[string]Operator
+}
+
+// NewIndex - Creates an empty index, add the operators before the txs
+func NewIndex() *Index {
+	return &Index{operators: make(map[string]Operator)}
+}
+
+// AddValidators - Adds the operators of the validators collection by their account address
+func (idx *Index) AddValidators(validators []db.Validator) {
+	for _, v := range validators {
+		if acc, err := address.ValOperToAccount(v.OperatorAddress); err == nil {
+			idx.operators[acc] = Operator{OperatorAddr: v.OperatorAddress, Moniker: v.Description.Moniker}
+		}
+	}
+}
+
+// AddCreateValidatorTxs - Adds the operators of create validator txs by their delegator address,
+// operators already added keep their moniker
+func (idx *Index) AddCreateValidatorTxs(txs []db.Transaction) {
+	for _, tx := range txs {
+		for _, msg := range tx.Msgs {
+			if msg.Type != db.MSG_CREATE_VALIDATOR || msg.Value.DelegatorAddress == "" {
+				continue
+			}
+
+			if _, ok := idx.operators[msg.Value.DelegatorAddress]; !ok {
+				idx.operators[msg.Value.DelegatorAddress] = Operator{
+					OperatorAddr: msg.Value.ValidatorAddress, Moniker: msg.Value.Description.Moniker,
+				}
+			}
+		}
+	}
+}
+
+// Load - Indexes the store code, instantiate and execute txs of the source
+func (idx *Index) Load(txs TxQuery) error {
+	validators, err := txs.QueryTxsByMsgType(db.MSG_CREATE_VALIDATOR)
+	if err != nil {
+		return err
+	}
+
+	idx.AddCreateValidatorTxs(validators)
+
+	for _, msgType := range []string{db.MSG_STORE_CODE, db.MSG_INSTANTIATE_CONTRACT, db.MSG_EXECUTE_CONTRACT} {
+		result, err := txs.QueryTxsByMsgType(msgType)
+		if err != nil {
+			return err
+		}
+
+		idx.AddTxs(result, msgType)
+	}
+
+	idx.finish()
+
+	return nil
+}
+
+// AddTxs - Indexes the messages of a type of the txs, code ids and contract addresses are taken
+// from the events as they are assigned by the chain
+func (idx *Index) AddTxs(txs []db.Transaction, msgType string) {
+	for _, tx := range txs {
+		if tx.Code != 0 {
+			continue
+		}
+
+		//Executions are read like the tx proofs of the challenges, with the same sender rule
+		if msgType == db.MSG_EXECUTE_CONTRACT {
+			for _, execution := range Executions(tx) {
+				idx.addExecution(execution)
+			}
+
+			continue
+		}
+
+		for _, msg := range tx.Msgs {
+			if msg.Type != msgType {
+				continue
+			}
+
+			switch msgType {
+			case db.MSG_STORE_CODE:
+				idx.Codes = append(idx.Codes, Code{
+					CodeID: tx.EventValue("message", "code_id"), Creator: msg.Value.Sender,
+					Operator: idx.operators[msg.Value.Sender], TxHash: tx.Hash, Height: tx.Height, Time: tx.Time,
+					Source: msg.Value.Source, Builder: msg.Value.Builder,
+				})
+			case db.MSG_INSTANTIATE_CONTRACT:
+				name, _ := msg.Value.InitMsg["name"].(string)
+
+				idx.Contracts = append(idx.Contracts, Contract{
+					Address: tx.EventValue("message", "contract_address"), CodeID: msg.Value.CodeID,
+					Creator: msg.Value.Sender, Operator: idx.operators[msg.Value.Sender], Label: msg.Value.Label,
+					Name: name, TxHash: tx.Hash, Height: tx.Height, Time: tx.Time, Kind: contractKind(msg.Value.InitMsg),
+					Actions: make(map[string]int64), initMsg: msg.Value.InitMsg, initFunds: msg.Value.InitFunds,
+					executors: make(map[string]bool),
+				})
+			}
+		}
+	}
+}
+
+// addExecution - Counts an execute message on its contract, executions of contracts which are
+// not indexed (instantiated before the indexed range) are skipped
+func (idx *Index) addExecution(execution Execution) {
+	for i := range idx.Contracts {
+		c := &idx.Contracts[i]
+
+		if c.Address != execution.Contract {
+			continue
+		}
+
+		c.Executions++
+		c.Actions[execution.Action]++
+
+		//An execution without a known sender isn't counted as an executor
+		if execution.Sender != "" {
+			c.executors[execution.Sender] = true
+			c.Executors = len(c.executors)
+		}
+
+		idx.Executions = append(idx.Executions, execution)
+
+		return
+	}
+}
+
+// Executions - Returns the execute messages of a tx, the sender falls back to the signer of the
+// message when it is not set
+func Executions(tx db.Transaction) []Execution {
+	var executions []Execution
+
+	for i, msg := range tx.Msgs {
+		if msg.Type != db.MSG_EXECUTE_CONTRACT {
+			continue
+		}
+
+		execution := newExecution(tx, msg.Value)
+		if execution.Sender == "" {
+			execution.Sender = msgSigner(tx, i)
+		}
+
+		executions = append(executions, execution)
+	}
+
+	return executions
+}
+
+// msgSigner - Returns the signer of the i-th message of a tx. The signers are de-duplicated in the
+// order of the messages, so the position only holds when every message has its own signer or all
+// of them share one, the signer is unknown ("") otherwise
+func msgSigner(tx db.Transaction, i int) string {
+	switch len(tx.Signers) {
+	case 1:
+		return tx.Signers[0]
+	case len(tx.Msgs):
+		return tx.Signers[i]
+	}
+
+	return ""
+}
+
+func newExecution(tx db.Transaction, msg db.MsgValue) Execution {
+	execution := Execution{Contract: msg.Contract, TxHash: tx.Hash, Height: tx.Height, Time: tx.Time, Sender: msg.Sender,
+		Action: msg.ExecuteAction()}
+
+	for _, args := range msg.Msg {
+		execution.Args, _ = args.(map[string]interface{})
+	}
+
+	return execution
+}
+
+// contractKind - ERC20 contracts are initialized with a symbol and decimals, escrows with an
+// arbiter and a recipient
+func contractKind(initMsg map[string]interface{}) string {
+	has := func(keys ...string) bool {
+		for _, key := range keys {
+			if _, ok := initMsg[key]; !ok {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	switch {
+	case has("symbol", "decimals"):
+		return KIND_ERC20
+	case has("arbiter", "recipient"):
+		return KIND_ESCROW
+	}
+
+	return ""
+}
+
+// finish - Orders the codes and contracts by height and checks them
+func (idx *Index) finish() {
+	sort.SliceStable(idx.Codes, func(i, j int) bool { return idx.Codes[i].Height < idx.Codes[j].Height })
+	sort.SliceStable(idx.Contracts, func(i, j int) bool { return idx.Contracts[i].Height < idx.Contracts[j].Height })
+	sort.SliceStable(idx.Executions, func(i, j int) bool { return idx.Executions[i].Height < idx.Executions[j].Height })
+
+	for i := range idx.Codes {
+		idx.Codes[i].Order = i + 1
+		idx.Codes[i].Issues = checkCode(idx.Codes[i])
+	}
+
+	for i := range idx.Contracts {
+		idx.Contracts[i].Order = i + 1
+		idx.Contracts[i].Issues = checkContract(idx.Contracts[i])
+	}
+}
+
+// ContractExecutions - Returns the executions of a contract ordered by height
+func (idx *Index) ContractExecutions(contract string) []Execution {
+	var executions []Execution
+
+	for _, e := range idx.Executions {
+		if e.Contract == contract {
+			executions = append(executions, e)
+		}
+	}
+
+	return executions
+}
+
+// ByOperator - Returns the codes and contracts created by an operator in deployment order
+func (idx *Index) ByOperator(operator string) ([]Code, []Contract) {
+	var (
+		codes     []Code
+		contracts []Contract
+	)
+
+	for _, c := range idx.Codes {
+		if c.Operator.OperatorAddr == operator {
+			codes = append(codes, c)
+		}
+	}
+
+	for _, c := range idx.Contracts {
+		if c.Operator.OperatorAddr == operator {
+			contracts = append(contracts, c)
+		}
+	}
+
+	return codes, contracts
+}
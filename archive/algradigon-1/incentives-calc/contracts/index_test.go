@@ -0,0 +1,241 @@
+package contracts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// account without a validator
+const stranger = "xrn:1stranger"
+
+// txsByType is a tx query of the txs by message type
+type txsByType map[string][]db.Transaction
+
+func (m txsByType) QueryTxsByMsgType(msgType string) ([]db.Transaction, error) {
+	return m[msgType], nil
+}
+
+// wasmTx - A tx of a single message, the event attributes are emitted on the message event
+func wasmTx(hash string, height int64, msgType string, value db.MsgValue, attributes ...db.Attribute) db.Transaction {
+	tx := db.Transaction{Hash: hash, Height: height, Msgs: []db.Msg{{Type: msgType, Value: value}}}
+
+	if len(attributes) > 0 {
+		tx.Logs = []db.TxLog{{Events: []db.Event{{Type: "message", Attributes: attributes}}}}
+	}
+
+	return tx
+}
+
+func failed(tx db.Transaction) db.Transaction {
+	tx.Code = 5
+	return tx
+}
+
+func execute(hash string, sender, contract, action string) db.Transaction {
+	return wasmTx(hash, 20, db.MSG_EXECUTE_CONTRACT, db.MsgValue{
+		Sender: sender, Contract: contract, Msg: map[string]interface{}{action: map[string]interface{}{}},
+	})
+}
+
+// testIndex - Codes and contracts of the validators vitwit (xrn:1acc1) and beta (xrn:1acc2), and of an
+// account without a validator
+func testIndex(t *testing.T) *Index {
+	codeID := func(id string) db.Attribute { return db.Attribute{Key: "code_id", Value: id} }
+	contract := func(addr string) db.Attribute { return db.Attribute{Key: "contract_address", Value: addr} }
+
+	txs := txsByType{
+		db.MSG_CREATE_VALIDATOR: {
+			wasmTx("V1", 1, db.MSG_CREATE_VALIDATOR, db.MsgValue{
+				DelegatorAddress: "xrn:1acc1", ValidatorAddress: "xrn:valoper1", Description: db.Description{Moniker: "Vitwit_Validator"},
+			}),
+			wasmTx("V2", 1, db.MSG_CREATE_VALIDATOR, db.MsgValue{
+				DelegatorAddress: "xrn:1acc2", ValidatorAddress: "xrn:valoper2", Description: db.Description{Moniker: "beta"},
+			}),
+		},
+		db.MSG_STORE_CODE: {
+			wasmTx("S1", 5, db.MSG_STORE_CODE, db.MsgValue{
+				Sender: "xrn:1acc1", Source: "https://github.com/vitwit/erc20", Builder: "cosmwasm/rust-optimizer:0.8.0",
+			}, codeID("2")),
+			wasmTx("S2", 3, db.MSG_STORE_CODE, db.MsgValue{Sender: stranger, Builder: "cosmwasm/rust-optimizer"}, codeID("1")),
+			failed(wasmTx("S3", 4, db.MSG_STORE_CODE, db.MsgValue{Sender: "xrn:1acc1"})),
+		},
+		db.MSG_INSTANTIATE_CONTRACT: {
+			wasmTx("I1", 10, db.MSG_INSTANTIATE_CONTRACT, db.MsgValue{
+				Sender: "xrn:1acc1", CodeID: "2", Label: "ERC20 vitwit validator",
+			}, contract("c1")),
+			wasmTx("I2", 8, db.MSG_INSTANTIATE_CONTRACT, db.MsgValue{
+				Sender: "xrn:1acc2", CodeID: "2", Label: "token", InitMsg: map[string]interface{}{"name": "Beta Coin"},
+			}, contract("c2")),
+			wasmTx("I3", 12, db.MSG_INSTANTIATE_CONTRACT, db.MsgValue{Sender: stranger, CodeID: "1", Label: "mine"}, contract("c3")),
+		},
+		db.MSG_EXECUTE_CONTRACT: {
+			execute("E1", "xrn:1acc2", "c1", "transfer"),
+			execute("E2", "xrn:1acc2", "c1", "transfer"),
+			execute("E3", stranger, "c1", "transfer_from"),
+			execute("E4", stranger, "c9", "transfer"),
+			failed(execute("E5", stranger, "c1", "approve")),
+		},
+	}
+
+	idx := NewIndex()
+
+	if err := idx.Load(txs); err != nil {
+		t.Fatal(err)
+	}
+
+	return idx
+}
+
+func TestIndexCodes(t *testing.T) {
+	type code struct {
+		TxHash   string
+		CodeID   string
+		Operator string
+		Order    int
+		Issues   []string
+	}
+
+	var got []code
+	for _, c := range testIndex(t).Codes {
+		got = append(got, code{c.TxHash, c.CodeID, c.Operator.OperatorAddr, c.Order, c.Issues})
+	}
+
+	want := []code{
+		{"S2", "1", "", 1, []string{
+			"creator xrn:1stranger is not a validator account", "no source url",
+			`builder "cosmwasm/rust-optimizer" has no tag`,
+		}},
+		{"S1", "2", "xrn:valoper1", 2, nil},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("codes =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestIndexContracts(t *testing.T) {
+	type contract struct {
+		Address    string
+		Operator   string
+		Order      int
+		Executions int64
+		Executors  int
+		Actions    map[string]int64
+		Issues     []string
+	}
+
+	var got []contract
+	for _, c := range testIndex(t).Contracts {
+		got = append(got, contract{c.Address, c.Operator.OperatorAddr, c.Order, c.Executions, c.Executors, c.Actions, c.Issues})
+	}
+
+	//The execution of c9 is skipped, c9 was instantiated before the indexed txs
+	want := []contract{
+		{"c2", "xrn:valoper2", 1, 0, 0, map[string]int64{}, nil},
+		{"c1", "xrn:valoper1", 2, 3, 2, map[string]int64{"transfer": 2, "transferfrom": 1}, nil},
+		{"c3", "", 3, 0, 0, map[string]int64{}, []string{"creator xrn:1stranger is not a validator account"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("contracts =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCheckContract(t *testing.T) {
+	operator := Operator{OperatorAddr: "xrn:valoper1", Moniker: "Vitwit_Validator"}
+
+	tests := []struct {
+		contract Contract
+		want     []string
+	}{
+		{Contract{Operator: operator, Label: "ERC20 vitwit validator"}, nil},
+		{Contract{Operator: operator, Label: "token", Name: "VitwitValidator Coin"}, nil},
+		{Contract{Operator: operator, Label: "vitwit"}, []string{`label "vitwit" is not named after the validator "Vitwit_Validator"`}},
+		{Contract{Operator: Operator{OperatorAddr: "xrn:valoper1"}, Label: "token"}, []string{"the validator has no moniker"}},
+		{Contract{Creator: "xrn:1x"}, []string{"creator xrn:1x is not a validator account"}},
+	}
+
+	for _, tt := range tests {
+		if got := checkContract(tt.contract); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("checkContract(%q) = %q, want %q", tt.contract.Label, got, tt.want)
+		}
+	}
+}
+
+func TestByOperator(t *testing.T) {
+	codes, contracts := testIndex(t).ByOperator("xrn:valoper1")
+
+	if len(codes) != 1 || codes[0].TxHash != "S1" || len(contracts) != 1 || contracts[0].Address != "c1" {
+		t.Errorf("ByOperator = %+v %+v, want S1 and c1", codes, contracts)
+	}
+
+	if codes, contracts := testIndex(t).ByOperator("xrn:valoper9"); codes != nil || contracts != nil {
+		t.Errorf("ByOperator of an unknown operator = %+v %+v", codes, contracts)
+	}
+}
+
+// executeTx - A tx of execute messages of c1 without a sender, signed by the signers
+func executeTx(hash string, msgs int, signers ...string) db.Transaction {
+	tx := db.Transaction{Hash: hash, Height: 20, Signers: signers}
+
+	for i := 0; i < msgs; i++ {
+		tx.Msgs = append(tx.Msgs, db.Msg{Type: db.MSG_EXECUTE_CONTRACT, Value: db.MsgValue{
+			Contract: "c1", Msg: map[string]interface{}{"transfer": map[string]interface{}{}},
+		}})
+	}
+
+	return tx
+}
+
+func TestExecutions(t *testing.T) {
+	tests := []struct {
+		name    string
+		tx      db.Transaction
+		senders []string
+	}{
+		{name: "message sender", tx: execute("E1", "xrn:1acc1", "c1", "transfer"), senders: []string{"xrn:1acc1"}},
+		{name: "single signer", tx: executeTx("E2", 2, "xrn:1acc1"), senders: []string{"xrn:1acc1", "xrn:1acc1"}},
+		{name: "signer per message", tx: executeTx("E3", 2, "xrn:1acc1", "xrn:1acc2"), senders: []string{"xrn:1acc1", "xrn:1acc2"}},
+		//The signers of acc1, acc2, acc1 are de-duplicated, the position of the third message is lost
+		{name: "de-duplicated signers", tx: executeTx("E4", 3, "xrn:1acc1", "xrn:1acc2"), senders: []string{"", "", ""}},
+		{name: "no signers", tx: executeTx("E5", 1), senders: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var senders []string
+			for _, e := range Executions(tt.tx) {
+				senders = append(senders, e.Sender)
+			}
+
+			if !reflect.DeepEqual(senders, tt.senders) {
+				t.Errorf("senders = %q, want %q", senders, tt.senders)
+			}
+		})
+	}
+}
+
+// TestIndexExecutors - The index reads the senders like Executions, an execution without a
+// known sender is counted but not as an executor
+func TestIndexExecutors(t *testing.T) {
+	idx := NewIndex()
+	idx.AddTxs([]db.Transaction{
+		wasmTx("I1", 10, db.MSG_INSTANTIATE_CONTRACT, db.MsgValue{Sender: "xrn:1acc1"}, db.Attribute{Key: "contract_address", Value: "c1"}),
+	}, db.MSG_INSTANTIATE_CONTRACT)
+	idx.AddTxs([]db.Transaction{
+		executeTx("E1", 2, "xrn:1acc1"),
+		executeTx("E2", 3, "xrn:1acc1", "xrn:1acc2"),
+		execute("E3", "xrn:1acc2", "c1", "transfer"),
+	}, db.MSG_EXECUTE_CONTRACT)
+
+	c := idx.Contracts[0]
+	if c.Executions != 6 || c.Executors != 2 {
+		t.Errorf("executions = %d, executors = %d, want 6 and 2", c.Executions, c.Executors)
+	}
+
+	if len(idx.Executions) != 6 {
+		t.Errorf("indexed %d executions, want 6", len(idx.Executions))
+	}
+}
@@ -1,16 +1,16 @@
 package db
 
 import (
-	"github.com/spf13/viper"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
-//configuring db name and collections
+//configuring db name and collections, the db name is set from the config on Connect
 var (
-	DB_NAME, dbErr        = viper.Get("database").(string)
-	BLOCKS_COLLECTION     = "blocks"
-	VALIDATORS_COLLECTION = "validators"
+	DB_NAME                 = ""
+	BLOCKS_COLLECTION       = "blocks"
+	VALIDATORS_COLLECTION   = "validators"
+	TRANSACTIONS_COLLECTION = "transactions"
 )
 
 type Blocks struct {
@@ -26,7 +26,7 @@ type Validator struct {
 }
 
 type ValAggregateResult struct {
-	Id                string              `json:"_id" bson.M:"_id"`
+	Id                string              `json:"_id" bson:"_id"`
 	Uptime_count      int64               `json:"uptime_count" bson:"uptime_count"`
 	Upgrade1_block    int64               `json:"upgrade1_block" bson:"upgrade1_block"`
 	Upgrade2_block    int64               `json:"upgrade2_block" bson:"upgrade2_block"`
@@ -40,12 +40,16 @@ type Validator_details struct {
 }
 
 type Description struct {
-	Moniker string `json:"moniker" bson:"moniker"`
+	Moniker  string `json:"moniker" bson:"moniker"`
+	Identity string `json:"identity" bson:"identity"`
+	Website  string `json:"website" bson:"website"`
 }
 
 // Connect returns a pointer to a MongoDB instance,
 // which is used for collecting the metrics required for uptime calculations
 func Connect(info *mgo.DialInfo) (DB, error) {
+	DB_NAME = info.Database
+
 	session, err := mgo.DialWithInfo(info)
 
 	return Store{session: session}, err
@@ -67,6 +71,13 @@ type (
 	DB interface {
 		Terminate()
 		QueryValAggregateData(aggQuery []bson.M) ([]ValAggregateResult, error)
+		TxSource
+		QueryTxsByMsgType(msgType string) ([]Transaction, error)
+		TxStore
+		ScoreStore
+		PowerStore
+		HeaderStore
+		EvidenceStore
 	}
 
 	// Store will be used to satisfy the DB interface
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+// collection of the misbehaviour evidence included in blocks
+var (
+	EVIDENCE_COLLECTION = "evidence"
+)
+
+// Evidence is a piece of misbehaviour evidence included in the block at height, such as a
+// DuplicateVoteEvidence of a validator which signed two votes for the same vote height and round.
+// Address is the hex consensus address of the offender
+type Evidence struct {
+	ID         string    `json:"id" bson:"_id"`
+	Height     int64     `json:"height" bson:"height"`
+	Time       time.Time `json:"time" bson:"time"`
+	Type       string    `json:"type" bson:"type"`
+	Address    string    `json:"address" bson:"address"`
+	VoteHeight int64     `json:"voteHeight" bson:"vote_height"`
+	VoteRound  int64     `json:"voteRound" bson:"vote_round"`
+}
+
+// EvidenceStore persists the ingested evidence
+type EvidenceStore interface {
+	QueryEvidence(fromHeight, toHeight int64) ([]Evidence, error)
+	SaveEvidence(evidence *Evidence) error
+}
+
+// QueryEvidence - Fetch the evidence included in between the given heights ordered by height
+func (db Store) QueryEvidence(fromHeight, toHeight int64) (result []Evidence, err error) {
+	query := bson.M{"height": bson.M{"$gte": fromHeight, "$lte": toHeight}}
+	err = db.session.DB(DB_NAME).C(EVIDENCE_COLLECTION).Find(query).Sort("height", "address").All(&result)
+	return result, err
+}
+
+// SaveEvidence - Replace the evidence with the same id
+func (db Store) SaveEvidence(evidence *Evidence) error {
+	_, err := db.session.DB(DB_NAME).C(EVIDENCE_COLLECTION).UpsertId(evidence.ID, evidence)
+	return err
+}
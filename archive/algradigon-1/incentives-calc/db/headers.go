@@ -0,0 +1,55 @@
+package db
+
+import (
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// collection of the block headers
+var (
+	BLOCK_HEADERS_COLLECTION = "block_headers"
+)
+
+// BlockHeader holds the header fields of a block which are not part of Blocks, the proposer
+// is the hex consensus address of the validator which proposed it and round the consensus
+// round the block was committed in, 0 when the first proposal was accepted
+type BlockHeader struct {
+	Height   int64     `json:"height" bson:"_id"`
+	Time     time.Time `json:"time" bson:"time"`
+	Proposer string    `json:"proposer" bson:"proposer"`
+	Round    int64     `json:"round" bson:"round"`
+}
+
+// HeaderStore persists the ingested block headers
+type HeaderStore interface {
+	QueryBlockHeaders(fromHeight, toHeight int64) ([]BlockHeader, error)
+	QueryLatestHeaderHeight() (int64, error)
+	SaveBlockHeader(header *BlockHeader) error
+}
+
+// QueryBlockHeaders - Fetch the headers of the heights in between the given heights ordered by height
+func (db Store) QueryBlockHeaders(fromHeight, toHeight int64) (result []BlockHeader, err error) {
+	query := bson.M{"_id": bson.M{"$gte": fromHeight, "$lte": toHeight}}
+	err = db.session.DB(DB_NAME).C(BLOCK_HEADERS_COLLECTION).Find(query).Sort("_id").All(&result)
+	return result, err
+}
+
+// QueryLatestHeaderHeight - Fetch the latest height with an ingested header, 0 when there is none
+func (db Store) QueryLatestHeaderHeight() (int64, error) {
+	var header BlockHeader
+
+	err := db.session.DB(DB_NAME).C(BLOCK_HEADERS_COLLECTION).Find(nil).Sort("-_id").Limit(1).One(&header)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	}
+
+	return header.Height, err
+}
+
+// SaveBlockHeader - Replace the header of a height
+func (db Store) SaveBlockHeader(header *BlockHeader) error {
+	_, err := db.session.DB(DB_NAME).C(BLOCK_HEADERS_COLLECTION).UpsertId(header.Height, header)
+	return err
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// collection of the voting power per height
+var (
+	VOTING_POWER_COLLECTION = "voting_power"
+)
+
+// ValidatorPower is the voting power of a validator, address is the hex consensus address
+type ValidatorPower struct {
+	Address     string `json:"address" bson:"address"`
+	VotingPower int64  `json:"votingPower" bson:"voting_power"`
+}
+
+// BlockPower is the validator set which signed the commit included in the block at height,
+// the set of height - 1, so it lines up with the signers in Blocks.Validators
+type BlockPower struct {
+	Height     int64            `json:"height" bson:"_id"`
+	TotalPower int64            `json:"totalPower" bson:"total_power"`
+	Validators []ValidatorPower `json:"validators" bson:"validators"`
+}
+
+// PowerStore persists the voting power ingested per height
+type PowerStore interface {
+	QueryBlockPowers(fromHeight, toHeight int64) ([]BlockPower, error)
+	QueryLatestPowerHeight() (int64, error)
+	SaveBlockPower(power *BlockPower) error
+}
+
+// QueryBlockPowers - Fetch the voting power of the heights in between the given heights ordered by height
+func (db Store) QueryBlockPowers(fromHeight, toHeight int64) (result []BlockPower, err error) {
+	query := bson.M{"_id": bson.M{"$gte": fromHeight, "$lte": toHeight}}
+	err = db.session.DB(DB_NAME).C(VOTING_POWER_COLLECTION).Find(query).Sort("_id").All(&result)
+	return result, err
+}
+
+// QueryLatestPowerHeight - Fetch the latest height with ingested voting power, 0 when there is none
+func (db Store) QueryLatestPowerHeight() (int64, error) {
+	var power BlockPower
+
+	err := db.session.DB(DB_NAME).C(VOTING_POWER_COLLECTION).Find(nil).Sort("-_id").Limit(1).One(&power)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	}
+
+	return power.Height, err
+}
+
+// SaveBlockPower - Replace the voting power of a height
+func (db Store) SaveBlockPower(power *BlockPower) error {
+	_, err := db.session.DB(DB_NAME).C(VOTING_POWER_COLLECTION).UpsertId(power.Height, power)
+	return err
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/regen-friends/testnets/util/uptime/upgrades"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// collections of the incremental scoring
+var (
+	SCORE_STATE_COLLECTION       = "score_state"
+	SCORE_CHECKPOINTS_COLLECTION = "score_checkpoints"
+)
+
+// Accumulator holds the running counts of a validator, the upgrade blocks are the
+// first heights signed in the upgrade windows (0 when none)
+type Accumulator struct {
+	Address       string `json:"address" bson:"address"`
+	UptimeCount   int64  `json:"uptimeCount" bson:"uptime_count"`
+	Upgrade1Block int64  `json:"upgrade1Block" bson:"upgrade1_block"`
+	Upgrade2Block int64  `json:"upgrade2Block" bson:"upgrade2_block"`
+}
+
+// ScoreState is the state of an incremental scoring run after consuming all blocks up to height.
+// The head state of a run is kept in score_state, copies are kept in score_checkpoints
+type ScoreState struct {
+	ID          string          `json:"id" bson:"_id"`
+	Run         string          `json:"run" bson:"run"`
+	StartHeight int64           `json:"startHeight" bson:"start_height"`
+	Height      int64           `json:"height" bson:"height"`
+	Upgrade1    upgrades.Window `json:"upgrade1" bson:"upgrade1"`
+	Upgrade2    upgrades.Window `json:"upgrade2" bson:"upgrade2"`
+	Validators  []Accumulator   `json:"validators" bson:"validators"`
+}
+
+// ScoreStore persists the state of incremental scoring runs, the loads return
+// a nil state when it is not found
+type ScoreStore interface {
+	QueryBlocks(fromHeight, toHeight int64) ([]Blocks, error)
+	QueryLatestHeight() (int64, error)
+	QueryValidators() ([]Validator, error)
+	LoadScoreState(run string) (*ScoreState, error)
+	SaveScoreState(state *ScoreState) error
+	LoadCheckpoint(run string, height int64) (*ScoreState, error)
+	SaveCheckpoint(state *ScoreState) error
+}
+
+// QueryBlocks - Fetch the blocks in between the given heights ordered by height
+func (db Store) QueryBlocks(fromHeight, toHeight int64) (result []Blocks, err error) {
+	query := bson.M{"height": bson.M{"$gte": fromHeight, "$lte": toHeight}}
+	err = db.session.DB(DB_NAME).C(BLOCKS_COLLECTION).Find(query).Sort("height").All(&result)
+	return result, err
+}
+
+// QueryLatestHeight - Fetch the height of the latest ingested block
+func (db Store) QueryLatestHeight() (int64, error) {
+	var block Blocks
+
+	err := db.session.DB(DB_NAME).C(BLOCKS_COLLECTION).Find(nil).Sort("-height").Limit(1).One(&block)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	}
+
+	return block.Height, err
+}
+
+// QueryValidators - Fetch all validators
+func (db Store) QueryValidators() (result []Validator, err error) {
+	err = db.session.DB(DB_NAME).C(VALIDATORS_COLLECTION).Find(nil).All(&result)
+	return result, err
+}
+
+// LoadScoreState - Fetch the head state of a scoring run
+func (db Store) LoadScoreState(run string) (*ScoreState, error) {
+	state := &ScoreState{}
+
+	err := db.session.DB(DB_NAME).C(SCORE_STATE_COLLECTION).FindId(run).One(state)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return state, err
+}
+
+// SaveScoreState - Replace the head state of a scoring run
+func (db Store) SaveScoreState(state *ScoreState) error {
+	state.ID = state.Run
+
+	_, err := db.session.DB(DB_NAME).C(SCORE_STATE_COLLECTION).UpsertId(state.ID, state)
+	return err
+}
+
+// LoadCheckpoint - Fetch the latest checkpoint of a run at or below the given height
+func (db Store) LoadCheckpoint(run string, height int64) (*ScoreState, error) {
+	state := &ScoreState{}
+
+	query := bson.M{"run": run, "height": bson.M{"$lte": height}}
+
+	err := db.session.DB(DB_NAME).C(SCORE_CHECKPOINTS_COLLECTION).Find(query).Sort("-height").Limit(1).One(state)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return state, err
+}
+
+// SaveCheckpoint - Store a copy of the state under its run and height
+func (db Store) SaveCheckpoint(state *ScoreState) error {
+	checkpoint := *state
+	checkpoint.ID = fmt.Sprintf("%s/%d", state.Run, state.Height)
+
+	_, err := db.session.DB(DB_NAME).C(SCORE_CHECKPOINTS_COLLECTION).UpsertId(checkpoint.ID, &checkpoint)
+	return err
+}
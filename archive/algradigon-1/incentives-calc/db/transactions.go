@@ -0,0 +1,284 @@
+package db
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// message types stored in transactions
+const (
+	MSG_CREATE_VALIDATOR     = "cosmos-sdk/MsgCreateValidator"
+	MSG_STORE_CODE           = "wasm/store-code"
+	MSG_INSTANTIATE_CONTRACT = "wasm/instantiate"
+	MSG_EXECUTE_CONTRACT     = "wasm/execute"
+)
+
+// Transaction is an indexed transaction, signers are the account addresses of the
+// message signers in order. Gas and fee are only set on transactions ingested with txs ingest
+type Transaction struct {
+	Hash      string    `json:"hash" bson:"hash"`
+	Height    int64     `json:"height" bson:"height"`
+	Time      time.Time `json:"time" bson:"time"`
+	Code      uint32    `json:"code" bson:"code"`
+	Signers   []string  `json:"signers" bson:"signers"`
+	Msgs      []Msg     `json:"msgs" bson:"msgs"`
+	Logs      []TxLog   `json:"logs" bson:"logs"`
+	GasWanted int64     `json:"gas_wanted" bson:"gas_wanted"`
+	GasUsed   int64     `json:"gas_used" bson:"gas_used"`
+	Fee       []Coin    `json:"fee" bson:"fee"`
+}
+
+type Msg struct {
+	Type  string   `json:"type" bson:"type"`
+	Value MsgValue `json:"value" bson:"value"`
+}
+
+// MsgValue holds the fields of all the message types used by the calculator,
+// fields which are not part of a message type are left empty
+type MsgValue struct {
+	Description      Description `json:"description" bson:"description"`
+	DelegatorAddress string      `json:"delegator_address" bson:"delegator_address"`
+	ValidatorAddress string      `json:"validator_address" bson:"validator_address"`
+	Pubkey           string      `json:"pubkey" bson:"pubkey"`
+	Value            Coin        `json:"value" bson:"value"`
+	FromAddress      string      `json:"from_address" bson:"from_address"`
+	ToAddress        string      `json:"to_address" bson:"to_address"`
+
+	// wasm messages
+	Sender    string                 `json:"sender" bson:"sender"`
+	CodeID    string                 `json:"code_id" bson:"code_id"`
+	Contract  string                 `json:"contract" bson:"contract"`
+	Label     string                 `json:"label" bson:"label"`
+	Source    string                 `json:"source" bson:"source"`
+	Builder   string                 `json:"builder" bson:"builder"`
+	InitMsg   map[string]interface{} `json:"init_msg" bson:"init_msg"`
+	Msg       map[string]interface{} `json:"msg" bson:"msg"`
+	InitFunds []Coin                 `json:"init_funds" bson:"init_funds"`
+	SentFunds []Coin                 `json:"sent_funds" bson:"sent_funds"`
+}
+
+type Coin struct {
+	Denom  string `json:"denom" bson:"denom"`
+	Amount string `json:"amount" bson:"amount"`
+}
+
+type TxLog struct {
+	MsgIndex int     `json:"msg_index" bson:"msg_index"`
+	Events   []Event `json:"events" bson:"events"`
+}
+
+type Event struct {
+	Type       string      `json:"type" bson:"type"`
+	Attributes []Attribute `json:"attributes" bson:"attributes"`
+}
+
+type Attribute struct {
+	Key   string `json:"key" bson:"key"`
+	Value string `json:"value" bson:"value"`
+}
+
+// EventValue - Returns the first value of an event attribute emitted by the tx
+func (tx Transaction) EventValue(eventType, key string) string {
+	for _, log := range tx.Logs {
+		for _, event := range log.Events {
+			if event.Type != eventType {
+				continue
+			}
+
+			for _, attr := range event.Attributes {
+				if attr.Key == key {
+					return attr.Value
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// HasAttribute - Returns whether an event of any type emitted by the tx has the attribute, as the
+// $elemMatch of Store queries
+func (tx Transaction) HasAttribute(key, value string) bool {
+	for _, log := range tx.Logs {
+		for _, event := range log.Events {
+			for _, attr := range event.Attributes {
+				if attr.Key == key && attr.Value == value {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// ExecuteAction - Returns the normalized name of a contract execute message, the
+// message names are case and underscore insensitive (transferFrom, transfer_from)
+func (msg MsgValue) ExecuteAction() string {
+	for action := range msg.Msg {
+		return NormalizeAction(action)
+	}
+
+	return ""
+}
+
+func NormalizeAction(action string) string {
+	return strings.ToLower(strings.Replace(action, "_", "", -1))
+}
+
+// TxSource is used for resolving transactions, the lookups return a nil
+// transaction when it is not found
+type TxSource interface {
+	QueryTxByHash(hash string) (*Transaction, error)
+	QueryInstantiateTx(contract string) (*Transaction, error)
+}
+
+// TxStore is used for the transactions of a height range and for storing ingested transactions
+type TxStore interface {
+	QueryTxsByHeight(fromHeight, toHeight int64) ([]Transaction, error)
+	SaveTransaction(tx *Transaction) error
+}
+
+// QueryTxsByHeight - Fetch the transactions in between the given heights ordered by height
+func (db Store) QueryTxsByHeight(fromHeight, toHeight int64) (result []Transaction, err error) {
+	query := bson.M{"height": bson.M{"$gte": fromHeight, "$lte": toHeight}}
+	err = db.session.DB(DB_NAME).C(TRANSACTIONS_COLLECTION).Find(query).Sort("height").All(&result)
+	return result, err
+}
+
+// SaveTransaction - Replace the transaction with the same hash
+func (db Store) SaveTransaction(tx *Transaction) error {
+	_, err := db.session.DB(DB_NAME).C(TRANSACTIONS_COLLECTION).Upsert(bson.M{"hash": tx.Hash}, tx)
+	return err
+}
+
+// QueryTxsByMsgType - Fetch all successful transactions containing a message of given type
+func (db Store) QueryTxsByMsgType(msgType string) (result []Transaction, err error) {
+	query := bson.M{"code": 0, "msgs.type": msgType}
+	err = db.session.DB(DB_NAME).C(TRANSACTIONS_COLLECTION).Find(query).Sort("height").All(&result)
+	return result, err
+}
+
+// QueryTxByHash - Fetch a transaction by its hash
+func (db Store) QueryTxByHash(hash string) (*Transaction, error) {
+	result := &Transaction{}
+
+	err := db.session.DB(DB_NAME).C(TRANSACTIONS_COLLECTION).Find(bson.M{"hash": strings.ToUpper(hash)}).One(result)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return result, err
+}
+
+// QueryInstantiateTx - Fetch the successful transaction which instantiated a contract
+func (db Store) QueryInstantiateTx(contract string) (*Transaction, error) {
+	query := bson.M{
+		"code":      0,
+		"msgs.type": MSG_INSTANTIATE_CONTRACT,
+		"logs.events.attributes": bson.M{
+			"$elemMatch": bson.M{"key": "contract_address", "value": contract},
+		},
+	}
+
+	result := &Transaction{}
+
+	err := db.session.DB(DB_NAME).C(TRANSACTIONS_COLLECTION).Find(query).One(result)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return result, err
+}
+
+// TxDump is a local stand-in for the transactions collection, read from
+// a JSON file holding an array of transactions
+type TxDump struct {
+	txs    []Transaction
+	byHash map[string]int
+}
+
+// LoadTxDump - Reads a JSON dump of transactions
+func LoadTxDump(path string) (*TxDump, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dump := &TxDump{byHash: make(map[string]int)}
+
+	if err := json.Unmarshal(bz, &dump.txs); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(dump.txs, func(i, j int) bool {
+		return dump.txs[i].Height < dump.txs[j].Height
+	})
+
+	for i := range dump.txs {
+		dump.txs[i].Hash = strings.ToUpper(dump.txs[i].Hash)
+		dump.byHash[dump.txs[i].Hash] = i
+	}
+
+	return dump, nil
+}
+
+func (d *TxDump) QueryTxByHash(hash string) (*Transaction, error) {
+	i, ok := d.byHash[strings.ToUpper(hash)]
+	if !ok {
+		return nil, nil
+	}
+
+	return &d.txs[i], nil
+}
+
+func (d *TxDump) QueryInstantiateTx(contract string) (*Transaction, error) {
+	for i, tx := range d.txs {
+		if tx.Code == 0 && tx.HasAttribute("contract_address", contract) {
+			for _, msg := range tx.Msgs {
+				if msg.Type == MSG_INSTANTIATE_CONTRACT {
+					return &d.txs[i], nil
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (d *TxDump) QueryTxsByMsgType(msgType string) ([]Transaction, error) {
+	var result []Transaction
+
+	for _, tx := range d.txs {
+		if tx.Code != 0 {
+			continue
+		}
+
+		for _, msg := range tx.Msgs {
+			if msg.Type == msgType {
+				result = append(result, tx)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (d *TxDump) QueryTxsByHeight(fromHeight, toHeight int64) ([]Transaction, error) {
+	var result []Transaction
+
+	for _, tx := range d.txs {
+		if tx.Height >= fromHeight && tx.Height <= toHeight {
+			result = append(result, tx)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,161 @@
+package distribution
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/genesis"
+	"github.com/regen-friends/testnets/util/uptime/program"
+)
+
+// formulas of a distribution
+const (
+	FORMULA_PROPORTIONAL = "proportional"
+	FORMULA_CAPPED       = "capped"
+	FORMULA_FLOOR        = "floor"
+)
+
+// Vesting is a vesting schedule of the allocations, continuous from the start time to the end time,
+// or delayed until the end time when the start time is not set
+type Vesting struct {
+	StartTime time.Time `json:"startTime,omitempty"`
+	EndTime   time.Time `json:"endTime"`
+}
+
+// Config describes how the points of the final standings become token allocations. The standings
+// are those of a program file or of the JSON output of the program command. Proportional shares the
+// pool by points, capped limits an allocation to the cap and floor raises it to the floor, the
+// difference is shared among the others by points. Amounts are integers of the denom. Addresses
+// maps identities to the account paid instead of the delegator address of their latest operator
+type Config struct {
+	Program   string            `json:"program,omitempty"`
+	Standings string            `json:"standings,omitempty"`
+	Pool      string            `json:"pool"`
+	Denom     string            `json:"denom"`
+	Formula   string            `json:"formula"`
+	Cap       string            `json:"cap,omitempty"`
+	Floor     string            `json:"floor,omitempty"`
+	MinPoints float64           `json:"minPoints,omitempty"`
+	Vesting   *Vesting          `json:"vesting,omitempty"`
+	Addresses map[string]string `json:"addresses,omitempty"`
+}
+
+// Load - Reads and validates a distribution file, the program and standings paths are relative to it
+func Load(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	var c Config
+
+	if err := decoder.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if errs := c.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %s", path, strings.Join(errs, "; "))
+	}
+
+	dir := filepath.Dir(path)
+
+	if c.Program != "" && !filepath.IsAbs(c.Program) {
+		c.Program = filepath.Join(dir, c.Program)
+	}
+
+	if c.Standings != "" && !filepath.IsAbs(c.Standings) {
+		c.Standings = filepath.Join(dir, c.Standings)
+	}
+
+	return &c, nil
+}
+
+// Validate - Returns every problem found in the config
+func (c *Config) Validate() []string {
+	var errs []string
+
+	if (c.Program == "") == (c.Standings == "") {
+		errs = append(errs, "exactly one of program or standings is required")
+	}
+
+	if pool, err := genesis.ParseAmount(c.Pool); err != nil || pool.Sign() <= 0 {
+		errs = append(errs, fmt.Sprintf("pool %q is not a positive amount", c.Pool))
+	}
+
+	if c.Denom == "" {
+		errs = append(errs, "denom is missing")
+	}
+
+	switch c.Formula {
+	case FORMULA_PROPORTIONAL:
+	case FORMULA_CAPPED:
+		if limit, err := genesis.ParseAmount(c.Cap); err != nil || limit.Sign() <= 0 {
+			errs = append(errs, fmt.Sprintf("cap %q is not a positive amount", c.Cap))
+		}
+	case FORMULA_FLOOR:
+		if floor, err := genesis.ParseAmount(c.Floor); err != nil || floor.Sign() <= 0 {
+			errs = append(errs, fmt.Sprintf("floor %q is not a positive amount", c.Floor))
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("formula %q is not one of %s, %s or %s", c.Formula,
+			FORMULA_PROPORTIONAL, FORMULA_CAPPED, FORMULA_FLOOR))
+	}
+
+	if c.Vesting != nil {
+		if c.Vesting.EndTime.IsZero() {
+			errs = append(errs, "vesting endTime is missing")
+		} else if !c.Vesting.StartTime.IsZero() && !c.Vesting.EndTime.After(c.Vesting.StartTime) {
+			errs = append(errs, "vesting endTime is not after startTime")
+		}
+	}
+
+	return errs
+}
+
+// LoadStandings - Runs the program of the config or reads its standings
+func (c *Config) LoadStandings() (*program.Standings, error) {
+	if c.Program != "" {
+		p, err := program.Load(c.Program)
+		if err != nil {
+			return nil, err
+		}
+
+		return program.Run(p)
+	}
+
+	file, err := os.Open(c.Standings)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var standings program.Standings
+
+	if err := json.NewDecoder(file).Decode(&standings); err != nil {
+		return nil, fmt.Errorf("%s: %v", c.Standings, err)
+	}
+
+	return &standings, nil
+}
+
+// amount - Parses a validated amount, empty amounts are nil
+func amount(value string) *big.Int {
+	if value == "" {
+		return nil
+	}
+
+	parsed, _ := genesis.ParseAmount(value)
+
+	return parsed
+}
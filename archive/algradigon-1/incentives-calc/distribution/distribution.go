@@ -0,0 +1,282 @@
+package distribution
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/program"
+)
+
+// bounds of an allocation set by the cap or the floor instead of the points
+const (
+	BOUND_CAP   = "cap"
+	BOUND_FLOOR = "floor"
+)
+
+// Allocation is the tokens of a participant, paid to the delegator address of its latest operator
+type Allocation struct {
+	Rank     int     `json:"rank"`
+	Identity string  `json:"identity"`
+	Moniker  string  `json:"moniker"`
+	Operator string  `json:"operator"`
+	Address  string  `json:"address"`
+	Points   float64 `json:"points"`
+	Amount   string  `json:"amount"`
+	Bound    string  `json:"bound,omitempty"`
+}
+
+// Exclusion is a participant of the standings without an allocation
+type Exclusion struct {
+	Rank     int     `json:"rank"`
+	Identity string  `json:"identity"`
+	Moniker  string  `json:"moniker"`
+	Points   float64 `json:"points"`
+	Reason   string  `json:"reason"`
+}
+
+// Distribution is the allocations of the pool in the order of the standings, the amounts sum
+// exactly to the pool
+type Distribution struct {
+	Program     string       `json:"program"`
+	Pool        string       `json:"pool"`
+	Denom       string       `json:"denom"`
+	Formula     string       `json:"formula"`
+	Points      float64      `json:"points"`
+	Vesting     *Vesting     `json:"vesting,omitempty"`
+	Allocations []Allocation `json:"allocations"`
+	Excluded    []Exclusion  `json:"excluded"`
+}
+
+// New - Allocates the pool to the participants of the standings with positive points (at least
+// the minimum points when set). Participants without an address are excluded
+func New(c *Config, standings *program.Standings) (*Distribution, error) {
+	d := &Distribution{Program: standings.Program, Pool: c.Pool, Denom: c.Denom, Formula: c.Formula, Vesting: c.Vesting}
+
+	var points []float64
+
+	paid := make(map[string]string)
+
+	for _, s := range standings.Standings {
+		exclude := func(reason string) {
+			d.Excluded = append(d.Excluded, Exclusion{
+				Rank: s.Rank, Identity: s.Identity, Moniker: s.Moniker, Points: s.Total, Reason: reason,
+			})
+		}
+
+		if s.Total <= 0 || s.Total < c.MinPoints {
+			exclude(fmt.Sprintf("%g points is below the minimum", s.Total))
+			continue
+		}
+
+		allocation := Allocation{Rank: s.Rank, Identity: s.Identity, Moniker: s.Moniker, Points: s.Total}
+
+		if len(s.Operators) > 0 {
+			allocation.Operator = s.Operators[len(s.Operators)-1].Operator
+		}
+
+		allocation.Address = c.Addresses[s.Identity]
+
+		if allocation.Address == "" {
+			acc, err := address.ValOperToAccount(allocation.Operator)
+			if err != nil {
+				exclude(fmt.Sprintf("no delegator address: %v", err))
+				continue
+			}
+
+			allocation.Address = acc
+		}
+
+		if other, ok := paid[allocation.Address]; ok {
+			return nil, fmt.Errorf("%s and %s are paid to the same address %s", other, s.Identity, allocation.Address)
+		}
+
+		paid[allocation.Address] = s.Identity
+
+		d.Allocations = append(d.Allocations, allocation)
+		d.Points += s.Total
+		points = append(points, s.Total)
+	}
+
+	var limit, floor *big.Int
+
+	switch c.Formula {
+	case FORMULA_CAPPED:
+		limit = amount(c.Cap)
+	case FORMULA_FLOOR:
+		floor = amount(c.Floor)
+	}
+
+	pool := amount(c.Pool)
+
+	amounts, bounds, err := allocate(pool, points, floor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	total := new(big.Int)
+
+	for i := range d.Allocations {
+		d.Allocations[i].Amount = amounts[i].String()
+		d.Allocations[i].Bound = bounds[i]
+		total.Add(total, amounts[i])
+	}
+
+	if total.Cmp(pool) != 0 {
+		return nil, fmt.Errorf("allocated %s of the pool %s", total, pool)
+	}
+
+	return d, nil
+}
+
+// allocate - Shares the pool by points. Shares above the limit are set to it, or shares below the
+// floor raised to it, and the rest of the pool is shared again among the others until no share is
+// out of bounds. Every round uses the largest remainder so the shares sum exactly to the pool
+func allocate(pool *big.Int, points []float64, floor, limit *big.Int) ([]*big.Int, []string, error) {
+	n := big.NewInt(int64(len(points)))
+
+	if len(points) == 0 {
+		return nil, nil, fmt.Errorf("no participant to distribute the pool to")
+	}
+
+	if limit != nil && new(big.Int).Mul(limit, n).Cmp(pool) < 0 {
+		return nil, nil, fmt.Errorf("cap %s for %s participants does not cover the pool %s", limit, n, pool)
+	}
+
+	if floor != nil && new(big.Int).Mul(floor, n).Cmp(pool) > 0 {
+		return nil, nil, fmt.Errorf("floor %s for %s participants exceeds the pool %s", floor, n, pool)
+	}
+
+	amounts := make([]*big.Int, len(points))
+	bounds := make([]string, len(points))
+
+	for {
+		remaining := new(big.Int).Set(pool)
+
+		var active []int
+
+		for i := range points {
+			if bounds[i] != "" {
+				remaining.Sub(remaining, amounts[i])
+			} else {
+				active = append(active, i)
+			}
+		}
+
+		if len(active) == 0 {
+			break
+		}
+
+		weights := make([]float64, len(active))
+		for k, i := range active {
+			weights[k] = points[i]
+		}
+
+		shares := largestRemainder(remaining, weights)
+
+		bounded := false
+
+		for k, i := range active {
+			amounts[i] = shares[k]
+
+			if limit != nil && shares[k].Cmp(limit) > 0 {
+				amounts[i], bounds[i], bounded = new(big.Int).Set(limit), BOUND_CAP, true
+			}
+
+			if floor != nil && shares[k].Cmp(floor) < 0 {
+				amounts[i], bounds[i], bounded = new(big.Int).Set(floor), BOUND_FLOOR, true
+			}
+		}
+
+		if !bounded {
+			break
+		}
+	}
+
+	return amounts, bounds, nil
+}
+
+// largestRemainder - Shares the total by weight rounding down, the units left are given one by one
+// to the largest remainders, ties go to the first weight
+func largestRemainder(total *big.Int, weights []float64) []*big.Int {
+	sum := new(big.Rat)
+	rats := make([]*big.Rat, len(weights))
+
+	for i, w := range weights {
+		rats[i] = new(big.Rat).SetFloat64(w)
+		sum.Add(sum, rats[i])
+	}
+
+	shares := make([]*big.Int, len(weights))
+	remainders := make([]*big.Rat, len(weights))
+	left := new(big.Int).Set(total)
+
+	for i, w := range rats {
+		exact := new(big.Rat).Mul(new(big.Rat).SetInt(total), w)
+		exact.Quo(exact, sum)
+
+		shares[i] = new(big.Int).Quo(exact.Num(), exact.Denom())
+		remainders[i] = new(big.Rat).Sub(exact, new(big.Rat).SetInt(shares[i]))
+		left.Sub(left, shares[i])
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]].Cmp(remainders[order[j]]) > 0 })
+
+	for k := 0; left.Sign() > 0; k++ {
+		shares[order[k]].Add(shares[order[k]], big.NewInt(1))
+		left.Sub(left, big.NewInt(1))
+	}
+
+	return shares
+}
+
+// GenesisAccount is an account of app_state.accounts (cosmos-sdk 0.37), vesting accounts have the
+// original vesting and the schedule in unix seconds, continuous when the start time is set
+type GenesisAccount struct {
+	Address          string    `json:"address"`
+	Coins            []db.Coin `json:"coins"`
+	SequenceNumber   string    `json:"sequence_number"`
+	AccountNumber    string    `json:"account_number"`
+	OriginalVesting  []db.Coin `json:"original_vesting"`
+	DelegatedFree    []db.Coin `json:"delegated_free"`
+	DelegatedVesting []db.Coin `json:"delegated_vesting"`
+	StartTime        string    `json:"start_time"`
+	EndTime          string    `json:"end_time"`
+}
+
+// GenesisAccounts - Returns the genesis accounts of the allocations, vesting their whole amount
+// when the distribution has a vesting schedule
+func (d *Distribution) GenesisAccounts() []GenesisAccount {
+	var accounts []GenesisAccount
+
+	for _, a := range d.Allocations {
+		coins := []db.Coin{{Denom: d.Denom, Amount: a.Amount}}
+
+		account := GenesisAccount{
+			Address: a.Address, Coins: coins, SequenceNumber: "0", AccountNumber: "0",
+			OriginalVesting: []db.Coin{}, DelegatedFree: []db.Coin{}, DelegatedVesting: []db.Coin{},
+			StartTime: "0", EndTime: "0",
+		}
+
+		if d.Vesting != nil {
+			account.OriginalVesting = coins
+			account.EndTime = strconv.FormatInt(d.Vesting.EndTime.Unix(), 10)
+
+			if !d.Vesting.StartTime.IsZero() {
+				account.StartTime = strconv.FormatInt(d.Vesting.StartTime.Unix(), 10)
+			}
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts
+}
@@ -0,0 +1,179 @@
+package distribution
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/program"
+)
+
+// operator of the account bytes 1 to 20, see address/address_test.go
+const (
+	testValOper = "xrn:valoper1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc500g569"
+	testAccount = "xrn:1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5eye2ar"
+)
+
+func amountStrings(amounts []*big.Int) []string {
+	var result []string
+	for _, a := range amounts {
+		result = append(result, a.String())
+	}
+
+	return result
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    string
+		points  []float64
+		floor   string
+		limit   string
+		amounts []string
+		bounds  []string
+		wantErr bool
+	}{
+		{
+			name: "ties go to the first", pool: "100", points: []float64{1, 1, 1},
+			amounts: []string{"34", "33", "33"}, bounds: []string{"", "", ""},
+		},
+		{
+			name: "largest remainder", pool: "10", points: []float64{1.4, 2.6, 6},
+			amounts: []string{"1", "3", "6"}, bounds: []string{"", "", ""},
+		},
+		{
+			name: "capped shares go to the others", pool: "100", points: []float64{8, 1, 1}, limit: "50",
+			amounts: []string{"50", "25", "25"}, bounds: []string{BOUND_CAP, "", ""},
+		},
+		{
+			name: "caps cascade", pool: "100", points: []float64{60, 30, 5, 5}, limit: "30",
+			amounts: []string{"30", "30", "20", "20"}, bounds: []string{BOUND_CAP, BOUND_CAP, "", ""},
+		},
+		{
+			name: "floor is taken from the others", pool: "100", points: []float64{98, 1, 1}, floor: "10",
+			amounts: []string{"80", "10", "10"}, bounds: []string{"", BOUND_FLOOR, BOUND_FLOOR},
+		},
+		{name: "cap does not cover the pool", pool: "100", points: []float64{1, 1}, limit: "49", wantErr: true},
+		{name: "floor exceeds the pool", pool: "100", points: []float64{1, 1}, floor: "51", wantErr: true},
+		{name: "no participants", pool: "100", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amounts, bounds, err := allocate(amount(tt.pool), tt.points, amount(tt.floor), amount(tt.limit))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("allocate error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if got := amountStrings(amounts); !reflect.DeepEqual(got, tt.amounts) {
+				t.Errorf("amounts = %v, want %v", got, tt.amounts)
+			}
+
+			if !reflect.DeepEqual(bounds, tt.bounds) {
+				t.Errorf("bounds = %q, want %q", bounds, tt.bounds)
+			}
+		})
+	}
+}
+
+// TestAllocateSumsToPool - The amounts sum exactly to the pool for every formula, whatever the
+// rounding of the points
+func TestAllocateSumsToPool(t *testing.T) {
+	pool := amount("1000000007")
+
+	for n := 1; n <= 40; n++ {
+		points := make([]float64, n)
+		for i := range points {
+			points[i] = float64((i*7919)%97) + 1.0/float64(i+3)
+		}
+
+		perHead := new(big.Int).Quo(pool, big.NewInt(int64(n)))
+
+		formulas := map[string][2]*big.Int{
+			FORMULA_PROPORTIONAL: {nil, nil},
+			FORMULA_CAPPED:       {nil, new(big.Int).Add(perHead, big.NewInt(1))},
+			FORMULA_FLOOR:        {new(big.Int).Quo(perHead, big.NewInt(2)), nil},
+		}
+
+		for formula, bounds := range formulas {
+			amounts, _, err := allocate(pool, points, bounds[0], bounds[1])
+			if err != nil {
+				t.Fatalf("%s of %d participants: %v", formula, n, err)
+			}
+
+			total := new(big.Int)
+			for _, a := range amounts {
+				total.Add(total, a)
+			}
+
+			if total.Cmp(pool) != 0 {
+				t.Errorf("%s of %d participants allocated %s of %s", formula, n, total, pool)
+			}
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	standings := &program.Standings{
+		Program: "test",
+		Standings: []program.Standing{
+			{Rank: 1, Identity: "alice", Total: 300, Operators: []program.PhaseOperator{{Operator: "xrn:valoper1old"}, {Operator: testValOper}}},
+			{Rank: 2, Identity: "bob", Total: 100},
+			{Rank: 3, Identity: "carol", Total: 5},
+			{Rank: 4, Identity: "dave", Total: 50},
+		},
+	}
+
+	c := &Config{
+		Pool: "1000", Denom: "utree", Formula: FORMULA_PROPORTIONAL, MinPoints: 10,
+		Addresses: map[string]string{"bob": "xrn:1bob"},
+		Vesting:   &Vesting{EndTime: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	d, err := New(c, standings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type allocation struct{ Identity, Address, Amount string }
+
+	var got []allocation
+	for _, a := range d.Allocations {
+		got = append(got, allocation{a.Identity, a.Address, a.Amount})
+	}
+
+	//alice is paid to the account of the latest operator, dave has no address
+	want := []allocation{{"alice", testAccount, "750"}, {"bob", "xrn:1bob", "250"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("allocations = %+v, want %+v", got, want)
+	}
+
+	var excluded []string
+	for _, e := range d.Excluded {
+		excluded = append(excluded, e.Identity)
+	}
+
+	if !reflect.DeepEqual(excluded, []string{"carol", "dave"}) {
+		t.Errorf("excluded = %v, want carol and dave", excluded)
+	}
+
+	if d.Points != 400 {
+		t.Errorf("points = %f, want 400", d.Points)
+	}
+
+	accounts := d.GenesisAccounts()
+	if len(accounts) != 2 || accounts[0].EndTime != "1609459200" || accounts[0].StartTime != "0" ||
+		!reflect.DeepEqual(accounts[0].OriginalVesting, accounts[0].Coins) {
+		t.Errorf("genesis accounts = %+v, want delayed vesting until 2021", accounts)
+	}
+
+	//Two identities can not be paid to the same address
+	c.Addresses["dave"] = "xrn:1bob"
+
+	if _, err := New(c, standings); err == nil {
+		t.Error("New paid two identities to the same address")
+	}
+}
@@ -0,0 +1,79 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+type Account struct {
+	Address string    `json:"address"`
+	Coins   []db.Coin `json:"coins"`
+}
+
+// Accounts are stored either in app_state.accounts (cosmos-sdk <= 0.37)
+// or as typed accounts in app_state.auth.accounts
+type authState struct {
+	Accounts []json.RawMessage `json:"accounts"`
+}
+
+type typedAccount struct {
+	Type  string  `json:"type"`
+	Value Account `json:"value"`
+}
+
+// Accounts - Returns the genesis accounts of both account layouts
+func (g *Genesis) Accounts() ([]Account, error) {
+	var accounts []Account
+
+	if _, err := g.Module("accounts", &accounts); err != nil {
+		return nil, err
+	}
+
+	var auth authState
+
+	if _, err := g.Module("auth", &auth); err != nil {
+		return nil, err
+	}
+
+	for _, raw := range auth.Accounts {
+		var acc typedAccount
+		if err := json.Unmarshal(raw, &acc); err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, acc.Value)
+	}
+
+	return accounts, nil
+}
+
+// ParseAmount - Parses a coin amount, amounts are integers of the smallest denom unit
+func ParseAmount(amount string) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid coin amount %q", amount)
+	}
+
+	return value, nil
+}
+
+// SumCoins - Sums the given coins by denom
+func SumCoins(total map[string]*big.Int, coins []db.Coin) error {
+	for _, coin := range coins {
+		amount, err := ParseAmount(coin.Amount)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := total[coin.Denom]; !ok {
+			total[coin.Denom] = new(big.Int)
+		}
+
+		total[coin.Denom].Add(total[coin.Denom], amount)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package genesis
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// Change is a single difference between two genesis summaries,
+// an empty value means the key is missing in that genesis
+type Change struct {
+	Key string `json:"key"`
+	A   string `json:"a"`
+	B   string `json:"b"`
+}
+
+// Diff - Compares two genesis summaries and returns the changed
+// metadata, supply and params sorted by key
+func Diff(a, b *Summary) []Change {
+	var changes []Change
+
+	add := func(key, valueA, valueB string) {
+		if valueA != valueB {
+			changes = append(changes, Change{Key: key, A: valueA, B: valueB})
+		}
+	}
+
+	add("chain_id", a.ChainID, b.ChainID)
+	add("genesis_time", a.GenesisTime, b.GenesisTime)
+	add("accounts", strconv.Itoa(a.Accounts), strconv.Itoa(b.Accounts))
+	add("validators", strconv.Itoa(a.Validators), strconv.Itoa(b.Validators))
+
+	for _, denom := range unionKeys(a.Supply, b.Supply) {
+		add("supply."+denom, amountString(a.Supply[denom]), amountString(b.Supply[denom]))
+	}
+
+	keys := make(map[string]bool)
+
+	for key := range a.Params {
+		keys[key] = true
+	}
+
+	for key := range b.Params {
+		keys[key] = true
+	}
+
+	var paramKeys []string
+
+	for key := range keys {
+		paramKeys = append(paramKeys, key)
+	}
+
+	sort.Strings(paramKeys)
+
+	for _, key := range paramKeys {
+		add(key, a.Params[key], b.Params[key])
+	}
+
+	return changes
+}
+
+func unionKeys(a, b map[string]*big.Int) []string {
+	var keys []string
+
+	for key := range a {
+		keys = append(keys, key)
+	}
+
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func amountString(amount *big.Int) string {
+	if amount == nil {
+		return ""
+	}
+
+	return amount.String()
+}
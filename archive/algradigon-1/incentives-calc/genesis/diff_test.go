@@ -0,0 +1,38 @@
+package genesis
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := &Summary{
+		ChainID: "test-1", GenesisTime: "2020-04-20T10:00:00Z", Accounts: 2, Validators: 1,
+		Supply: map[string]*big.Int{"utree": big.NewInt(10), "uxrn": big.NewInt(7)},
+		Params: map[string]string{"staking.bond_denom": "utree", "mint.inflation_max": "0.2"},
+	}
+
+	b := &Summary{
+		ChainID: "test-2", GenesisTime: "2020-04-20T10:00:00Z", Accounts: 3, Validators: 1,
+		Supply: map[string]*big.Int{"utree": big.NewInt(10), "useed": big.NewInt(1)},
+		Params: map[string]string{"staking.bond_denom": "utree", "gov.voting_period": "60s"},
+	}
+
+	want := []Change{
+		{Key: "chain_id", A: "test-1", B: "test-2"},
+		{Key: "accounts", A: "2", B: "3"},
+		{Key: "supply.useed", A: "", B: "1"},
+		{Key: "supply.uxrn", A: "7", B: ""},
+		{Key: "gov.voting_period", A: "", B: "60s"},
+		{Key: "mint.inflation_max", A: "0.2", B: ""},
+	}
+
+	if got := Diff(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := Diff(a, a); got != nil {
+		t.Errorf("Diff of the same summary = %+v", got)
+	}
+}
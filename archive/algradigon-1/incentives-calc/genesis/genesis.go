@@ -0,0 +1,148 @@
+package genesis
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// Genesis keeps the module states raw, as their layout differs between the
+// cosmos-sdk versions used by the testnets
+type Genesis struct {
+	GenesisTime     string                     `json:"genesis_time"`
+	ChainID         string                     `json:"chain_id"`
+	ConsensusParams json.RawMessage            `json:"consensus_params"`
+	Validators      []Validator                `json:"validators"`
+	AppState        map[string]json.RawMessage `json:"app_state"`
+}
+
+type Validator struct {
+	Address string `json:"address"`
+	PubKey  PubKey `json:"pub_key"`
+	Power   string `json:"power"`
+	Name    string `json:"name"`
+}
+
+// PubKey is an amino JSON public key, the value is base64 encoded
+type PubKey struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type Genutil struct {
+	Gentxs []Gentx `json:"gentxs"`
+}
+
+type Gentx struct {
+	Type  string     `json:"type"`
+	Value GentxValue `json:"value"`
+}
+
+type GentxValue struct {
+	Msg        []db.Msg         `json:"msg"`
+	Signatures []GentxSignature `json:"signatures"`
+	Memo       string           `json:"memo"`
+}
+
+type GentxSignature struct {
+	PubKey PubKey `json:"pub_key"`
+}
+
+// Load - Reads and decodes a genesis file
+func Load(path string) (*Genesis, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	gen := &Genesis{}
+	if err := json.Unmarshal(bz, gen); err != nil {
+		return nil, err
+	}
+
+	return gen, nil
+}
+
+// Module - Decodes the state of a module into v, returns false if the module is not present
+func (g *Genesis) Module(name string, v interface{}) (bool, error) {
+	raw, ok := g.AppState[name]
+	if !ok || string(raw) == "null" {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(raw, v)
+}
+
+// Gentxs - Returns the gentxs of the genesis, if any
+func (g *Genesis) Gentxs() ([]Gentx, error) {
+	var genutil Genutil
+
+	if _, err := g.Module("genutil", &genutil); err != nil {
+		return nil, err
+	}
+
+	return genutil.Gentxs, nil
+}
+
+// CreateValidatorMsgs - Returns create validator messages from all the gentxs
+func (g *Genesis) CreateValidatorMsgs() ([]db.Msg, error) {
+	gentxs, err := g.Gentxs()
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []db.Msg
+
+	for _, tx := range gentxs {
+		for _, msg := range tx.Value.Msg {
+			if msg.Type == db.MSG_CREATE_VALIDATOR {
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+
+	return msgs, nil
+}
+
+// ConsAddresses - Returns the unique consensus addresses of the genesis validators and of the
+// gentxs, a validator listed in both is counted once. Pubkeys which can not be converted are
+// kept as they are
+func (g *Genesis) ConsAddresses() (map[string]bool, error) {
+	consAddresses := make(map[string]bool)
+
+	for _, v := range g.Validators {
+		consAddress := strings.ToUpper(v.Address)
+
+		if pubKey, err := base64.StdEncoding.DecodeString(v.PubKey.Value); err == nil {
+			if hexAddress, err := address.Ed25519PubKeyToHexAddress(pubKey); err == nil {
+				consAddress = hexAddress
+			}
+		}
+
+		if consAddress == "" {
+			consAddress = v.PubKey.Value
+		}
+
+		consAddresses[consAddress] = true
+	}
+
+	msgs, err := g.CreateValidatorMsgs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, msg := range msgs {
+		consAddress, err := address.ConsPubKeyToHexAddress(msg.Value.Pubkey)
+		if err != nil {
+			consAddress = msg.Value.Pubkey
+		}
+
+		consAddresses[consAddress] = true
+	}
+
+	return consAddresses, nil
+}
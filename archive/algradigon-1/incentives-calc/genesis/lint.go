@@ -0,0 +1,284 @@
+package genesis
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// CheckResult is the outcome of a single lint check, the check passed if there are no errors
+type CheckResult struct {
+	Name   string   `json:"name"`
+	Errors []string `json:"errors"`
+}
+
+func (c *CheckResult) Passed() bool {
+	return len(c.Errors) == 0
+}
+
+func (c *CheckResult) errorf(format string, args ...interface{}) {
+	c.Errors = append(c.Errors, fmt.Sprintf(format, args...))
+}
+
+type LintOptions struct {
+	// Reference time the genesis time should be after, usually the release time
+	Reference time.Time
+	// Checksum file in sha256sum format, skipped when empty
+	ChecksumFile string
+}
+
+type supplyState struct {
+	Supply []db.Coin `json:"supply"`
+}
+
+// Lint - Runs all the sanity checks on a genesis file
+func Lint(path string, opts LintOptions) ([]CheckResult, error) {
+	gen, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := gen.Accounts()
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := Summarize(gen)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []CheckResult{
+		checkSupply(gen, summary),
+		checkGentxDelegations(gen, accounts),
+		checkDuplicateAccounts(accounts),
+		checkBondDenom(gen, summary),
+		checkGenesisTime(gen, opts.Reference),
+	}
+
+	if opts.ChecksumFile != "" {
+		results = append(results, checkChecksum(path, opts.ChecksumFile))
+	}
+
+	return results, nil
+}
+
+// checkSupply - Total supply, when set, must equal the sum of all the account balances
+func checkSupply(gen *Genesis, summary *Summary) CheckResult {
+	result := CheckResult{Name: "supply matches balances"}
+
+	var supply supplyState
+
+	if _, err := gen.Module("supply", &supply); err != nil {
+		result.errorf("invalid supply state: %v", err)
+		return result
+	}
+
+	//An empty supply is computed by the chain from the balances at init
+	if len(supply.Supply) == 0 {
+		return result
+	}
+
+	total := make(map[string]*big.Int)
+
+	if err := SumCoins(total, supply.Supply); err != nil {
+		result.errorf("invalid supply: %v", err)
+		return result
+	}
+
+	for _, denom := range unionKeys(total, summary.Supply) {
+		if amountString(total[denom]) != amountString(summary.Supply[denom]) {
+			result.errorf("supply of %s is %s, balances sum to %s", denom,
+				orZero(total[denom]), orZero(summary.Supply[denom]))
+		}
+	}
+
+	return result
+}
+
+// checkGentxDelegations - Self delegation of every gentx must be covered by the delegator balance
+func checkGentxDelegations(gen *Genesis, accounts []Account) CheckResult {
+	result := CheckResult{Name: "gentx delegations covered"}
+
+	msgs, err := gen.CreateValidatorMsgs()
+	if err != nil {
+		result.errorf("invalid gentxs: %v", err)
+		return result
+	}
+
+	balances := make(map[string]map[string]*big.Int)
+
+	for _, acc := range accounts {
+		if _, ok := balances[acc.Address]; !ok {
+			balances[acc.Address] = make(map[string]*big.Int)
+		}
+
+		if err := SumCoins(balances[acc.Address], acc.Coins); err != nil {
+			result.errorf("account %s: %v", acc.Address, err)
+		}
+	}
+
+	//Delegators with several gentxs need to cover all of them
+	delegated := make(map[string]map[string]*big.Int)
+
+	for _, msg := range msgs {
+		delegator := msg.Value.DelegatorAddress
+
+		balance, ok := balances[delegator]
+		if !ok {
+			result.errorf("gentx of %s: delegator %s has no genesis account",
+				msg.Value.Description.Moniker, delegator)
+			continue
+		}
+
+		if _, ok := delegated[delegator]; !ok {
+			delegated[delegator] = make(map[string]*big.Int)
+		}
+
+		if err := SumCoins(delegated[delegator], []db.Coin{msg.Value.Value}); err != nil {
+			result.errorf("gentx of %s: %v", msg.Value.Description.Moniker, err)
+			continue
+		}
+
+		denom := msg.Value.Value.Denom
+		if balance[denom] == nil || delegated[delegator][denom].Cmp(balance[denom]) > 0 {
+			result.errorf("gentx of %s: delegation of %s%s exceeds balance %s%s of %s",
+				msg.Value.Description.Moniker, delegated[delegator][denom], denom,
+				orZero(balance[denom]), denom, delegator)
+		}
+	}
+
+	return result
+}
+
+// checkDuplicateAccounts - Every address must have a single genesis account
+func checkDuplicateAccounts(accounts []Account) CheckResult {
+	result := CheckResult{Name: "no duplicate accounts"}
+
+	seen := make(map[string]bool)
+
+	for _, acc := range accounts {
+		if seen[acc.Address] {
+			result.errorf("duplicate account %s", acc.Address)
+		}
+
+		seen[acc.Address] = true
+	}
+
+	return result
+}
+
+// checkBondDenom - Staking bond denom must be used by mint, crisis, gov deposits and gentxs
+func checkBondDenom(gen *Genesis, summary *Summary) CheckResult {
+	result := CheckResult{Name: "bond denom consistent"}
+
+	bondDenom, ok := summary.Params["staking.bond_denom"]
+	if !ok {
+		result.errorf("staking bond_denom is not set")
+		return result
+	}
+
+	for _, key := range []string{"mint.mint_denom", "crisis.constant_fee.denom"} {
+		if denom, ok := summary.Params[key]; ok && denom != bondDenom {
+			result.errorf("%s is %s, bond denom is %s", key, denom, bondDenom)
+		}
+	}
+
+	msgs, err := gen.CreateValidatorMsgs()
+	if err != nil {
+		result.errorf("invalid gentxs: %v", err)
+		return result
+	}
+
+	for _, msg := range msgs {
+		if msg.Value.Value.Denom != bondDenom {
+			result.errorf("gentx of %s delegates %s, bond denom is %s",
+				msg.Value.Description.Moniker, msg.Value.Value.Denom, bondDenom)
+		}
+	}
+
+	return result
+}
+
+// checkGenesisTime - Genesis time must be after the reference time
+func checkGenesisTime(gen *Genesis, reference time.Time) CheckResult {
+	result := CheckResult{Name: "genesis time in future"}
+
+	genesisTime, err := time.Parse(time.RFC3339Nano, gen.GenesisTime)
+	if err != nil {
+		result.errorf("invalid genesis_time %q: %v", gen.GenesisTime, err)
+		return result
+	}
+
+	if !genesisTime.After(reference) {
+		result.errorf("genesis_time %s is not after %s", genesisTime.UTC().Format(time.RFC3339),
+			reference.UTC().Format(time.RFC3339))
+	}
+
+	return result
+}
+
+// checkChecksum - The sha256 of the genesis must match the checksum file
+func checkChecksum(path, checksumFile string) CheckResult {
+	result := CheckResult{Name: "checksum matches"}
+
+	expected, err := readChecksum(checksumFile)
+	if err != nil {
+		result.errorf("%v", err)
+		return result
+	}
+
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		result.errorf("%v", err)
+		return result
+	}
+
+	hash := sha256.Sum256(bz)
+
+	if actual := hex.EncodeToString(hash[:]); actual != expected {
+		result.errorf("sha256 of %s is %s, %s has %s", path, actual, checksumFile, expected)
+	}
+
+	return result
+}
+
+// readChecksum - Reads the hash from a file of `sha256sum` output
+func readChecksum(checksumFile string) (string, error) {
+	file, err := os.Open(checksumFile)
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	if scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no checksum found in %s", checksumFile)
+}
+
+func orZero(amount *big.Int) string {
+	if amount == nil {
+		return "0"
+	}
+
+	return amount.String()
+}
@@ -0,0 +1,237 @@
+package genesis
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// ed25519 key of bytes 0 to 31 as a valconspub, see address/address_test.go
+const testConsPub = "xrn:valconspub1zcjduepqqqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0ssl033y"
+
+var lintReference = time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+
+// testGenesis - A genesis passing every lint check: two accounts, a supply matching their balances and
+// a gentx of val1 delegating its whole balance
+func testGenesis() map[string]interface{} {
+	pubKey := make([]byte, 32)
+	for i := range pubKey {
+		pubKey[i] = byte(i)
+	}
+
+	return map[string]interface{}{
+		"genesis_time": "2020-04-20T10:00:00Z",
+		"chain_id":     "test-1",
+		"validators": []interface{}{
+			map[string]interface{}{
+				"pub_key": map[string]string{"type": "tendermint/PubKeyEd25519", "value": base64.StdEncoding.EncodeToString(pubKey)},
+				"power":   "10",
+			},
+		},
+		"app_state": map[string]interface{}{
+			"accounts": []interface{}{
+				map[string]interface{}{"address": "val1", "coins": []interface{}{coin("utree", "10000000")}},
+				map[string]interface{}{"address": "faucet", "coins": []interface{}{coin("utree", "5"), coin("uxrn", "7")}},
+			},
+			"supply":  map[string]interface{}{"supply": []interface{}{coin("utree", "10000005"), coin("uxrn", "7")}},
+			"staking": map[string]interface{}{"params": map[string]interface{}{"bond_denom": "utree"}},
+			"mint":    map[string]interface{}{"params": map[string]interface{}{"mint_denom": "utree"}},
+			"genutil": map[string]interface{}{"gentxs": []interface{}{gentx("val1", "val1", coin("utree", "10000000"))}},
+		},
+	}
+}
+
+func coin(denom, amount string) map[string]string {
+	return map[string]string{"denom": denom, "amount": amount}
+}
+
+func gentx(moniker, delegator string, value map[string]string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "cosmos-sdk/StdTx",
+		"value": map[string]interface{}{
+			"msg": []interface{}{
+				map[string]interface{}{
+					"type": "cosmos-sdk/MsgCreateValidator",
+					"value": map[string]interface{}{
+						"description":       map[string]string{"moniker": moniker},
+						"delegator_address": delegator,
+						"pubkey":            testConsPub,
+						"value":             value,
+					},
+				},
+			},
+		},
+	}
+}
+
+func appState(g map[string]interface{}) map[string]interface{} {
+	return g["app_state"].(map[string]interface{})
+}
+
+func writeGenesis(t *testing.T, dir string, g map[string]interface{}) string {
+	bz, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "genesis.json")
+
+	if err := ioutil.WriteFile(path, bz, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(g map[string]interface{})
+		checksum func(path string) string
+		failed   map[string]string
+	}{
+		{
+			name:   "valid",
+			failed: map[string]string{},
+		},
+		{
+			name: "supply does not match the balances",
+			mutate: func(g map[string]interface{}) {
+				appState(g)["supply"] = map[string]interface{}{"supply": []interface{}{coin("utree", "10000005")}}
+			},
+			failed: map[string]string{"supply matches balances": "supply of uxrn is 0, balances sum to 7"},
+		},
+		{
+			name: "gentx delegations exceed the balance",
+			mutate: func(g map[string]interface{}) {
+				appState(g)["genutil"] = map[string]interface{}{"gentxs": []interface{}{
+					gentx("val1", "val1", coin("utree", "6000000")),
+					gentx("val1-bis", "val1", coin("utree", "6000000")),
+					gentx("ghost", "nobody", coin("utree", "1")),
+				}}
+			},
+			failed: map[string]string{
+				"gentx delegations covered": "gentx of val1-bis: delegation of 12000000utree exceeds balance 10000000utree of val1",
+			},
+		},
+		{
+			name: "duplicate account",
+			mutate: func(g map[string]interface{}) {
+				state := appState(g)
+				state["accounts"] = append(state["accounts"].([]interface{}),
+					map[string]interface{}{"address": "faucet", "coins": []interface{}{}})
+			},
+			failed: map[string]string{"no duplicate accounts": "duplicate account faucet"},
+		},
+		{
+			name: "mint denom differs from the bond denom",
+			mutate: func(g map[string]interface{}) {
+				appState(g)["mint"] = map[string]interface{}{"params": map[string]interface{}{"mint_denom": "uxrn"}}
+			},
+			failed: map[string]string{"bond denom consistent": "mint.mint_denom is uxrn, bond denom is utree"},
+		},
+		{
+			name: "genesis time before the reference",
+			mutate: func(g map[string]interface{}) {
+				g["genesis_time"] = "2020-03-01T00:00:00Z"
+			},
+			failed: map[string]string{
+				"genesis time in future": "genesis_time 2020-03-01T00:00:00Z is not after 2020-04-01T00:00:00Z",
+			},
+		},
+		{
+			name: "checksum matches",
+			checksum: func(path string) string {
+				bz, _ := ioutil.ReadFile(path)
+				hash := sha256.Sum256(bz)
+
+				return fmt.Sprintf("%s  genesis.json\n", hex.EncodeToString(hash[:]))
+			},
+			failed: map[string]string{},
+		},
+		{
+			name: "checksum differs",
+			checksum: func(path string) string {
+				return "0000  genesis.json\n"
+			},
+			failed: map[string]string{"checksum matches": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lint")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer os.RemoveAll(dir)
+
+			g := testGenesis()
+			if tt.mutate != nil {
+				tt.mutate(g)
+			}
+
+			path := writeGenesis(t, dir, g)
+
+			opts := LintOptions{Reference: lintReference}
+
+			if tt.checksum != nil {
+				opts.ChecksumFile = filepath.Join(dir, "genesis.json.sha256")
+
+				if err := ioutil.WriteFile(opts.ChecksumFile, []byte(tt.checksum(path)), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			results, err := Lint(path, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, result := range results {
+				want, fail := tt.failed[result.Name]
+
+				if result.Passed() == fail {
+					t.Errorf("%s passed = %v, errors %v", result.Name, result.Passed(), result.Errors)
+					continue
+				}
+
+				if want != "" && (len(result.Errors) == 0 || result.Errors[0] != want) {
+					t.Errorf("%s errors = %v, want %q", result.Name, result.Errors, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConsAddresses(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lint")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	g, err := Load(writeGenesis(t, dir, testGenesis()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//The genesis validator and the gentx have the same key
+	addresses, err := g.ConsAddresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(addresses) != 1 || !addresses["630DCD2966C4336691125448BBB25B4FF412A49C"] {
+		t.Errorf("ConsAddresses = %v, want the single validator", addresses)
+	}
+}
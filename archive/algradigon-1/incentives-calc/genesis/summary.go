@@ -0,0 +1,179 @@
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Summary is the overview of a genesis file used for inspecting and diffing testnets
+type Summary struct {
+	ChainID     string              `json:"chainId"`
+	GenesisTime string              `json:"genesisTime"`
+	Accounts    int                 `json:"accounts"`
+	Validators  int                 `json:"validators"`
+	Supply      map[string]*big.Int `json:"supply"`
+	Params      map[string]string   `json:"params"`
+}
+
+// Module state which is not a parameter, these are skipped while collecting params
+var stateKeys = map[string]bool{
+	"accounts": true, "gentxs": true, "validators": true, "delegations": true,
+	"unbonding_delegations": true, "redelegations": true, "last_validator_powers": true,
+	"last_total_power": true, "exported": true, "deposits": true, "votes": true,
+	"proposals": true, "signing_infos": true, "missed_blocks": true,
+	"delegator_withdraw_infos": true, "previous_proposer": true, "outstanding_rewards": true,
+	"validator_accumulated_commissions": true, "validator_historical_rewards": true,
+	"validator_current_rewards": true, "delegator_starting_infos": true,
+	"validator_slash_events": true, "fee_pool": true, "pool": true, "minter": true,
+	"evidence": true, "supply": true, "collected_fees": true, "codes": true,
+	"contracts": true, "geometries": true,
+}
+
+// Modules of older testnets which only hold state
+var stateModules = map[string]bool{
+	"accounts": true, "agents": true, "groups": true,
+}
+
+// Older cosmos-sdk versions use different module names
+var moduleAliases = map[string]string{
+	"distr": "distribution",
+}
+
+// Summarize - Builds the summary of a genesis
+func Summarize(g *Genesis) (*Summary, error) {
+	summary := &Summary{
+		ChainID:     g.ChainID,
+		GenesisTime: g.GenesisTime,
+		Supply:      make(map[string]*big.Int),
+		Params:      make(map[string]string),
+	}
+
+	accounts, err := g.Accounts()
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Accounts = len(accounts)
+
+	for _, acc := range accounts {
+		if err := SumCoins(summary.Supply, acc.Coins); err != nil {
+			return nil, fmt.Errorf("account %s: %v", acc.Address, err)
+		}
+	}
+
+	consAddresses, err := g.ConsAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Validators = len(consAddresses)
+
+	if err := flattenParams(summary.Params, "consensus", g.ConsensusParams); err != nil {
+		return nil, err
+	}
+
+	for module, raw := range g.AppState {
+		if stateModules[module] {
+			continue
+		}
+
+		if alias, ok := moduleAliases[module]; ok {
+			module = alias
+		}
+
+		if err := flattenParams(summary.Params, module, raw); err != nil {
+			return nil, fmt.Errorf("module %s: %v", module, err)
+		}
+	}
+
+	return summary, nil
+}
+
+// flattenParams - Collects the params of a module as dotted keys. The "params" level
+// is dropped so that params of different sdk versions share the same keys
+func flattenParams(params map[string]string, prefix string, raw json.RawMessage) error {
+	var value interface{}
+
+	if len(raw) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+
+	flatten(params, prefix, value)
+
+	return nil
+}
+
+func flatten(params map[string]string, prefix string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if stateKeys[key] {
+				continue
+			}
+
+			if key == "params" {
+				flatten(params, prefix, child)
+				continue
+			}
+
+			flatten(params, prefix+"."+toSnakeCase(key), child)
+		}
+	case nil:
+		return
+	default:
+		bz, _ := json.Marshal(v)
+		params[prefix] = strings.Trim(string(bz), `"`)
+	}
+}
+
+// toSnakeCase - Normalizes CamelCase keys of old sdk versions (TxSigLimit -> tx_sig_limit)
+func toSnakeCase(key string) string {
+	var b strings.Builder
+
+	for i, r := range key {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rune(key[i-1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+				b.WriteByte('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// ParamKeys - Returns the sorted param keys
+func (s *Summary) ParamKeys() []string {
+	keys := make([]string, 0, len(s.Params))
+
+	for key := range s.Params {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Denoms - Returns the sorted supply denoms
+func (s *Summary) Denoms() []string {
+	denoms := make([]string, 0, len(s.Supply))
+
+	for denom := range s.Supply {
+		denoms = append(denoms, denom)
+	}
+
+	sort.Strings(denoms)
+
+	return denoms
+}
@@ -0,0 +1,196 @@
+package identity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/genesis"
+)
+
+// Record is a validator of a chain with the keys and description it registered with.
+// Validators listed in the genesis without a gentx only have a consensus address
+type Record struct {
+	ChainID       string `json:"chainId"`
+	Operator      string `json:"operator,omitempty"`
+	Delegator     string `json:"delegator,omitempty"`
+	ConsAddress   string `json:"consAddress,omitempty"`
+	AccountPubKey string `json:"accountPubKey,omitempty"`
+	NodeID        string `json:"nodeId,omitempty"`
+	Moniker       string `json:"moniker"`
+	Keybase       string `json:"keybase,omitempty"`
+	Website       string `json:"website,omitempty"`
+}
+
+// Ref - Identifies the record as <chain-id>/<operator address>, or <chain-id>/<consensus address>
+func (r Record) Ref() string {
+	if r.Operator != "" {
+		return r.ChainID + "/" + r.Operator
+	}
+
+	return r.ChainID + "/" + r.ConsAddress
+}
+
+// CollectChain - Reads the validators of a testnet directory from its genesis.json gentxs and
+// genesis validators, and from the gentxs/ submissions which may not have made it into the genesis
+func CollectChain(dir string) ([]Record, error) {
+	g, err := genesis.Load(filepath.Join(dir, "genesis.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	gentxs, err := g.Gentxs()
+	if err != nil {
+		return nil, err
+	}
+
+	submitted, err := readGentxDir(filepath.Join(dir, "gentxs"))
+	if err != nil {
+		return nil, err
+	}
+
+	byRef := make(map[string]*Record)
+
+	var refs []string
+
+	add := func(record Record) {
+		existing, ok := byRef[record.Ref()]
+		if !ok {
+			refs = append(refs, record.Ref())
+			byRef[record.Ref()] = &record
+			return
+		}
+
+		existing.merge(record)
+	}
+
+	for _, tx := range append(gentxs, submitted...) {
+		for _, record := range gentxRecords(g.ChainID, tx) {
+			add(record)
+		}
+	}
+
+	//Genesis validators without a gentx, such as the validators of the early testnets
+	known := make(map[string]bool)
+
+	for _, ref := range refs {
+		known[byRef[ref].ConsAddress] = true
+	}
+
+	for _, v := range g.Validators {
+		consAddress := strings.ToUpper(v.Address)
+
+		if pubKey, err := base64.StdEncoding.DecodeString(v.PubKey.Value); err == nil {
+			if hexAddress, err := address.Ed25519PubKeyToHexAddress(pubKey); err == nil {
+				consAddress = hexAddress
+			}
+		}
+
+		if !known[consAddress] {
+			add(Record{ChainID: g.ChainID, ConsAddress: consAddress, Moniker: v.Name})
+		}
+	}
+
+	records := make([]Record, 0, len(refs))
+
+	for _, ref := range refs {
+		records = append(records, *byRef[ref])
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Ref() < records[j].Ref() })
+
+	return records, nil
+}
+
+func gentxRecords(chainID string, tx genesis.Gentx) []Record {
+	var records []Record
+
+	nodeID := ""
+	if at := strings.Index(tx.Value.Memo, "@"); at > 0 {
+		nodeID = strings.ToLower(tx.Value.Memo[:at])
+	}
+
+	for i, msg := range tx.Value.Msg {
+		if msg.Type != db.MSG_CREATE_VALIDATOR {
+			continue
+		}
+
+		record := Record{
+			ChainID:   chainID,
+			Operator:  msg.Value.ValidatorAddress,
+			Delegator: msg.Value.DelegatorAddress,
+			NodeID:    nodeID,
+			Moniker:   strings.TrimSpace(msg.Value.Description.Moniker),
+			Keybase:   strings.ToUpper(strings.TrimSpace(msg.Value.Description.Identity)),
+			Website:   strings.TrimSpace(msg.Value.Description.Website),
+		}
+
+		if consAddress, err := address.ConsPubKeyToHexAddress(msg.Value.Pubkey); err == nil {
+			record.ConsAddress = consAddress
+		}
+
+		if i < len(tx.Value.Signatures) {
+			record.AccountPubKey = tx.Value.Signatures[i].PubKey.Value
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// readGentxDir - Reads the gentx submissions of a directory, files which are not gentxs are skipped
+func readGentxDir(dir string) ([]genesis.Gentx, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var gentxs []genesis.Gentx
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var tx genesis.Gentx
+
+		if err := json.Unmarshal(data, &tx); err == nil && len(tx.Value.Msg) > 0 {
+			gentxs = append(gentxs, tx)
+		}
+	}
+
+	return gentxs, nil
+}
+
+// merge - Fills the empty fields of the record, the genesis gentx is read first and wins
+func (r *Record) merge(other Record) {
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+
+	fill(&r.Delegator, other.Delegator)
+	fill(&r.ConsAddress, other.ConsAddress)
+	fill(&r.AccountPubKey, other.AccountPubKey)
+	fill(&r.NodeID, other.NodeID)
+	fill(&r.Moniker, other.Moniker)
+	fill(&r.Keybase, other.Keybase)
+	fill(&r.Website, other.Website)
+}
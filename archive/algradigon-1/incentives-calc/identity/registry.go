@@ -0,0 +1,232 @@
+package identity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Participant groups the validators one team ran across the chains. Members are record refs,
+// <chain-id>/<operator address> or <chain-id>/<consensus address> for genesis validators
+type Participant struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	Keybase string   `json:"keybase,omitempty"`
+	Members []string `json:"members"`
+}
+
+// Rejection records that two refs were reviewed and are not the same participant
+type Rejection struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+// Registry is the manually confirmed identity file, suggestions never change it by themselves
+type Registry struct {
+	Participants []Participant `json:"participants"`
+	Rejected     []Rejection   `json:"rejected,omitempty"`
+}
+
+// LoadRegistry - Reads a registry file, a missing file is an empty registry
+func LoadRegistry(path string) (*Registry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &Registry{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	var r Registry
+
+	if err := decoder.Decode(&r); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if errs := r.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %s", path, strings.Join(errs, "; "))
+	}
+
+	return &r, nil
+}
+
+// Save - Writes the registry with participants and members sorted so diffs stay reviewable
+func (r *Registry) Save(path string) error {
+	sort.Slice(r.Participants, func(i, j int) bool { return r.Participants[i].ID < r.Participants[j].ID })
+
+	for i := range r.Participants {
+		sort.Strings(r.Participants[i].Members)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Validate - Returns every problem found in the registry
+func (r *Registry) Validate() []string {
+	var errs []string
+
+	ids := make(map[string]bool)
+	members := make(map[string]string)
+
+	for i, p := range r.Participants {
+		label := p.ID
+		if label == "" {
+			label = fmt.Sprintf("participant %d", i)
+			errs = append(errs, label+": id is missing")
+		}
+
+		if ids[p.ID] {
+			errs = append(errs, label+": duplicate id")
+		}
+
+		ids[p.ID] = true
+
+		for _, ref := range p.Members {
+			if !validRef(ref) {
+				errs = append(errs, fmt.Sprintf("%s: invalid member %q, expected <chain-id>/<address>", label, ref))
+			}
+
+			if other, ok := members[ref]; ok && other != p.ID {
+				errs = append(errs, fmt.Sprintf("%s: %s is already a member of %s", label, ref, other))
+			}
+
+			members[ref] = p.ID
+		}
+	}
+
+	for _, rejection := range r.Rejected {
+		if !validRef(rejection.A) || !validRef(rejection.B) {
+			errs = append(errs, fmt.Sprintf("invalid rejection %s - %s", rejection.A, rejection.B))
+		}
+	}
+
+	return errs
+}
+
+// Participant - Returns the participant a ref was confirmed for
+func (r *Registry) Participant(ref string) (*Participant, bool) {
+	for i := range r.Participants {
+		for _, member := range r.Participants[i].Members {
+			if member == ref {
+				return &r.Participants[i], true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Resolve - Returns the participant id of an operator of a chain, or false when it isn't confirmed
+func (r *Registry) Resolve(chainID, operator string) (string, bool) {
+	p, ok := r.Participant(chainID + "/" + operator)
+	if !ok {
+		return "", false
+	}
+
+	return p.ID, true
+}
+
+// Confirm - Adds the refs to a participant, creating it when the id is new. A ref
+// which already belongs to another participant is an error, it has to be removed first
+func (r *Registry) Confirm(id, name string, refs []string) error {
+	for _, ref := range refs {
+		if !validRef(ref) {
+			return fmt.Errorf("invalid ref %q, expected <chain-id>/<address>", ref)
+		}
+
+		if other, ok := r.Participant(ref); ok && other.ID != id {
+			return fmt.Errorf("%s is already a member of %s", ref, other.ID)
+		}
+	}
+
+	var p *Participant
+
+	for i := range r.Participants {
+		if r.Participants[i].ID == id {
+			p = &r.Participants[i]
+		}
+	}
+
+	if p == nil {
+		r.Participants = append(r.Participants, Participant{ID: id})
+		p = &r.Participants[len(r.Participants)-1]
+	}
+
+	if name != "" {
+		p.Name = name
+	}
+
+	for _, ref := range refs {
+		if !contains(p.Members, ref) {
+			p.Members = append(p.Members, ref)
+		}
+	}
+
+	return nil
+}
+
+// Reject - Records that two refs are different participants so they aren't suggested again
+func (r *Registry) Reject(a, b string) error {
+	if !validRef(a) || !validRef(b) {
+		return fmt.Errorf("invalid refs %q - %q, expected <chain-id>/<address>", a, b)
+	}
+
+	if !r.Rejects(a, b) {
+		r.Rejected = append(r.Rejected, Rejection{A: a, B: b})
+	}
+
+	return nil
+}
+
+// Rejects - Returns whether the pair was rejected, in either order
+func (r *Registry) Rejects(a, b string) bool {
+	for _, rejection := range r.Rejected {
+		if (rejection.A == a && rejection.B == b) || (rejection.A == b && rejection.B == a) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Linked - Returns whether both refs are members of the same participant
+func (r *Registry) Linked(a, b string) bool {
+	pa, ok := r.Participant(a)
+	if !ok {
+		return false
+	}
+
+	pb, ok := r.Participant(b)
+
+	return ok && pa.ID == pb.ID
+}
+
+func validRef(ref string) bool {
+	parts := strings.SplitN(ref, "/", 2)
+
+	return len(parts) == 2 && parts[0] != "" && parts[1] != ""
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package identity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	r := &Registry{Participants: []Participant{{ID: "p1", Members: []string{"a/x"}}}}
+
+	tests := []struct {
+		name    string
+		id      string
+		label   string
+		refs    []string
+		wantErr bool
+	}{
+		{name: "new participant", id: "p2", label: "Two", refs: []string{"a/y", "b/y"}},
+		{name: "add to a participant", id: "p1", refs: []string{"b/x", "a/x"}},
+		{name: "rename keeps the members", id: "p2", label: "Second"},
+		{name: "member of another participant", id: "p1", refs: []string{"c/x", "b/y"}, wantErr: true},
+		{name: "invalid ref", id: "p3", refs: []string{"a/z", "no-chain"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := r.Confirm(tt.id, tt.label, tt.refs); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Confirm error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	//Failed confirmations leave the registry unchanged
+	want := []Participant{
+		{ID: "p1", Members: []string{"a/x", "b/x"}},
+		{ID: "p2", Name: "Second", Members: []string{"a/y", "b/y"}},
+	}
+
+	if !reflect.DeepEqual(r.Participants, want) {
+		t.Errorf("participants =\n%+v\nwant\n%+v", r.Participants, want)
+	}
+
+	if id, ok := r.Resolve("b", "x"); !ok || id != "p1" {
+		t.Errorf("Resolve(b, x) = %s %v, want p1", id, ok)
+	}
+
+	if errs := r.Validate(); len(errs) != 0 {
+		t.Errorf("confirmed registry is invalid: %v", errs)
+	}
+
+	if !r.Linked("a/y", "b/y") || r.Linked("a/x", "a/y") {
+		t.Error("Linked does not follow the participants")
+	}
+}
@@ -0,0 +1,173 @@
+package identity
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// similarMoniker is the moniker similarity from which monikers are reported as similar
+const similarMoniker = 0.6
+
+// Suggestion is a pair of validators of different chains which are likely the same participant
+type Suggestion struct {
+	A       Record   `json:"a"`
+	B       Record   `json:"b"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// Suggest - Compares the validators of different chains and returns the pairs scoring at least
+// minScore which the registry neither links nor rejects, best matches first. Shared keys are
+// near certain matches, a shared keybase identity or node id is strong, a similar moniker is a hint
+func Suggest(records []Record, registry *Registry, minScore float64) []Suggestion {
+	var suggestions []Suggestion
+
+	for i := 0; i < len(records); i++ {
+		for j := i + 1; j < len(records); j++ {
+			a, b := records[i], records[j]
+
+			if a.ChainID == b.ChainID {
+				continue
+			}
+
+			if registry.Linked(a.Ref(), b.Ref()) || registry.Rejects(a.Ref(), b.Ref()) {
+				continue
+			}
+
+			score, reasons := match(a, b)
+			if score < minScore || len(reasons) == 0 {
+				continue
+			}
+
+			suggestions = append(suggestions, Suggestion{A: a, B: b, Score: score, Reasons: reasons})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+
+		return suggestions[i].A.Ref()+suggestions[i].B.Ref() < suggestions[j].A.Ref()+suggestions[j].B.Ref()
+	})
+
+	return suggestions
+}
+
+// match - Returns the best evidence score of the pair with every reason found
+func match(a, b Record) (float64, []string) {
+	var reasons []string
+
+	score := 0.0
+
+	add := func(s float64, reason string) {
+		reasons = append(reasons, reason)
+		if s > score {
+			score = s
+		}
+	}
+
+	if a.ConsAddress != "" && a.ConsAddress == b.ConsAddress {
+		add(1, "same consensus key")
+	}
+
+	if a.AccountPubKey != "" && a.AccountPubKey == b.AccountPubKey {
+		add(1, "same account key")
+	}
+
+	if a.Delegator != "" && a.Delegator == b.Delegator {
+		add(1, "same delegator address")
+	}
+
+	if a.Keybase != "" && a.Keybase == b.Keybase {
+		add(0.9, "same keybase identity")
+	}
+
+	if a.NodeID != "" && a.NodeID == b.NodeID {
+		add(0.8, "same node id")
+	}
+
+	if similarity := MonikerSimilarity(a.Moniker, b.Moniker); similarity >= similarMoniker {
+		if similarity == 1 {
+			reasons = append(reasons, "same moniker")
+		} else {
+			reasons = append(reasons, "similar moniker")
+		}
+
+		if similarity > score {
+			score = similarity
+		}
+	}
+
+	return score, reasons
+}
+
+// MonikerSimilarity - Returns 1 minus the edit distance of the normalized monikers over the
+// longest one, 0 when either is empty
+func MonikerSimilarity(a, b string) float64 {
+	a, b = normalizeMoniker(a), normalizeMoniker(b)
+	if a == "" || b == "" {
+		return 0
+	}
+
+	ra, rb := []rune(a), []rune(b)
+
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// normalizeMoniker - Lowercases and keeps letters and digits, so "Foo-Validator" and "foo validator" match
+func normalizeMoniker(moniker string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(moniker) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,87 @@
+package identity
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMonikerSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Foo-Validator", "foo validator", 1},
+		{"alpha", "alpha1", 1 - 1.0/6},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"abc", "xyz", 0},
+		{"", "abc", 0},
+		{"--", "--", 0},
+	}
+
+	for _, tt := range tests {
+		if got := MonikerSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("MonikerSimilarity(%q, %q) = %f, want %f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	records := []Record{
+		{ChainID: "a", Operator: "a1", Moniker: "Foo", Keybase: "K1"},
+		{ChainID: "a", Operator: "a2", Moniker: "Bar Node", ConsAddress: "C1"},
+		{ChainID: "a", Operator: "a3", Moniker: "qux", Keybase: "K2"},
+		{ChainID: "b", Operator: "b1", Moniker: "foo"},
+		{ChainID: "b", Operator: "b2", Moniker: "other", ConsAddress: "C1"},
+		{ChainID: "b", Operator: "b3", Moniker: "Bar Nodes", Keybase: "K1"},
+		{ChainID: "b", ConsAddress: "C2", Moniker: "qux"},
+	}
+
+	//a2 and b2 are confirmed, a1 and b1 were reviewed and rejected
+	registry := &Registry{
+		Participants: []Participant{{ID: "bar", Members: []string{"a/a2", "b/b2"}}},
+		Rejected:     []Rejection{{A: "b/b1", B: "a/a1"}},
+	}
+
+	type pair struct {
+		A, B    string
+		Reasons []string
+	}
+
+	tests := []struct {
+		name     string
+		minScore float64
+		want     []pair
+	}{
+		{
+			name:     "best matches first",
+			minScore: 0.85,
+			want: []pair{
+				{"a/a3", "b/C2", []string{"same moniker"}},
+				{"a/a1", "b/b3", []string{"same keybase identity"}},
+				{"a/a2", "b/b3", []string{"similar moniker"}},
+			},
+		},
+		{
+			name:     "above the minimum score",
+			minScore: 0.9,
+			want: []pair{
+				{"a/a3", "b/C2", []string{"same moniker"}},
+				{"a/a1", "b/b3", []string{"same keybase identity"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []pair
+			for _, s := range Suggest(records, registry, tt.minScore) {
+				got = append(got, pair{s.A.Ref(), s.B.Ref(), s.Reasons})
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggestions =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+// Entry is a ranked validator, the rank is by total points whatever the sort order
+type Entry struct {
+	Rank int `json:"rank"`
+	src.ValidatorInfo
+}
+
+// Detail is an entry with the breakdown of its points
+type Detail struct {
+	Entry
+	Breakdown []src.PointsItem `json:"breakdown"`
+}
+
+// sortKeys - Ascending order of the sortable columns
+var sortKeys = map[string]func(a, b src.Info) bool{
+	"total":    func(a, b src.Info) bool { return a.TotalPoints < b.TotalPoints },
+	"uptime":   func(a, b src.Info) bool { return a.UptimePoints < b.UptimePoints },
+	"upgrade1": func(a, b src.Info) bool { return a.Upgrade1Points < b.Upgrade1Points },
+	"upgrade2": func(a, b src.Info) bool { return a.Upgrade2Points < b.Upgrade2Points },
+	"proposals": func(a, b src.Info) bool {
+		return a.Proposal1VoteScore+a.Proposal2VoteScore < b.Proposal1VoteScore+b.Proposal2VoteScore
+	},
+	"genesis":     func(a, b src.Info) bool { return a.GenesisPoints < b.GenesisPoints },
+	"adjustments": func(a, b src.Info) bool { return a.AdjustmentPoints < b.AdjustmentPoints },
+	"moniker":     func(a, b src.Info) bool { return strings.ToLower(a.Moniker) < strings.ToLower(b.Moniker) },
+	"operator":    func(a, b src.Info) bool { return a.OperatorAddr < b.OperatorAddr },
+}
+
+// Server serves the results read-only as HTML pages and a JSON API
+type Server struct {
+	results *src.Results
+	entries []Entry
+	mux     *http.ServeMux
+}
+
+func NewServer(results *src.Results) *Server {
+	s := &Server{results: results, mux: http.NewServeMux()}
+
+	for _, v := range results.Validators {
+		s.entries = append(s.entries, Entry{ValidatorInfo: v})
+	}
+
+	sort.SliceStable(s.entries, func(i, j int) bool {
+		return s.entries[i].Info.TotalPoints > s.entries[j].Info.TotalPoints
+	})
+
+	for i := range s.entries {
+		s.entries[i].Rank = i + 1
+	}
+
+	s.mux.HandleFunc("/", s.handleLeaderboard)
+	s.mux.HandleFunc("/validators/", s.handleValidator)
+	s.mux.HandleFunc("/api/validators", s.handleAPIValidators)
+	s.mux.HandleFunc("/api/validators/", s.handleAPIValidator)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// Query - Filters the entries by a case insensitive moniker or operator address match and
+// sorts them by the given column, the default is total points descending
+func (s *Server) Query(filter, sortBy, order string) ([]Entry, bool) {
+	less, ok := sortKeys[sortBy]
+	if sortBy == "" {
+		less, ok = sortKeys["total"], true
+	}
+
+	if !ok {
+		return nil, false
+	}
+
+	filter = strings.ToLower(strings.TrimSpace(filter))
+
+	var entries []Entry
+
+	for _, entry := range s.entries {
+		if filter == "" || strings.Contains(strings.ToLower(entry.Info.Moniker), filter) ||
+			strings.Contains(strings.ToLower(entry.Info.OperatorAddr), filter) {
+			entries = append(entries, entry)
+		}
+	}
+
+	//Text columns default to ascending, point columns to descending
+	desc := order == "desc" || (order == "" && sortBy != "moniker" && sortBy != "operator")
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if desc {
+			return less(entries[j].Info, entries[i].Info)
+		}
+
+		return less(entries[i].Info, entries[j].Info)
+	})
+
+	return entries, true
+}
+
+// Find - Returns the entry of an operator address with its points breakdown
+func (s *Server) Find(operator string) (Detail, bool) {
+	for _, entry := range s.entries {
+		if entry.Info.OperatorAddr == operator {
+			return Detail{Entry: entry, Breakdown: s.results.Breakdown(entry.ValidatorInfo)}, true
+		}
+	}
+
+	return Detail{}, false
+}
+
+func (s *Server) handleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	q := r.URL.Query()
+
+	entries, ok := s.Query(q.Get("q"), q.Get("sort"), q.Get("order"))
+	if !ok {
+		http.Error(w, "unknown sort column "+q.Get("sort"), http.StatusBadRequest)
+		return
+	}
+
+	render(w, leaderboardTemplate, map[string]interface{}{
+		"Results": s.results,
+		"Entries": entries,
+		"Filter":  q.Get("q"),
+		"Sort":    q.Get("sort"),
+	})
+}
+
+func (s *Server) handleValidator(w http.ResponseWriter, r *http.Request) {
+	detail, ok := s.Find(strings.TrimPrefix(r.URL.Path, "/validators/"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	render(w, validatorTemplate, detail)
+}
+
+func (s *Server) handleAPIValidators(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	entries, ok := s.Query(q.Get("q"), q.Get("sort"), q.Get("order"))
+	if !ok {
+		http.Error(w, "unknown sort column "+q.Get("sort"), http.StatusBadRequest)
+		return
+	}
+
+	if entries == nil {
+		entries = []Entry{}
+	}
+
+	writeJSON(w, entries)
+}
+
+func (s *Server) handleAPIValidator(w http.ResponseWriter, r *http.Request) {
+	detail, ok := s.Find(strings.TrimPrefix(r.URL.Path, "/api/validators/"))
+	if !ok {
+		http.Error(w, "validator not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, detail)
+}
+
+func render(w http.ResponseWriter, tmpl *template.Template, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := tmpl.Execute(w, data); err != nil {
+		log.Printf("Error while rendering %s: %v", tmpl.Name(), err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(v); err != nil {
+		log.Printf("Error while writing response: %v", err)
+	}
+}
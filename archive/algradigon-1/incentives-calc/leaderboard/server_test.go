@@ -0,0 +1,99 @@
+package leaderboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+func testServer() *Server {
+	validator := func(moniker, operator string, uptime float64, upgrade1 int64) src.ValidatorInfo {
+		return src.ValidatorInfo{Info: src.Info{
+			Moniker: moniker, OperatorAddr: operator, UptimePoints: uptime, Upgrade1Points: upgrade1,
+			TotalPoints: uptime + float64(upgrade1),
+		}}
+	}
+
+	return NewServer(&src.Results{StartBlock: 1, EndBlock: 101, Validators: []src.ValidatorInfo{
+		validator("bravo", "xrn:valoper1b", 50, 100),
+		validator("Alpha", "xrn:valoper1a", 100, 0),
+		validator("charlie", "xrn:valoper1c", 10, 200),
+	}})
+}
+
+func TestQuery(t *testing.T) {
+	s := testServer()
+
+	tests := []struct {
+		name     string
+		filter   string
+		sortBy   string
+		order    string
+		monikers []string
+		ranks    []int
+		ok       bool
+	}{
+		{name: "total descending", monikers: []string{"charlie", "bravo", "Alpha"}, ranks: []int{1, 2, 3}, ok: true},
+		{name: "uptime descending", sortBy: "uptime", monikers: []string{"Alpha", "bravo", "charlie"}, ranks: []int{3, 2, 1}, ok: true},
+		{name: "uptime ascending", sortBy: "uptime", order: "asc", monikers: []string{"charlie", "bravo", "Alpha"}, ranks: []int{1, 2, 3}, ok: true},
+		{name: "moniker ascending ignores case", sortBy: "moniker", monikers: []string{"Alpha", "bravo", "charlie"}, ranks: []int{3, 2, 1}, ok: true},
+		{name: "moniker descending", sortBy: "moniker", order: "desc", monikers: []string{"charlie", "bravo", "Alpha"}, ranks: []int{1, 2, 3}, ok: true},
+		{name: "filter on moniker", filter: " ALPHA ", monikers: []string{"Alpha"}, ranks: []int{3}, ok: true},
+		{name: "filter on operator", filter: "valoper1b", monikers: []string{"bravo"}, ranks: []int{2}, ok: true},
+		{name: "no match", filter: "delta", ok: true},
+		{name: "unknown column", sortBy: "rank"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, ok := s.Query(tt.filter, tt.sortBy, tt.order)
+
+			if ok != tt.ok {
+				t.Fatalf("Query ok = %v, want %v", ok, tt.ok)
+			}
+
+			var monikers []string
+			var ranks []int
+
+			for _, entry := range entries {
+				monikers = append(monikers, entry.Info.Moniker)
+				ranks = append(ranks, entry.Rank)
+			}
+
+			if !reflect.DeepEqual(monikers, tt.monikers) || !reflect.DeepEqual(ranks, tt.ranks) {
+				t.Errorf("Query = %v ranked %v, want %v ranked %v", monikers, ranks, tt.monikers, tt.ranks)
+			}
+		})
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	s := testServer()
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/", http.StatusOK},
+		{http.MethodGet, "/?sort=rank", http.StatusBadRequest},
+		{http.MethodGet, "/api/validators?q=alpha", http.StatusOK},
+		{http.MethodGet, "/api/validators/xrn:valoper1a", http.StatusOK},
+		{http.MethodGet, "/api/validators/xrn:valoper1z", http.StatusNotFound},
+		{http.MethodGet, "/validators/xrn:valoper1a", http.StatusOK},
+		{http.MethodGet, "/missing", http.StatusNotFound},
+		{http.MethodPost, "/api/validators", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if rec.Code != tt.status {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.status)
+		}
+	}
+}
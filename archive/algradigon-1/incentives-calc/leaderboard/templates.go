@@ -0,0 +1,68 @@
+package leaderboard
+
+import "html/template"
+
+const style = `<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 4px 10px; border-bottom: 1px solid #ddd; text-align: left; }
+td.points { text-align: right; }
+</style>`
+
+var leaderboardTemplate = template.Must(template.New("leaderboard").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Leaderboard</title>` + style + `</head>
+<body>
+<h1>Leaderboard</h1>
+<p>Blocks {{.Results.StartBlock}} - {{.Results.EndBlock}}</p>
+<form method="get" action="/">
+<input type="text" name="q" value="{{.Filter}}" placeholder="Moniker or operator address">
+<input type="hidden" name="sort" value="{{.Sort}}">
+<button type="submit">Filter</button>
+</form>
+<table>
+<tr>
+<th>Rank</th>
+<th><a href="?q={{.Filter}}&sort=moniker">Moniker</a></th>
+<th><a href="?q={{.Filter}}&sort=operator">Operator Address</a></th>
+<th><a href="?q={{.Filter}}&sort=uptime">Uptime</a></th>
+<th><a href="?q={{.Filter}}&sort=upgrade1">Upgrade-1</a></th>
+<th><a href="?q={{.Filter}}&sort=upgrade2">Upgrade-2</a></th>
+<th><a href="?q={{.Filter}}&sort=proposals">Proposals</a></th>
+<th><a href="?q={{.Filter}}&sort=genesis">Genesis</a></th>
+<th><a href="?q={{.Filter}}&sort=adjustments">Adjustments</a></th>
+<th><a href="?q={{.Filter}}&sort=total">Total</a></th>
+</tr>
+{{range .Entries}}<tr>
+<td>{{.Rank}}</td>
+<td><a href="/validators/{{.Info.OperatorAddr}}">{{.Info.Moniker}}</a></td>
+<td>{{.Info.OperatorAddr}}</td>
+<td class="points">{{printf "%.2f" .Info.UptimePoints}}</td>
+<td class="points">{{.Info.Upgrade1Points}}</td>
+<td class="points">{{.Info.Upgrade2Points}}</td>
+<td class="points">{{.Info.Proposal1VoteScore}} + {{.Info.Proposal2VoteScore}}</td>
+<td class="points">{{.Info.GenesisPoints}}</td>
+<td class="points">{{.Info.AdjustmentPoints}}</td>
+<td class="points">{{printf "%.2f" .Info.TotalPoints}}</td>
+</tr>
+{{else}}<tr><td colspan="10">No validators found</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+var validatorTemplate = template.Must(template.New("validator").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Info.Moniker}}</title>` + style + `</head>
+<body>
+<p><a href="/">Leaderboard</a></p>
+<h1>{{.Info.Moniker}}</h1>
+<p>Rank {{.Rank}}<br>Operator address {{.Info.OperatorAddr}}<br>Validator address {{.ValAddress}}</p>
+<table>
+<tr><th>Category</th><th>Points</th><th>How</th></tr>
+{{range .Breakdown}}<tr><td>{{.Category}}</td><td class="points">{{printf "%.2f" .Points}}</td><td>{{.Detail}}</td></tr>
+{{end}}<tr><th>Total</th><th class="points">{{printf "%.2f" .Info.TotalPoints}}</th><th></th></tr>
+</table>
+</body>
+</html>
+`))
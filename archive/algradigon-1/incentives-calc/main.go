@@ -4,12 +4,40 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/regen-friends/testnets/util/uptime/db"
 	"github.com/regen-friends/testnets/util/uptime/src"
 )
 
+// Sub commands, the uptime calculation runs when no sub command is given
+var commands = map[string]func(args []string){
+	"genesis":      runGenesis,
+	"challenges":   runChallenges,
+	"upgrades":     runUpgrades,
+	"serve":        runServe,
+	"live":         runLive,
+	"bundle":       runBundle,
+	"adjustments":  runAdjustments,
+	"program":      runProgram,
+	"identity":     runIdentity,
+	"power":        runPower,
+	"headers":      runHeaders,
+	"proposers":    runProposers,
+	"anomalies":    runAnomalies,
+	"txs":          runTxs,
+	"evidence":     runEvidence,
+	"contracts":    runContracts,
+	"distribution": runDistribution,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			cmd(os.Args[2:])
+			return
+		}
+	}
 
 	fmt.Println("Starting...")
 
@@ -29,10 +57,10 @@ func main() {
 	}
 
 	//Read database configuration from config.toml
-	uri := db.ReadDBConfig()
+	cfg := db.ReadConfig()
 
 	//Connect Mongo database
-	session, err := db.Connect(uri)
+	session, err := db.Connect(db.DialInfo(cfg))
 
 	if err != nil {
 		log.Fatalf("ERR_DB_CONN: %s", err)
@@ -43,7 +71,7 @@ func main() {
 	//Close the session safely after the operations are done
 	defer session.Terminate()
 
-	handler := src.New(session)
+	handler := src.New(session, cfg)
 
 	handler.CalculateUptime(int64(startBlock), int64(endBlock))
 }
@@ -0,0 +1,99 @@
+package program
+
+import (
+	"sort"
+
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+// PhaseOperator is the operator address an identity used in a phase
+type PhaseOperator struct {
+	Phase    string `json:"phase"`
+	ChainID  string `json:"chainId"`
+	Operator string `json:"operator"`
+	Moniker  string `json:"moniker"`
+}
+
+// Standing is the cumulative score of an identity, subtotals are in the order of the
+// program phases and 0 for phases the identity didn't take part in
+type Standing struct {
+	Rank      int             `json:"rank"`
+	Identity  string          `json:"identity"`
+	Moniker   string          `json:"moniker"`
+	Operators []PhaseOperator `json:"operators"`
+	Subtotals []float64       `json:"subtotals"`
+	Total     float64         `json:"total"`
+}
+
+// Standings are the merged results of all the phases of a program
+type Standings struct {
+	Program   string     `json:"program"`
+	Phases    []Phase    `json:"phases"`
+	Standings []Standing `json:"standings"`
+}
+
+// Run - Runs every phase of the program and merges the results
+func Run(p *Program) (*Standings, error) {
+	var results []*src.Results
+
+	for _, phase := range p.Phases {
+		phaseResults, err := RunPhase(phase)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, phaseResults)
+	}
+
+	return Merge(p, results), nil
+}
+
+// Merge - Merges the results of the phases by operator identity, the moniker
+// of the latest phase an identity took part in with a moniker is used
+func Merge(p *Program, results []*src.Results) *Standings {
+	byIdentity := make(map[string]*Standing)
+
+	var order []string
+
+	for i, phaseResults := range results {
+		phase := p.Phases[i]
+
+		for _, v := range phaseResults.Validators {
+			identity := p.Identity(phase.ChainID, v.Info.OperatorAddr)
+
+			standing, ok := byIdentity[identity]
+			if !ok {
+				standing = &Standing{Identity: identity, Subtotals: make([]float64, len(p.Phases))}
+				byIdentity[identity] = standing
+				order = append(order, identity)
+			}
+
+			//Challenge results have no moniker
+			if v.Info.Moniker != "" {
+				standing.Moniker = v.Info.Moniker
+			}
+
+			standing.Operators = append(standing.Operators, PhaseOperator{
+				Phase: phase.Name, ChainID: phase.ChainID, Operator: v.Info.OperatorAddr, Moniker: v.Info.Moniker,
+			})
+			standing.Subtotals[i] += v.Info.TotalPoints
+			standing.Total += v.Info.TotalPoints
+		}
+	}
+
+	standings := &Standings{Program: p.Name, Phases: p.Phases}
+
+	for _, identity := range order {
+		standings.Standings = append(standings.Standings, *byIdentity[identity])
+	}
+
+	sort.SliceStable(standings.Standings, func(i, j int) bool {
+		return standings.Standings[i].Total > standings.Standings[j].Total
+	})
+
+	for i := range standings.Standings {
+		standings.Standings[i].Rank = i + 1
+	}
+
+	return standings
+}
@@ -0,0 +1,228 @@
+package program
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/regen-friends/testnets/util/uptime/bundle"
+	"github.com/regen-friends/testnets/util/uptime/challenges"
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/identity"
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+// Phase is a scored window of the program. Its results are calculated from the config file
+// (database and rules of the phase) over the block range, or read from a result.json, a bundle
+// or the JSON output of challenges verify
+type Phase struct {
+	Name       string `json:"name"`
+	ChainID    string `json:"chainId"`
+	Config     string `json:"config,omitempty"`
+	StartBlock int64  `json:"startBlock,omitempty"`
+	EndBlock   int64  `json:"endBlock,omitempty"`
+	Results    string `json:"results,omitempty"`
+	Bundle     string `json:"bundle,omitempty"`
+	Challenges string `json:"challenges,omitempty"`
+}
+
+// Program lists the phases of a testnet program, which can span several chains. Operators
+// which changed address between chains are merged through the confirmed participants of the
+// identity registry, Identities maps operator addresses to an identity and takes precedence
+type Program struct {
+	Name             string            `json:"name"`
+	Phases           []Phase           `json:"phases"`
+	IdentityRegistry string            `json:"identityRegistry,omitempty"`
+	Identities       map[string]string `json:"identities,omitempty"`
+
+	registry *identity.Registry
+}
+
+// Load - Reads and validates a program file, the paths of the phases are relative to it
+func Load(path string) (*Program, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	var p Program
+
+	if err := decoder.Decode(&p); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if errs := p.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %s", path, strings.Join(errs, "; "))
+	}
+
+	dir := filepath.Dir(path)
+
+	for i := range p.Phases {
+		phase := &p.Phases[i]
+		phase.Config = resolvePath(dir, phase.Config)
+		phase.Results = resolvePath(dir, phase.Results)
+		phase.Bundle = resolvePath(dir, phase.Bundle)
+		phase.Challenges = resolvePath(dir, phase.Challenges)
+	}
+
+	if p.IdentityRegistry != "" {
+		p.IdentityRegistry = resolvePath(dir, p.IdentityRegistry)
+
+		if p.registry, err = identity.LoadRegistry(p.IdentityRegistry); err != nil {
+			return nil, err
+		}
+	}
+
+	return &p, nil
+}
+
+// Validate - Returns every problem found in the program
+func (p *Program) Validate() []string {
+	var errs []string
+
+	if len(p.Phases) == 0 {
+		errs = append(errs, "program has no phases")
+	}
+
+	names := make(map[string]bool)
+
+	for i, phase := range p.Phases {
+		label := phase.Name
+		if label == "" {
+			label = fmt.Sprintf("phase %d", i)
+			errs = append(errs, label+": name is missing")
+		}
+
+		if names[phase.Name] {
+			errs = append(errs, label+": duplicate name")
+		}
+
+		names[phase.Name] = true
+
+		if phase.ChainID == "" {
+			errs = append(errs, label+": chainId is missing")
+		}
+
+		sources := 0
+
+		for _, source := range []string{phase.Config, phase.Results, phase.Bundle, phase.Challenges} {
+			if source != "" {
+				sources++
+			}
+		}
+
+		if sources != 1 {
+			errs = append(errs, label+": exactly one of config, results, bundle or challenges is required")
+		}
+
+		if phase.Config != "" && (phase.StartBlock < 0 || phase.EndBlock <= phase.StartBlock) {
+			errs = append(errs, fmt.Sprintf("%s: invalid block range %d - %d", label, phase.StartBlock, phase.EndBlock))
+		}
+	}
+
+	return errs
+}
+
+// Identity - Returns the identity of an operator of a chain, the operator address when it has none
+func (p *Program) Identity(chainID, operator string) string {
+	if id, ok := p.Identities[operator]; ok {
+		return id
+	}
+
+	if p.registry != nil {
+		if id, ok := p.registry.Resolve(chainID, operator); ok {
+			return id
+		}
+	}
+
+	return operator
+}
+
+// RunPhase - Calculates or reads the results of a phase
+func RunPhase(phase Phase) (*src.Results, error) {
+	switch {
+	case phase.Results != "":
+		return src.LoadResults(phase.Results)
+	case phase.Bundle != "":
+		b, err := bundle.Load(phase.Bundle)
+		if err != nil {
+			return nil, err
+		}
+
+		return b.Results, nil
+	case phase.Challenges != "":
+		return LoadChallengeResults(phase.Challenges)
+	}
+
+	//Every phase has its own database and rules
+	cfg, err := config.ReadFile(phase.Config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid config: %v", phase.Name, err)
+	}
+
+	session, err := db.Connect(db.DialInfo(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("ERR_DB_CONN: %s", err)
+	}
+
+	defer session.Terminate()
+
+	return src.New(session, cfg).ScoreValidators(phase.StartBlock, phase.EndBlock), nil
+}
+
+// LoadChallengeResults - Reads the JSON output of challenges verify as the results of a phase, an
+// operator scores the points of its best submission
+func LoadChallengeResults(path string) (*src.Results, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifications []challenges.Verification
+
+	if err := json.Unmarshal(bz, &verifications); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	results := &src.Results{}
+	best := make(map[string]int)
+
+	for _, v := range verifications {
+		if v.Operator == "" || v.Total <= 0 {
+			continue
+		}
+
+		i, ok := best[v.Operator]
+		if !ok {
+			best[v.Operator] = len(results.Validators)
+			results.Validators = append(results.Validators, src.ValidatorInfo{
+				Info: src.Info{OperatorAddr: v.Operator, TotalPoints: float64(v.Total)},
+			})
+
+			continue
+		}
+
+		if points := float64(v.Total); points > results.Validators[i].Info.TotalPoints {
+			results.Validators[i].Info.TotalPoints = points
+		}
+	}
+
+	return results, nil
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,93 @@
+package program
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/src"
+)
+
+func TestLoadChallengeResults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "program")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	//The output of challenges verify --json, val1 submitted twice
+	path := filepath.Join(dir, "phase-2.json")
+	verifications := `[
+		{"file": "val1.json", "operator": "xrn:valoper1", "total": 150},
+		{"file": "val1-bis.json", "operator": "xrn:valoper1", "total": 300},
+		{"file": "val2.json", "operator": "xrn:valoper2", "total": 100},
+		{"file": "val3.json", "operator": "xrn:valoper3", "total": 0},
+		{"file": "broken.json", "errors": ["unexpected end of JSON input"]}
+	]`
+
+	if err := ioutil.WriteFile(path, []byte(verifications), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := RunPhase(Phase{Name: "phase-2", ChainID: "test-1", Challenges: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]float64)
+	for _, v := range results.Validators {
+		got[v.Info.OperatorAddr] = v.Info.TotalPoints
+	}
+
+	want := map[string]float64{"xrn:valoper1": 300, "xrn:valoper2": 100}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("points = %v, want %v", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	p := &Program{
+		Name: "test",
+		Phases: []Phase{
+			{Name: "uptime", ChainID: "test-1", Results: "result.json"},
+			{Name: "phase-2", ChainID: "test-1", Challenges: "phase-2.json"},
+			{Name: "uptime-2", ChainID: "test-2", Results: "result.json"},
+		},
+		Identities: map[string]string{"xrn:valoper1new": "xrn:valoper1"},
+	}
+
+	validator := func(operator, moniker string, points float64) src.ValidatorInfo {
+		return src.ValidatorInfo{Info: src.Info{OperatorAddr: operator, Moniker: moniker, TotalPoints: points}}
+	}
+
+	standings := Merge(p, []*src.Results{
+		{Validators: []src.ValidatorInfo{validator("xrn:valoper1", "one", 100), validator("xrn:valoper2", "two", 250)}},
+		{Validators: []src.ValidatorInfo{validator("xrn:valoper1", "", 300)}},
+		{Validators: []src.ValidatorInfo{validator("xrn:valoper1new", "one-new", 50)}},
+	})
+
+	type standing struct {
+		Rank      int
+		Identity  string
+		Moniker   string
+		Subtotals []float64
+	}
+
+	var got []standing
+	for _, s := range standings.Standings {
+		got = append(got, standing{s.Rank, s.Identity, s.Moniker, s.Subtotals})
+	}
+
+	//The challenge phase has no moniker, the one of the latest phase with a moniker is kept
+	want := []standing{
+		{1, "xrn:valoper1", "one-new", []float64{100, 300, 50}},
+		{2, "xrn:valoper2", "two", []float64{250, 0, 0}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("standings = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,289 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// validators requested per page, the maximum allowed by tendermint
+const validatorsPerPage = 100
+
+// block metas returned by a blockchain call, the maximum allowed by tendermint
+const blockchainPageSize = 20
+
+// Client queries the tendermint RPC of a node, e.g. http://localhost:26657
+type Client struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// NewClient - Creates a client for the RPC at the given url
+func NewClient(url string) *Client {
+	return &Client{URL: strings.TrimRight(url, "/"), HTTP: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Int64 is an integer encoded as a string by amino JSON, plain numbers are accepted as well
+type Int64 int64
+
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s", data)
+	}
+
+	*i = Int64(value)
+
+	return nil
+}
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+type validatorsResult struct {
+	BlockHeight Int64 `json:"block_height"`
+	Validators  []struct {
+		Address     string `json:"address"`
+		VotingPower Int64  `json:"voting_power"`
+	} `json:"validators"`
+	Total Int64 `json:"total"`
+}
+
+// Validators - Fetch the validator set at a height with the voting power of every validator
+func (c *Client) Validators(height int64) ([]db.ValidatorPower, error) {
+	var validators []db.ValidatorPower
+
+	for page := 1; ; page++ {
+		var result validatorsResult
+
+		params := url.Values{}
+		params.Set("height", strconv.FormatInt(height, 10))
+		params.Set("page", strconv.Itoa(page))
+		params.Set("per_page", strconv.Itoa(validatorsPerPage))
+
+		if err := c.Call("validators", params, &result); err != nil {
+			return nil, err
+		}
+
+		for _, v := range result.Validators {
+			validators = append(validators, db.ValidatorPower{
+				Address: strings.ToUpper(v.Address), VotingPower: int64(v.VotingPower),
+			})
+		}
+
+		//Nodes without pagination return the whole set and no total
+		if len(result.Validators) < validatorsPerPage || int64(len(validators)) >= int64(result.Total) {
+			return validators, nil
+		}
+	}
+}
+
+type blockMeta struct {
+	Header struct {
+		Height          Int64     `json:"height"`
+		Time            time.Time `json:"time"`
+		ProposerAddress string    `json:"proposer_address"`
+		EvidenceHash    string    `json:"evidence_hash"`
+	} `json:"header"`
+}
+
+type blockchainResult struct {
+	BlockMetas []blockMeta `json:"block_metas"`
+}
+
+// BlockHeaders - Fetch the headers of the heights in between min and max height ordered by height
+func (c *Client) BlockHeaders(minHeight, maxHeight int64) ([]db.BlockHeader, error) {
+	var headers []db.BlockHeader
+
+	err := c.blockMetas(minHeight, maxHeight, func(meta blockMeta) {
+		headers = append(headers, db.BlockHeader{
+			Height: int64(meta.Header.Height), Time: meta.Header.Time, Proposer: strings.ToUpper(meta.Header.ProposerAddress),
+		})
+	})
+
+	return headers, err
+}
+
+// EvidenceHeights - Fetch the heights in between min and max height whose block includes evidence
+func (c *Client) EvidenceHeights(minHeight, maxHeight int64) ([]int64, error) {
+	var heights []int64
+
+	err := c.blockMetas(minHeight, maxHeight, func(meta blockMeta) {
+		if meta.Header.EvidenceHash != "" {
+			heights = append(heights, int64(meta.Header.Height))
+		}
+	})
+
+	return heights, err
+}
+
+// blockMetas - Calls blockchain for the heights in between min and max height, page by page,
+// and passes the block metas to fn ordered by height
+func (c *Client) blockMetas(minHeight, maxHeight int64, fn func(meta blockMeta)) error {
+	for from := minHeight; from <= maxHeight; from += blockchainPageSize {
+		to := from + blockchainPageSize - 1
+		if to > maxHeight {
+			to = maxHeight
+		}
+
+		var result blockchainResult
+
+		params := url.Values{}
+		params.Set("minHeight", strconv.FormatInt(from, 10))
+		params.Set("maxHeight", strconv.FormatInt(to, 10))
+
+		if err := c.Call("blockchain", params, &result); err != nil {
+			return err
+		}
+
+		//Block metas are returned from the highest height down
+		for i := len(result.BlockMetas) - 1; i >= 0; i-- {
+			fn(result.BlockMetas[i])
+		}
+	}
+
+	return nil
+}
+
+type vote struct {
+	Height           Int64  `json:"height"`
+	Round            Int64  `json:"round"`
+	ValidatorAddress string `json:"validator_address"`
+}
+
+type blockResult struct {
+	Block struct {
+		Header struct {
+			Time time.Time `json:"time"`
+		} `json:"header"`
+		Evidence struct {
+			Evidence []struct {
+				Type  string `json:"type"`
+				Value struct {
+					PubKey struct {
+						Value []byte `json:"value"`
+					} `json:"PubKey"`
+					VoteA vote `json:"VoteA"`
+				} `json:"value"`
+			} `json:"evidence"`
+		} `json:"evidence"`
+	} `json:"block"`
+}
+
+// Evidence - Fetch the evidence included in the block at height. The offender is the validator
+// address of the first vote, or the address of the evidence pubkey when the vote has none
+func (c *Client) Evidence(height int64) ([]db.Evidence, error) {
+	var result blockResult
+
+	params := url.Values{}
+	params.Set("height", strconv.FormatInt(height, 10))
+
+	if err := c.Call("block", params, &result); err != nil {
+		return nil, err
+	}
+
+	var evidence []db.Evidence
+
+	for _, e := range result.Block.Evidence.Evidence {
+		offender := strings.ToUpper(e.Value.VoteA.ValidatorAddress)
+
+		if offender == "" && len(e.Value.PubKey.Value) > 0 {
+			hexAddr, err := address.Ed25519PubKeyToHexAddress(e.Value.PubKey.Value)
+			if err != nil {
+				return nil, fmt.Errorf("evidence at height %d: %v", height, err)
+			}
+
+			offender = hexAddr
+		}
+
+		evidence = append(evidence, db.Evidence{
+			ID:         fmt.Sprintf("%d-%s-%d-%d", height, offender, e.Value.VoteA.Height, e.Value.VoteA.Round),
+			Height:     height,
+			Time:       result.Block.Header.Time,
+			Type:       e.Type,
+			Address:    offender,
+			VoteHeight: int64(e.Value.VoteA.Height),
+			VoteRound:  int64(e.Value.VoteA.Round),
+		})
+	}
+
+	return evidence, nil
+}
+
+type commitResult struct {
+	SignedHeader struct {
+		Commit struct {
+			Round      *Int64 `json:"round"`
+			Precommits []*struct {
+				Round Int64 `json:"round"`
+			} `json:"precommits"`
+		} `json:"commit"`
+	} `json:"signed_header"`
+}
+
+// CommitRound - Fetch the round the block at height was committed in. Tendermint 0.33 has the
+// round on the commit, older versions only on the precommits
+func (c *Client) CommitRound(height int64) (int64, error) {
+	var result commitResult
+
+	params := url.Values{}
+	params.Set("height", strconv.FormatInt(height, 10))
+
+	if err := c.Call("commit", params, &result); err != nil {
+		return 0, err
+	}
+
+	commit := result.SignedHeader.Commit
+
+	if commit.Round != nil {
+		return int64(*commit.Round), nil
+	}
+
+	for _, precommit := range commit.Precommits {
+		if precommit != nil {
+			return int64(precommit.Round), nil
+		}
+	}
+
+	return 0, fmt.Errorf("commit of height %d has no round", height)
+}
+
+// Call - Calls an RPC endpoint with the URI params and decodes its result
+func (c *Client) Call(method string, params url.Values, result interface{}) error {
+	endpoint := c.URL + "/" + method
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	resp, err := c.HTTP.Get(endpoint)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	var r response
+
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("%s: %s: %v", method, resp.Status, err)
+	}
+
+	if r.Error != nil {
+		return fmt.Errorf("%s: %s %s", method, r.Error.Message, r.Error.Data)
+	}
+
+	return json.Unmarshal(r.Result, result)
+}
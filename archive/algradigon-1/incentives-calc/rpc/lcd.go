@@ -0,0 +1,132 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// txs returned per page of a tx search, the maximum allowed by the REST server
+const txsPerPage = 100
+
+// LCD queries the REST server of the light client daemon, e.g. http://localhost:1317, which
+// returns the decoded txs with gas and fee
+type LCD struct {
+	URL  string
+	HTTP *http.Client
+}
+
+// NewLCD - Creates a client for the REST server at the given url
+func NewLCD(url string) *LCD {
+	return &LCD{URL: strings.TrimRight(url, "/"), HTTP: &http.Client{Timeout: 30 * time.Second}}
+}
+
+type searchTxsResult struct {
+	TotalCount Int64        `json:"total_count"`
+	Txs        []txResponse `json:"txs"`
+}
+
+type txResponse struct {
+	Height    Int64      `json:"height"`
+	TxHash    string     `json:"txhash"`
+	Code      uint32     `json:"code"`
+	Logs      []db.TxLog `json:"logs"`
+	GasWanted Int64      `json:"gas_wanted"`
+	GasUsed   Int64      `json:"gas_used"`
+	Timestamp time.Time  `json:"timestamp"`
+	Tx        struct {
+		Value struct {
+			Msg []struct {
+				Type  string          `json:"type"`
+				Value json.RawMessage `json:"value"`
+			} `json:"msg"`
+			Fee struct {
+				Amount []db.Coin `json:"amount"`
+			} `json:"fee"`
+		} `json:"value"`
+	} `json:"tx"`
+}
+
+// Txs - Fetch the txs included in the block at height
+func (l *LCD) Txs(height int64) ([]db.Transaction, error) {
+	var txs []db.Transaction
+
+	for page := 1; ; page++ {
+		var result searchTxsResult
+
+		params := url.Values{}
+		params.Set("tx.height", strconv.FormatInt(height, 10))
+		params.Set("page", strconv.Itoa(page))
+		params.Set("limit", strconv.Itoa(txsPerPage))
+
+		if err := l.Get("txs", params, &result); err != nil {
+			return nil, err
+		}
+
+		for _, r := range result.Txs {
+			txs = append(txs, r.transaction())
+		}
+
+		if len(result.Txs) < txsPerPage || int64(len(txs)) >= int64(result.TotalCount) {
+			return txs, nil
+		}
+	}
+}
+
+// transaction - Converts a tx response to the stored transaction, the signer of a message is
+// taken from its sender field. Messages whose fields don't fit MsgValue keep their type only
+func (r txResponse) transaction() db.Transaction {
+	tx := db.Transaction{
+		Hash: strings.ToUpper(r.TxHash), Height: int64(r.Height), Time: r.Timestamp, Code: r.Code, Logs: r.Logs,
+		GasWanted: int64(r.GasWanted), GasUsed: int64(r.GasUsed), Fee: r.Tx.Value.Fee.Amount,
+	}
+
+	for _, m := range r.Tx.Value.Msg {
+		msg := db.Msg{Type: m.Type}
+		_ = json.Unmarshal(m.Value, &msg.Value)
+
+		tx.Msgs = append(tx.Msgs, msg)
+
+		for _, signer := range []string{msg.Value.FromAddress, msg.Value.Sender, msg.Value.DelegatorAddress} {
+			if signer != "" {
+				tx.Signers = append(tx.Signers, signer)
+				break
+			}
+		}
+	}
+
+	return tx
+}
+
+// Get - Calls a REST endpoint with the query params and decodes its response
+func (l *LCD) Get(path string, params url.Values, result interface{}) error {
+	endpoint := l.URL + "/" + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	resp, err := l.HTTP.Get(endpoint)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+
+		return fmt.Errorf("%s: %s %s", path, resp.Status, body.Error)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
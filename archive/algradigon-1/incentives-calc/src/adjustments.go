@@ -0,0 +1,139 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/config"
+)
+
+// Adjustment is a manual bonus or penalty of a validator, such as bug report,
+// community support, tweet or proven attack points
+type Adjustment struct {
+	Operator string  `json:"operator"`
+	Category string  `json:"category"`
+	Points   float64 `json:"points"`
+	Reason   string  `json:"reason"`
+	Evidence string  `json:"evidence,omitempty"`
+	Approver string  `json:"approver"`
+}
+
+// LoadAdjustments - Reads and validates an adjustments ledger, a JSON array of adjustments.
+// The ledger is rejected as a whole when any entry is invalid
+func LoadAdjustments(path string) ([]Adjustment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	var ledger []Adjustment
+
+	if err := decoder.Decode(&ledger); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	var errs []string
+
+	for i, adj := range ledger {
+		for _, msg := range adj.Validate() {
+			errs = append(errs, fmt.Sprintf("entry %d: %s", i, msg))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %s", path, strings.Join(errs, "; "))
+	}
+
+	return ledger, nil
+}
+
+// Validate - Returns the problems of an adjustment, every adjustment needs a reason and an approver
+func (a Adjustment) Validate() []string {
+	var errs []string
+
+	if err := address.ValidateBech32(a.Operator, address.ValOperPrefix); err != nil {
+		errs = append(errs, fmt.Sprintf("operator: %v", err))
+	}
+
+	if strings.TrimSpace(a.Category) == "" {
+		errs = append(errs, "category is missing")
+	}
+
+	if a.Points == 0 {
+		errs = append(errs, "points must not be 0")
+	}
+
+	if strings.TrimSpace(a.Reason) == "" {
+		errs = append(errs, "reason is missing")
+	}
+
+	if strings.TrimSpace(a.Approver) == "" {
+		errs = append(errs, "approver is missing")
+	}
+
+	if a.Evidence != "" {
+		u, err := url.Parse(a.Evidence)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Sprintf("evidence %q is not an http(s) url", a.Evidence))
+		}
+	}
+
+	return errs
+}
+
+// String - Category, points and reason of the adjustment with its approver
+func (a Adjustment) String() string {
+	s := fmt.Sprintf("%s %+g: %s (approved by %s", a.Category, a.Points, a.Reason, a.Approver)
+
+	if a.Evidence != "" {
+		s += ", " + a.Evidence
+	}
+
+	return s + ")"
+}
+
+// ApplyAdjustments - Adds the adjustments to the totals of the validators, adjustments of
+// operators without results are returned
+func ApplyAdjustments(validators []ValidatorInfo, ledger []Adjustment) []Adjustment {
+	index := make(map[string]int, len(validators))
+
+	for i, v := range validators {
+		index[v.Info.OperatorAddr] = i
+	}
+
+	var unapplied []Adjustment
+
+	for _, adj := range ledger {
+		i, ok := index[adj.Operator]
+		if !ok {
+			unapplied = append(unapplied, adj)
+			continue
+		}
+
+		info := &validators[i].Info
+		info.Adjustments = append(info.Adjustments, adj)
+		info.AdjustmentPoints += adj.Points
+		info.TotalPoints += adj.Points
+	}
+
+	return unapplied
+}
+
+// loadConfiguredAdjustments - Reads the ledger of the adjustments_file config, none when it is not set
+func loadConfiguredAdjustments(cfg *config.Config) ([]Adjustment, error) {
+	ledgerFile := cfg.AdjustmentsFile
+	if ledgerFile == "" {
+		return nil, nil
+	}
+
+	return LoadAdjustments(ledgerFile)
+}
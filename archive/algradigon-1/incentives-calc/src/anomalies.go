@@ -0,0 +1,286 @@
+package src
+
+import (
+	"sort"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// kinds of height anomalies
+const (
+	ANOMALY_SPIKE       = "spike"
+	ANOMALY_STALL       = "stall"
+	ANOMALY_MULTI_ROUND = "multi-round"
+)
+
+// AnomalyOptions tune the detection. A height is a spike when its block time is at least SpikeFactor
+// times the median block time of the range, and a stall from Stall on. Baseline is the number of
+// blocks before a window whose signers are expected to keep signing in it
+type AnomalyOptions struct {
+	SpikeFactor float64
+	Stall       time.Duration
+	Baseline    int64
+}
+
+// HeightAnomaly is a height which took long to commit or needed more than one round, the
+// interval is the time since the previous block
+type HeightAnomaly struct {
+	Height          int64     `json:"height"`
+	Time            time.Time `json:"time"`
+	IntervalSeconds float64   `json:"intervalSeconds"`
+	Round           int64     `json:"round"`
+	Kinds           []string  `json:"kinds"`
+}
+
+// DroppedValidator is a validator which signed the baseline before an anomaly window and
+// missed commits of the window
+type DroppedValidator struct {
+	Address      string `json:"address"`
+	OperatorAddr string `json:"operatorAddr"`
+	Moniker      string `json:"moniker"`
+	Missed       int64  `json:"missed"`
+	Commits      int64  `json:"commits"`
+}
+
+// AnomalyWindow groups consecutive anomalous heights
+type AnomalyWindow struct {
+	StartHeight        int64              `json:"startHeight"`
+	EndHeight          int64              `json:"endHeight"`
+	StartTime          time.Time          `json:"startTime"`
+	EndTime            time.Time          `json:"endTime"`
+	Kinds              []string           `json:"kinds"`
+	MaxIntervalSeconds float64            `json:"maxIntervalSeconds"`
+	MaxRound           int64              `json:"maxRound"`
+	Heights            []HeightAnomaly    `json:"heights"`
+	Dropped            []DroppedValidator `json:"dropped"`
+}
+
+// AnomalyReport holds the anomaly windows of a range, heights without an ingested header are
+// skipped and counted
+type AnomalyReport struct {
+	StartBlock            int64           `json:"startBlock"`
+	EndBlock              int64           `json:"endBlock"`
+	Heights               int64           `json:"heights"`
+	MissingHeights        int64           `json:"missingHeights"`
+	MedianIntervalSeconds float64         `json:"medianIntervalSeconds"`
+	Windows               []AnomalyWindow `json:"windows"`
+}
+
+// DetectAnomalies - Finds the block time spikes, stalls and multi-round heights in between start and
+// end block from the ingested headers, and the validators which dropped out during each window
+func (h handler) DetectAnomalies(startBlock, endBlock int64, options AnomalyOptions) (*AnomalyReport, error) {
+	report := &AnomalyReport{StartBlock: startBlock, EndBlock: endBlock}
+
+	//The header before the range gives the block time of its first height
+	var headers []db.BlockHeader
+
+	for from := startBlock - 1; from <= endBlock; from += liveBatchSize {
+		to := from + liveBatchSize - 1
+		if to > endBlock {
+			to = endBlock
+		}
+
+		batch, err := h.db.QueryBlockHeaders(from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, batch...)
+	}
+
+	var intervals []float64
+
+	previous := make(map[int64]db.BlockHeader, len(headers))
+
+	for _, header := range headers {
+		previous[header.Height+1] = header
+
+		if header.Height < startBlock {
+			continue
+		}
+
+		report.Heights++
+
+		if prev, ok := previous[header.Height]; ok {
+			intervals = append(intervals, header.Time.Sub(prev.Time).Seconds())
+		}
+	}
+
+	report.MissingHeights = endBlock - startBlock + 1 - report.Heights
+	report.MedianIntervalSeconds = median(intervals)
+
+	var anomalies []HeightAnomaly
+
+	for _, header := range headers {
+		if header.Height < startBlock {
+			continue
+		}
+
+		anomaly := HeightAnomaly{Height: header.Height, Time: header.Time, Round: header.Round}
+
+		if prev, ok := previous[header.Height]; ok {
+			anomaly.IntervalSeconds = header.Time.Sub(prev.Time).Seconds()
+
+			switch {
+			case options.Stall > 0 && anomaly.IntervalSeconds >= options.Stall.Seconds():
+				anomaly.Kinds = append(anomaly.Kinds, ANOMALY_STALL)
+			case report.MedianIntervalSeconds > 0 && anomaly.IntervalSeconds >= options.SpikeFactor*report.MedianIntervalSeconds:
+				anomaly.Kinds = append(anomaly.Kinds, ANOMALY_SPIKE)
+			}
+		}
+
+		if header.Round > 0 {
+			anomaly.Kinds = append(anomaly.Kinds, ANOMALY_MULTI_ROUND)
+		}
+
+		if len(anomaly.Kinds) > 0 {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+
+	report.Windows = groupAnomalies(anomalies)
+
+	validators, err := h.db.QueryValidators()
+	if err != nil {
+		return nil, err
+	}
+
+	operators := make(map[string]db.Validator, len(validators))
+
+	for _, v := range validators {
+		operators[v.Address] = v
+	}
+
+	for i := range report.Windows {
+		dropped, err := h.droppedValidators(report.Windows[i], options.Baseline)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, d := range dropped {
+			if v, ok := operators[d.Address]; ok {
+				dropped[j].OperatorAddr = v.OperatorAddress
+				dropped[j].Moniker = v.Description.Moniker
+			}
+		}
+
+		report.Windows[i].Dropped = dropped
+	}
+
+	return report, nil
+}
+
+// groupAnomalies - Merges anomalies of consecutive heights into windows
+func groupAnomalies(anomalies []HeightAnomaly) []AnomalyWindow {
+	var windows []AnomalyWindow
+
+	for _, anomaly := range anomalies {
+		n := len(windows)
+
+		if n == 0 || anomaly.Height > windows[n-1].EndHeight+1 {
+			windows = append(windows, AnomalyWindow{StartHeight: anomaly.Height, StartTime: anomaly.Time})
+			n++
+		}
+
+		window := &windows[n-1]
+		window.EndHeight = anomaly.Height
+		window.EndTime = anomaly.Time
+		window.Heights = append(window.Heights, anomaly)
+
+		if anomaly.IntervalSeconds > window.MaxIntervalSeconds {
+			window.MaxIntervalSeconds = anomaly.IntervalSeconds
+		}
+
+		if anomaly.Round > window.MaxRound {
+			window.MaxRound = anomaly.Round
+		}
+
+		for _, kind := range anomaly.Kinds {
+			if !containsString(window.Kinds, kind) {
+				window.Kinds = append(window.Kinds, kind)
+			}
+		}
+	}
+
+	return windows
+}
+
+// droppedValidators - Compares the signers of the baseline commits before the window with the
+// commits of the window. The commit of a height is included in the next block, so the window
+// commits are in blocks start + 1 to end + 1
+func (h handler) droppedValidators(window AnomalyWindow, baseline int64) ([]DroppedValidator, error) {
+	blocks, err := h.db.QueryBlocks(window.StartHeight-baseline+1, window.EndHeight+1)
+	if err != nil {
+		return nil, err
+	}
+
+	baselineCount := make(map[string]int64)
+	windowCount := make(map[string]int64)
+
+	var baselineBlocks, commits int64
+
+	for _, block := range blocks {
+		counts := windowCount
+
+		if block.Height <= window.StartHeight {
+			counts = baselineCount
+			baselineBlocks++
+		} else {
+			commits++
+		}
+
+		for _, address := range block.Validators {
+			counts[address]++
+		}
+	}
+
+	var dropped []DroppedValidator
+
+	for address, signed := range baselineCount {
+		//Regular signers of the baseline only, validators already offline are not dropped by the window
+		if signed*2 <= baselineBlocks {
+			continue
+		}
+
+		if missed := commits - windowCount[address]; missed > 0 {
+			dropped = append(dropped, DroppedValidator{Address: address, Missed: missed, Commits: commits})
+		}
+	}
+
+	sort.Slice(dropped, func(i, j int) bool {
+		if dropped[i].Missed != dropped[j].Missed {
+			return dropped[i].Missed > dropped[j].Missed
+		}
+
+		return dropped[i].Address < dropped[j].Address
+	})
+
+	return dropped, nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[middle]
+}
@@ -0,0 +1,174 @@
+package src
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+var anomalyStart = time.Date(2020, 4, 20, 10, 0, 0, 0, time.UTC)
+
+// anomalyStore - Blocks every 6 seconds, height 10 took 60 seconds and height 15 took 200, height 11
+// was committed in round 2 and the header of height 18 is missing. A, B and C sign every block but
+// C misses blocks 11 and 12 and B block 16, D only signs block 9
+func anomalyStore() *memStore {
+	s := &memStore{validators: []db.Validator{
+		{Address: "C", OperatorAddress: "xrn:valoper1c", Description: db.Description{Moniker: "charlie"}},
+	}}
+
+	offset := time.Duration(0)
+
+	for height := int64(0); height <= 20; height++ {
+		switch height {
+		case 10:
+			offset += 54 * time.Second
+		case 15:
+			offset += 194 * time.Second
+		}
+
+		header := db.BlockHeader{Height: height, Time: anomalyStart.Add(time.Duration(height)*6*time.Second + offset)}
+		if height == 11 {
+			header.Round = 2
+		}
+
+		if height != 18 {
+			s.headers = append(s.headers, header)
+		}
+
+		var signers []string
+		for _, address := range []string{"A", "B", "C"} {
+			if (address == "C" && (height == 11 || height == 12)) || (address == "B" && height == 16) {
+				continue
+			}
+
+			signers = append(signers, address)
+		}
+
+		if height == 9 {
+			signers = append(signers, "D")
+		}
+
+		s.blocks = append(s.blocks, block(height, signers...))
+	}
+
+	return s
+}
+
+type windowSummary struct {
+	Start, End  int64
+	Kinds       []string
+	MaxInterval float64
+	MaxRound    int64
+	Dropped     []DroppedValidator
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	spike := windowSummary{
+		Start: 10, End: 11, Kinds: []string{ANOMALY_SPIKE, ANOMALY_MULTI_ROUND}, MaxInterval: 60, MaxRound: 2,
+		Dropped: []DroppedValidator{{Address: "C", OperatorAddr: "xrn:valoper1c", Moniker: "charlie", Missed: 2, Commits: 2}},
+	}
+
+	stall := windowSummary{
+		Start: 15, End: 15, Kinds: []string{ANOMALY_STALL}, MaxInterval: 200,
+		Dropped: []DroppedValidator{{Address: "B", Missed: 1, Commits: 1}},
+	}
+
+	tests := []struct {
+		name    string
+		options AnomalyOptions
+		want    []windowSummary
+	}{
+		{
+			name:    "spike and stall",
+			options: AnomalyOptions{SpikeFactor: 3, Stall: 2 * time.Minute, Baseline: 3},
+			want:    []windowSummary{spike, stall},
+		},
+		{
+			name:    "stall only counts as a spike without a stall duration",
+			options: AnomalyOptions{SpikeFactor: 3, Baseline: 3},
+			want: []windowSummary{spike, {
+				Start: 15, End: 15, Kinds: []string{ANOMALY_SPIKE}, MaxInterval: 200, Dropped: stall.Dropped,
+			}},
+		},
+		{
+			name:    "large spike factor keeps the multi-round height",
+			options: AnomalyOptions{SpikeFactor: 40, Stall: 5 * time.Minute, Baseline: 3},
+			want: []windowSummary{{
+				Start: 11, End: 11, Kinds: []string{ANOMALY_MULTI_ROUND}, MaxInterval: 6, MaxRound: 2,
+				Dropped: []DroppedValidator{{Address: "C", OperatorAddr: "xrn:valoper1c", Moniker: "charlie", Missed: 1, Commits: 1}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := New(anomalyStore(), &config.Config{}).DetectAnomalies(1, 20, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if report.Heights != 19 || report.MissingHeights != 1 || report.MedianIntervalSeconds != 6 {
+				t.Errorf("heights = %d missing %d median %f, want 19 missing 1 median 6",
+					report.Heights, report.MissingHeights, report.MedianIntervalSeconds)
+			}
+
+			var got []windowSummary
+			for _, w := range report.Windows {
+				got = append(got, windowSummary{w.StartHeight, w.EndHeight, w.Kinds, w.MaxIntervalSeconds, w.MaxRound, w.Dropped})
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("windows =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDroppedValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   AnomalyWindow
+		baseline int64
+		want     []DroppedValidator
+	}{
+		{
+			name:     "regular signers missing commits",
+			window:   AnomalyWindow{StartHeight: 10, EndHeight: 11},
+			baseline: 3,
+			want:     []DroppedValidator{{Address: "C", Missed: 2, Commits: 2}},
+		},
+		{
+			//D signed block 9 of a baseline of 2 blocks, which is not more than half
+			name:     "occasional signers are not dropped",
+			window:   AnomalyWindow{StartHeight: 10, EndHeight: 10},
+			baseline: 2,
+			want:     []DroppedValidator{{Address: "C", Missed: 1, Commits: 1}},
+		},
+		{
+			name:     "regular signer of a short baseline",
+			window:   AnomalyWindow{StartHeight: 9, EndHeight: 9},
+			baseline: 1,
+			want:     []DroppedValidator{{Address: "D", Missed: 1, Commits: 1}},
+		},
+		{
+			name:   "no baseline",
+			window: AnomalyWindow{StartHeight: 10, EndHeight: 11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dropped, err := New(anomalyStore(), &config.Config{}).droppedValidators(tt.window, tt.baseline)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(dropped, tt.want) {
+				t.Errorf("dropped = %+v, want %+v", dropped, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/regen-friends/testnets/util/uptime/upgrades"
+)
+
+// PointsItem is a point category of a validator and how the points were earned
+type PointsItem struct {
+	Category string  `json:"category"`
+	Points   float64 `json:"points"`
+	Detail   string  `json:"detail"`
+}
+
+// Breakdown - Lists the points of every category of a validator with the rule that earned them
+func (r *Results) Breakdown(v ValidatorInfo) []PointsItem {
+	blocks := r.EndBlock - r.StartBlock
+
+	items := []PointsItem{
+		{
+			Category: "Uptime",
+			Points:   v.Info.UptimePoints,
+			Detail: fmt.Sprintf("Signed %d of %d blocks in %d - %d, max %d points",
+				v.Info.UptimeCount, blocks, r.StartBlock, r.EndBlock, r.MaxUptimeRewards),
+		},
+		{
+			Category: "Node",
+			Points:   float64(v.Info.NodePoints),
+			Detail:   "Running a validator node",
+		},
+		upgradeItem("Upgrade-1", v.Info.Upgrade1Points, r.Upgrade1),
+		upgradeItem("Upgrade-2", v.Info.Upgrade2Points, r.Upgrade2),
+		voteItem("Proposal-1", v.Info.Proposal1VoteScore),
+		voteItem("Proposal-2", v.Info.Proposal2VoteScore),
+	}
+
+	genesis := PointsItem{Category: "Genesis", Points: float64(v.Info.GenesisPoints),
+		Detail: "Not a gentx validator signing the first block"}
+
+	if v.Info.GenesisPoints > 0 {
+		genesis.Detail = "Gentx validator signing the first block"
+	}
+
+	items = append(items, genesis)
+
+	if r.ProposerPoints > 0 {
+		items = append(items, PointsItem{Category: "Proposer", Points: v.Info.ProposerPoints,
+			Detail: fmt.Sprintf("Proposed blocks over the number expected from the voting power, max %d points", r.ProposerPoints)})
+	}
+
+	//Bonuses taken for double signing keep their category with the reason
+	for i := range items {
+		if containsString(v.Info.Disqualified, strings.ToLower(strings.Replace(items[i].Category, "-", "", -1))) {
+			items[i].Detail = fmt.Sprintf("Disqualified, %d pieces of double-sign evidence", v.Info.DoubleSigns)
+		}
+	}
+
+	for _, adj := range v.Info.Adjustments {
+		items = append(items, PointsItem{Category: "Adjustment: " + adj.Category, Points: adj.Points, Detail: adj.String()})
+	}
+
+	return items
+}
+
+func upgradeItem(category string, points int64, window upgrades.Window) PointsItem {
+	item := PointsItem{Category: category, Points: float64(points)}
+
+	switch {
+	case window.PointsPerBlock <= 0:
+		item.Detail = "Upgrade not scored"
+	case points == 0:
+		item.Detail = fmt.Sprintf("No blocks signed in the upgrade window %d - %d", window.StartBlock, window.EndBlock)
+	default:
+		item.Detail = fmt.Sprintf("Signed %d blocks of the upgrade window %d - %d at %d points per block",
+			points/window.PointsPerBlock, window.StartBlock, window.EndBlock, window.PointsPerBlock)
+	}
+
+	return item
+}
+
+func voteItem(category string, points int64) PointsItem {
+	item := PointsItem{Category: category, Points: float64(points), Detail: "No vote found"}
+
+	if points > 0 {
+		item.Detail = "Voted on the proposal"
+	}
+
+	return item
+}
@@ -0,0 +1,116 @@
+package src
+
+import (
+	"fmt"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/rpc"
+)
+
+// EvidenceRecord is an ingested piece of evidence attributed to the operator of the offender
+type EvidenceRecord struct {
+	db.Evidence
+	OperatorAddr string `json:"operatorAddr"`
+	Moniker      string `json:"moniker"`
+}
+
+// IngestEvidence - Stores the evidence of the blocks in between from and to, only the blocks whose
+// header has an evidence hash are fetched
+func (h handler) IngestEvidence(client *rpc.Client, fromHeight, toHeight int64) (int, error) {
+	ingested := 0
+
+	for from := fromHeight; from <= toHeight; from += liveBatchSize {
+		to := from + liveBatchSize - 1
+		if to > toHeight {
+			to = toHeight
+		}
+
+		heights, err := client.EvidenceHeights(from, to)
+		if err != nil {
+			return ingested, fmt.Errorf("heights %d - %d: %v", from, to, err)
+		}
+
+		for _, height := range heights {
+			evidence, err := client.Evidence(height)
+			if err != nil {
+				return ingested, fmt.Errorf("height %d: %v", height, err)
+			}
+
+			for i := range evidence {
+				if err := h.db.SaveEvidence(&evidence[i]); err != nil {
+					return ingested, err
+				}
+
+				ingested++
+			}
+		}
+	}
+
+	return ingested, nil
+}
+
+// EvidenceRecords - Lists the evidence included in between start and end block, the offenders are
+// attributed with the validators collection, then with the gentxs and create validator txs
+func (h handler) EvidenceRecords(startBlock, endBlock int64, resolver *Resolver) ([]EvidenceRecord, error) {
+	evidence, err := h.db.QueryEvidence(startBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(evidence) == 0 {
+		return nil, nil
+	}
+
+	validators, err := h.db.QueryValidators()
+	if err != nil {
+		return nil, err
+	}
+
+	operators := make(map[string]db.Validator, len(validators))
+
+	for _, v := range validators {
+		operators[v.Address] = v
+	}
+
+	var records []EvidenceRecord
+
+	for _, e := range evidence {
+		record := EvidenceRecord{Evidence: e}
+
+		if v, ok := operators[e.Address]; ok && v.OperatorAddress != "" {
+			record.OperatorAddr, record.Moniker = v.OperatorAddress, v.Description.Moniker
+		} else if info, ok := resolver.Resolve(e.Address); ok {
+			record.OperatorAddr, record.Moniker = info.OperatorAddr, info.Moniker
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// disqualify - Takes the points of the given bonuses from a validator with double-sign evidence
+func disqualify(info *Info, bonuses []string) {
+	for _, bonus := range bonuses {
+		switch bonus {
+		case "uptime":
+			info.UptimePoints = 0
+		case "node":
+			info.NodePoints = 0
+		case "upgrade1":
+			info.Upgrade1Points = 0
+		case "upgrade2":
+			info.Upgrade2Points = 0
+		case "proposal1":
+			info.Proposal1VoteScore = 0
+		case "proposal2":
+			info.Proposal2VoteScore = 0
+		case "genesis":
+			info.GenesisPoints = 0
+		case "proposer":
+			info.ProposerPoints = 0
+		}
+	}
+
+	info.Disqualified = bonuses
+}
@@ -0,0 +1,102 @@
+package src
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+func TestDisqualify(t *testing.T) {
+	points := Info{
+		UptimePoints: 400, NodePoints: 100, Upgrade1Points: 50, Upgrade2Points: 60, Proposal1VoteScore: 20,
+		Proposal2VoteScore: 30, GenesisPoints: 100, ProposerPoints: 10,
+	}
+
+	tests := []struct {
+		name    string
+		bonuses []string
+		want    Info
+	}{
+		{
+			name:    "upgrade and proposer bonuses",
+			bonuses: []string{"upgrade1", "upgrade2", "proposer"},
+			want: Info{
+				UptimePoints: 400, NodePoints: 100, Proposal1VoteScore: 20, Proposal2VoteScore: 30, GenesisPoints: 100,
+			},
+		},
+		{
+			name:    "every bonus",
+			bonuses: []string{"uptime", "node", "upgrade1", "upgrade2", "proposal1", "proposal2", "genesis", "proposer"},
+			want:    Info{},
+		},
+		{
+			name:    "unknown bonuses are ignored",
+			bonuses: []string{"votes"},
+			want:    points,
+		},
+		{
+			name: "no bonuses",
+			want: points,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := points
+			disqualify(&info, tt.bonuses)
+
+			want := tt.want
+			want.Disqualified = tt.bonuses
+
+			if !reflect.DeepEqual(info, want) {
+				t.Errorf("info = %+v, want %+v", info, want)
+			}
+		})
+	}
+}
+
+func TestEvidenceRecords(t *testing.T) {
+	store := &memStore{
+		evidence: []db.Evidence{
+			{Height: 5, Address: "A", Type: "DuplicateVoteEvidence"},
+			{Height: 8, Address: testHexAddr, Type: "DuplicateVoteEvidence"},
+			{Height: 9, Address: "Z", Type: "DuplicateVoteEvidence"},
+			{Height: 20, Address: "A", Type: "DuplicateVoteEvidence"},
+		},
+		validators: []db.Validator{
+			{Address: "A", OperatorAddress: "xrn:valoper1a", Description: db.Description{Moniker: "alpha"}},
+			{Address: testHexAddr},
+		},
+	}
+
+	//The operator of testHexAddr is only known from its create validator tx
+	resolver := NewResolver()
+	resolver.AddCreateValidatorTxs([]db.Transaction{createValidatorTx("xrn:valoper1tx", "tx", testConsPub)})
+
+	records, err := New(store, &config.Config{}).EvidenceRecords(1, 10, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type attribution struct {
+		Height   int64
+		Operator string
+		Moniker  string
+	}
+
+	var got []attribution
+	for _, r := range records {
+		got = append(got, attribution{r.Height, r.OperatorAddr, r.Moniker})
+	}
+
+	want := []attribution{{5, "xrn:valoper1a", "alpha"}, {8, "xrn:valoper1tx", "tx"}, {9, "", ""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %+v, want %+v", got, want)
+	}
+
+	if records, err := New(store, &config.Config{}).EvidenceRecords(10, 19, resolver); err != nil || records != nil {
+		t.Errorf("records without evidence = %+v %v", records, err)
+	}
+}
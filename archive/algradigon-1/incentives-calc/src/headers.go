@@ -0,0 +1,42 @@
+package src
+
+import (
+	"fmt"
+
+	"github.com/regen-friends/testnets/util/uptime/rpc"
+)
+
+// IngestBlockHeaders - Stores the headers of every height in between from and to with the round
+// each height was committed in
+func (h handler) IngestBlockHeaders(client *rpc.Client, fromHeight, toHeight int64) (int, error) {
+	ingested := 0
+
+	for from := fromHeight; from <= toHeight; from += liveBatchSize {
+		to := from + liveBatchSize - 1
+		if to > toHeight {
+			to = toHeight
+		}
+
+		headers, err := client.BlockHeaders(from, to)
+		if err != nil {
+			return ingested, fmt.Errorf("heights %d - %d: %v", from, to, err)
+		}
+
+		for i := range headers {
+			round, err := client.CommitRound(headers[i].Height)
+			if err != nil {
+				return ingested, fmt.Errorf("height %d: %v", headers[i].Height, err)
+			}
+
+			headers[i].Round = round
+
+			if err := h.db.SaveBlockHeader(&headers[i]); err != nil {
+				return ingested, err
+			}
+
+			ingested++
+		}
+	}
+
+	return ingested, nil
+}
@@ -0,0 +1,222 @@
+package src
+
+import (
+	"fmt"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/upgrades"
+)
+
+// blocks fetched per query while consuming new heights
+const liveBatchSize = 1000
+
+// NewScoreState - Creates the state of a scoring run starting at the given height,
+// the upgrade windows are read once from the config and kept with the state
+func NewScoreState(cfg *config.Config, run string, startHeight int64) *db.ScoreState {
+	upgrade1, upgrade2 := LoadUpgradeWindows(cfg)
+
+	return &db.ScoreState{
+		Run:         run,
+		StartHeight: startHeight,
+		Height:      startHeight - 1,
+		Upgrade1:    upgrade1,
+		Upgrade2:    upgrade2,
+	}
+}
+
+// ApplyBlocks - Adds the signatures of the blocks following the state height to the accumulators.
+// Consuming stops at the first missing height, so a gap in the ingestion is picked up later
+func ApplyBlocks(state *db.ScoreState, blocks []db.Blocks) int {
+	index := make(map[string]int, len(state.Validators))
+
+	for i, acc := range state.Validators {
+		index[acc.Address] = i
+	}
+
+	consumed := 0
+
+	for _, block := range blocks {
+		if block.Height != state.Height+1 {
+			break
+		}
+
+		for _, address := range block.Validators {
+			i, ok := index[address]
+			if !ok {
+				i = len(state.Validators)
+				index[address] = i
+				state.Validators = append(state.Validators, db.Accumulator{Address: address})
+			}
+
+			acc := &state.Validators[i]
+			acc.UptimeCount++
+
+			if acc.Upgrade1Block == 0 && inUpgradeWindow(state.Upgrade1, block.Height) {
+				acc.Upgrade1Block = block.Height
+			}
+
+			if acc.Upgrade2Block == 0 && inUpgradeWindow(state.Upgrade2, block.Height) {
+				acc.Upgrade2Block = block.Height
+			}
+		}
+
+		state.Height = block.Height
+		consumed++
+	}
+
+	return consumed
+}
+
+// inUpgradeWindow - Votes are considered from the next block after the upgrade, same as the aggregate query
+func inUpgradeWindow(window upgrades.Window, height int64) bool {
+	return window.PointsPerBlock > 0 && height >= window.StartBlock+1 && height <= window.EndBlock+1
+}
+
+// UpdateLiveScores - Consumes the heights ingested since the last update of the run, a checkpoint
+// is stored every interval blocks so standings at past heights only scan the blocks since it
+func (h handler) UpdateLiveScores(run string, interval int64) (*db.ScoreState, int, error) {
+	state, err := h.db.LoadScoreState(run)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if state == nil {
+		return nil, 0, fmt.Errorf("scoring run %s is not initialised", run)
+	}
+
+	latest, err := h.db.QueryLatestHeight()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+
+	for state.Height < latest {
+		to := state.Height + liveBatchSize
+		if to > latest {
+			to = latest
+		}
+
+		blocks, err := h.db.QueryBlocks(state.Height+1, to)
+		if err != nil {
+			return nil, total, err
+		}
+
+		consumed := 0
+
+		for _, block := range blocks {
+			if ApplyBlocks(state, []db.Blocks{block}) == 0 {
+				break
+			}
+
+			consumed++
+
+			if interval > 0 && state.Height%interval == 0 {
+				if err := h.db.SaveCheckpoint(state); err != nil {
+					return nil, total, err
+				}
+			}
+		}
+
+		total += consumed
+
+		if err := h.db.SaveScoreState(state); err != nil {
+			return nil, total, err
+		}
+
+		//Wait for the missing height to be ingested
+		if consumed == 0 || state.Height < to {
+			break
+		}
+	}
+
+	return state, total, nil
+}
+
+// LiveStandings - Scores a run at the given height, the head of the run when height is 0. Past
+// heights start from the nearest checkpoint below and only scan the blocks after it
+func (h handler) LiveStandings(run string, height int64) (*Results, error) {
+	head, err := h.db.LoadScoreState(run)
+	if err != nil {
+		return nil, err
+	}
+
+	if head == nil {
+		return nil, fmt.Errorf("scoring run %s is not initialised", run)
+	}
+
+	state := head
+
+	if height > head.Height {
+		return nil, fmt.Errorf("height %d is not consumed yet, run %s is at %d", height, run, head.Height)
+	}
+
+	if height > 0 && height < head.Height {
+		state, err = h.db.LoadCheckpoint(run, height)
+		if err != nil {
+			return nil, err
+		}
+
+		if state == nil {
+			state = &db.ScoreState{
+				Run: run, StartHeight: head.StartHeight, Height: head.StartHeight - 1,
+				Upgrade1: head.Upgrade1, Upgrade2: head.Upgrade2,
+			}
+		}
+
+		blocks, err := h.db.QueryBlocks(state.Height+1, height)
+		if err != nil {
+			return nil, err
+		}
+
+		ApplyBlocks(state, blocks)
+
+		if state.Height != height {
+			return nil, fmt.Errorf("block %d is missing", state.Height+1)
+		}
+	}
+
+	if state.Height <= state.StartHeight {
+		return nil, fmt.Errorf("run %s has no blocks after its start height %d", run, state.StartHeight)
+	}
+
+	return h.scoreState(state)
+}
+
+// scoreState - Scores the accumulators like an aggregate over the consumed block range
+func (h handler) scoreState(state *db.ScoreState) (*Results, error) {
+	validators, err := h.db.QueryValidators()
+	if err != nil {
+		return nil, err
+	}
+
+	details := make(map[string]db.Validator_details, len(validators))
+
+	for _, v := range validators {
+		details[v.Address] = db.Validator_details{
+			Description:      v.Description,
+			Operator_address: v.OperatorAddress,
+			Address:          v.Address,
+		}
+	}
+
+	var aggregates []db.ValAggregateResult
+
+	for _, acc := range state.Validators {
+		aggregate := db.ValAggregateResult{
+			Id:             acc.Address,
+			Uptime_count:   acc.UptimeCount,
+			Upgrade1_block: acc.Upgrade1Block,
+			Upgrade2_block: acc.Upgrade2Block,
+		}
+
+		if detail, ok := details[acc.Address]; ok {
+			aggregate.Validator_details = []db.Validator_details{detail}
+		}
+
+		aggregates = append(aggregates, aggregate)
+	}
+
+	return h.scoreAggregates(aggregates, state.StartHeight, state.Height, state.Upgrade1, state.Upgrade2), nil
+}
@@ -0,0 +1,138 @@
+package src
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/upgrades"
+)
+
+func block(height int64, validators ...string) db.Blocks {
+	return db.Blocks{Height: height, Validators: validators}
+}
+
+func TestApplyBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		blocks   []db.Blocks
+		consumed int
+		height   int64
+		want     []db.Accumulator
+	}{
+		{
+			name:     "consecutive heights",
+			blocks:   []db.Blocks{block(10, "A", "B"), block(11, "A"), block(12, "B", "C")},
+			consumed: 3,
+			height:   12,
+			want: []db.Accumulator{
+				{Address: "A", UptimeCount: 2, Upgrade1Block: 11},
+				{Address: "B", UptimeCount: 2, Upgrade1Block: 12},
+				{Address: "C", UptimeCount: 1, Upgrade1Block: 12},
+			},
+		},
+		{
+			name:     "stops at the first missing height",
+			blocks:   []db.Blocks{block(10, "A"), block(12, "A", "B"), block(13, "B")},
+			consumed: 1,
+			height:   10,
+			want:     []db.Accumulator{{Address: "A", UptimeCount: 1}},
+		},
+		{
+			name:   "nothing consumed before the next height",
+			blocks: []db.Blocks{block(11, "A")},
+			height: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//Upgrade-1 at 10 votes from the next block, up to 10 blocks after the upgrade
+			state := &db.ScoreState{
+				Run: "test", StartHeight: 10, Height: 9,
+				Upgrade1: upgrades.Window{StartBlock: 10, EndBlock: 20, PointsPerBlock: 5},
+			}
+
+			if consumed := ApplyBlocks(state, tt.blocks); consumed != tt.consumed {
+				t.Errorf("consumed = %d, want %d", consumed, tt.consumed)
+			}
+
+			if state.Height != tt.height {
+				t.Errorf("height = %d, want %d", state.Height, tt.height)
+			}
+
+			if !reflect.DeepEqual(state.Validators, tt.want) {
+				t.Errorf("accumulators = %+v, want %+v", state.Validators, tt.want)
+			}
+		})
+	}
+}
+
+// TestApplyBlocksGap - A gap is consumed once the missing height is ingested, giving the same
+// accumulators as consuming the blocks in one go
+func TestApplyBlocksGap(t *testing.T) {
+	blocks := []db.Blocks{block(1, "A"), block(2, "A", "B"), block(3, "B"), block(4, "A")}
+
+	whole := &db.ScoreState{StartHeight: 1}
+	ApplyBlocks(whole, blocks)
+
+	state := &db.ScoreState{StartHeight: 1}
+
+	if consumed := ApplyBlocks(state, []db.Blocks{blocks[0], blocks[2], blocks[3]}); consumed != 1 {
+		t.Fatalf("consumed %d blocks over the gap", consumed)
+	}
+
+	if consumed := ApplyBlocks(state, blocks[1:]); consumed != 3 {
+		t.Fatalf("consumed %d blocks after filling the gap, want 3", consumed)
+	}
+
+	if !reflect.DeepEqual(state, whole) {
+		t.Errorf("state = %+v, want %+v", state, whole)
+	}
+}
+
+// liveStore serves the head of a run without checkpoints
+type liveStore struct {
+	memStore
+	head db.ScoreState
+}
+
+func (s *liveStore) LoadScoreState(run string) (*db.ScoreState, error) {
+	head := s.head
+	return &head, nil
+}
+
+func (s *liveStore) LoadCheckpoint(run string, height int64) (*db.ScoreState, error) {
+	return nil, nil
+}
+
+// TestLiveStandingsSingleBlock - Uptime is shared over the blocks after the start height, a run
+// scored at its start height has none and is rejected instead of dividing by zero
+func TestLiveStandingsSingleBlock(t *testing.T) {
+	tests := []struct {
+		name   string
+		head   int64
+		height int64
+	}{
+		{name: "nothing consumed", head: 9},
+		{name: "head at the start height", head: 10},
+		{name: "past height at the start height", head: 12, height: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &liveStore{
+				memStore: memStore{blocks: []db.Blocks{block(10, "A"), block(11, "A"), block(12, "A")}},
+				head:     db.ScoreState{Run: "test", StartHeight: 10, Height: tt.head},
+			}
+
+			results, err := New(store, &config.Config{}).LiveStandings("test", tt.height)
+
+			want := "run test has no blocks after its start height 10"
+			if err == nil || err.Error() != want || results != nil {
+				t.Errorf("LiveStandings = %v, %v, want error %q", results, err, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package src
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/rpc"
+)
+
+// TwoThirds is the share of the voting power which has to sign for a block to be committed
+const TwoThirds = 2.0 / 3.0
+
+// WeightedUptime is the signed voting power of a validator over a window. Share is its part of
+// the power signed by all validators, PowerUptime the part of its own power it signed with
+type WeightedUptime struct {
+	Address       string  `json:"address"`
+	OperatorAddr  string  `json:"operatorAddr"`
+	Moniker       string  `json:"moniker"`
+	SignedBlocks  int64   `json:"signedBlocks"`
+	ActiveBlocks  int64   `json:"activeBlocks"`
+	SignedPower   int64   `json:"signedPower"`
+	ExpectedPower int64   `json:"expectedPower"`
+	Share         float64 `json:"share"`
+	PowerUptime   float64 `json:"powerUptime"`
+}
+
+// BlockSigning is the voting power which signed the commit included in a block, missing
+// lists the validators of the set which didn't sign, highest power first
+type BlockSigning struct {
+	Height      int64               `json:"height"`
+	TotalPower  int64               `json:"totalPower"`
+	SignedPower int64               `json:"signedPower"`
+	SignedRatio float64             `json:"signedRatio"`
+	Missing     []db.ValidatorPower `json:"missing"`
+}
+
+// PowerReport holds the power weighted metrics of a window, heights without ingested
+// voting power are skipped and counted
+type PowerReport struct {
+	StartBlock     int64            `json:"startBlock"`
+	EndBlock       int64            `json:"endBlock"`
+	Heights        int64            `json:"heights"`
+	MissingHeights int64            `json:"missingHeights"`
+	Validators     []WeightedUptime `json:"validators"`
+	Blocks         []BlockSigning   `json:"blocks"`
+}
+
+// IngestVotingPower - Stores the voting power of every height in between from and to. The power
+// stored for a height is the validator set of the previous height, which signed its last commit
+func (h handler) IngestVotingPower(client *rpc.Client, fromHeight, toHeight int64) (int, error) {
+	ingested := 0
+
+	for height := fromHeight; height <= toHeight; height++ {
+		setHeight := height - 1
+		if setHeight < 1 {
+			setHeight = 1
+		}
+
+		validators, err := client.Validators(setHeight)
+		if err != nil {
+			return ingested, fmt.Errorf("height %d: %v", height, err)
+		}
+
+		power := &db.BlockPower{Height: height, Validators: validators}
+
+		for _, v := range validators {
+			power.TotalPower += v.VotingPower
+		}
+
+		if err := h.db.SaveBlockPower(power); err != nil {
+			return ingested, err
+		}
+
+		ingested++
+	}
+
+	return ingested, nil
+}
+
+// PowerReport - Calculates the power weighted uptime of the validators and the signed power of
+// every block in between start and end block
+func (h handler) PowerReport(startBlock, endBlock int64) (*PowerReport, error) {
+	report := &PowerReport{StartBlock: startBlock, EndBlock: endBlock}
+
+	byAddress := make(map[string]*WeightedUptime)
+
+	var order []string
+
+	uptime := func(address string) *WeightedUptime {
+		u, ok := byAddress[address]
+		if !ok {
+			u = &WeightedUptime{Address: address}
+			byAddress[address] = u
+			order = append(order, address)
+		}
+
+		return u
+	}
+
+	var totalSigned int64
+
+	for from := startBlock; from <= endBlock; from += liveBatchSize {
+		to := from + liveBatchSize - 1
+		if to > endBlock {
+			to = endBlock
+		}
+
+		blocks, err := h.db.QueryBlocks(from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		powers, err := h.db.QueryBlockPowers(from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		byHeight := make(map[int64]db.BlockPower, len(powers))
+
+		for _, power := range powers {
+			byHeight[power.Height] = power
+		}
+
+		for _, block := range blocks {
+			power, ok := byHeight[block.Height]
+			if !ok {
+				report.MissingHeights++
+				continue
+			}
+
+			signed := signers(block)
+
+			signing := signBlock(block.Height, signed, power)
+			report.Blocks = append(report.Blocks, signing)
+			report.Heights++
+			totalSigned += signing.SignedPower
+
+			for _, v := range power.Validators {
+				u := uptime(v.Address)
+				u.ActiveBlocks++
+				u.ExpectedPower += v.VotingPower
+
+				if signed[v.Address] {
+					u.SignedBlocks++
+					u.SignedPower += v.VotingPower
+				}
+			}
+		}
+
+		report.MissingHeights += (to - from + 1) - int64(len(blocks))
+	}
+
+	validators, err := h.db.QueryValidators()
+	if err != nil {
+		return nil, err
+	}
+
+	operators := make(map[string]db.Validator, len(validators))
+
+	for _, v := range validators {
+		operators[v.Address] = v
+	}
+
+	for _, address := range order {
+		u := byAddress[address]
+
+		if v, ok := operators[address]; ok {
+			u.OperatorAddr = v.OperatorAddress
+			u.Moniker = v.Description.Moniker
+		}
+
+		if totalSigned > 0 {
+			u.Share = float64(u.SignedPower) / float64(totalSigned)
+		}
+
+		if u.ExpectedPower > 0 {
+			u.PowerUptime = float64(u.SignedPower) / float64(u.ExpectedPower)
+		}
+
+		report.Validators = append(report.Validators, *u)
+	}
+
+	sort.SliceStable(report.Validators, func(i, j int) bool {
+		return report.Validators[i].SignedPower > report.Validators[j].SignedPower
+	})
+
+	return report, nil
+}
+
+// Below - Returns the blocks signed by less than the given ratio of the voting power, lowest first
+func (r *PowerReport) Below(ratio float64) []BlockSigning {
+	var blocks []BlockSigning
+
+	for _, b := range r.Blocks {
+		if b.SignedRatio < ratio {
+			blocks = append(blocks, b)
+		}
+	}
+
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].SignedRatio < blocks[j].SignedRatio })
+
+	return blocks
+}
+
+func signers(block db.Blocks) map[string]bool {
+	signed := make(map[string]bool, len(block.Validators))
+
+	for _, address := range block.Validators {
+		signed[address] = true
+	}
+
+	return signed
+}
+
+func signBlock(height int64, signed map[string]bool, power db.BlockPower) BlockSigning {
+	signing := BlockSigning{Height: height, TotalPower: power.TotalPower}
+
+	for _, v := range power.Validators {
+		if signed[v.Address] {
+			signing.SignedPower += v.VotingPower
+		} else {
+			signing.Missing = append(signing.Missing, v)
+		}
+	}
+
+	sort.SliceStable(signing.Missing, func(i, j int) bool {
+		return signing.Missing[i].VotingPower > signing.Missing[j].VotingPower
+	})
+
+	if power.TotalPower > 0 {
+		signing.SignedRatio = float64(signing.SignedPower) / float64(power.TotalPower)
+	}
+
+	return signing
+}
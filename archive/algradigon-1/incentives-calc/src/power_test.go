@@ -0,0 +1,183 @@
+package src
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// memStore serves the ingested data from memory, queries a test does not need are left to
+// the nil DB and panic
+type memStore struct {
+	db.DB
+	blocks     []db.Blocks
+	powers     []db.BlockPower
+	headers    []db.BlockHeader
+	evidence   []db.Evidence
+	validators []db.Validator
+}
+
+func (s *memStore) QueryBlocks(fromHeight, toHeight int64) ([]db.Blocks, error) {
+	var result []db.Blocks
+
+	for _, b := range s.blocks {
+		if b.Height >= fromHeight && b.Height <= toHeight {
+			result = append(result, b)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *memStore) QueryBlockPowers(fromHeight, toHeight int64) ([]db.BlockPower, error) {
+	var result []db.BlockPower
+
+	for _, p := range s.powers {
+		if p.Height >= fromHeight && p.Height <= toHeight {
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *memStore) QueryBlockHeaders(fromHeight, toHeight int64) ([]db.BlockHeader, error) {
+	var result []db.BlockHeader
+
+	for _, h := range s.headers {
+		if h.Height >= fromHeight && h.Height <= toHeight {
+			result = append(result, h)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *memStore) QueryEvidence(fromHeight, toHeight int64) ([]db.Evidence, error) {
+	var result []db.Evidence
+
+	for _, e := range s.evidence {
+		if e.Height >= fromHeight && e.Height <= toHeight {
+			result = append(result, e)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *memStore) QueryValidators() ([]db.Validator, error) {
+	return s.validators, nil
+}
+
+func blockPower(height int64, powers ...db.ValidatorPower) db.BlockPower {
+	power := db.BlockPower{Height: height, Validators: powers}
+
+	for _, v := range powers {
+		power.TotalPower += v.VotingPower
+	}
+
+	return power
+}
+
+// powerStore - A has 60 of the power, B 30 and C 10. Height 3 has no power and height 5 no block
+func powerStore() *memStore {
+	set := []db.ValidatorPower{{Address: "A", VotingPower: 60}, {Address: "B", VotingPower: 30}, {Address: "C", VotingPower: 10}}
+
+	return &memStore{
+		blocks: []db.Blocks{
+			block(1, "A", "B", "C"), block(2, "A", "B"), block(3, "A"), block(4, "A", "C"),
+		},
+		powers: []db.BlockPower{blockPower(1, set...), blockPower(2, set...), blockPower(4, set...)},
+		validators: []db.Validator{
+			{Address: "A", OperatorAddress: "xrn:valoper1a", Description: db.Description{Moniker: "alpha"}},
+		},
+	}
+}
+
+func TestPowerReport(t *testing.T) {
+	report, err := New(powerStore(), &config.Config{}).PowerReport(1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Heights != 3 || report.MissingHeights != 2 {
+		t.Errorf("heights = %d missing %d, want 3 missing 2", report.Heights, report.MissingHeights)
+	}
+
+	tests := []struct {
+		address      string
+		moniker      string
+		signedBlocks int64
+		signedPower  int64
+		expected     int64
+		share        float64
+		powerUptime  float64
+	}{
+		{"A", "alpha", 3, 180, 180, 180.0 / 260, 1},
+		{"B", "", 2, 60, 90, 60.0 / 260, 2.0 / 3},
+		{"C", "", 2, 20, 30, 20.0 / 260, 2.0 / 3},
+	}
+
+	if len(report.Validators) != len(tests) {
+		t.Fatalf("validators = %+v, want %d", report.Validators, len(tests))
+	}
+
+	for i, tt := range tests {
+		u := report.Validators[i]
+
+		if u.Address != tt.address || u.Moniker != tt.moniker || u.SignedBlocks != tt.signedBlocks ||
+			u.ActiveBlocks != 3 || u.SignedPower != tt.signedPower || u.ExpectedPower != tt.expected ||
+			math.Abs(u.Share-tt.share) > 1e-9 || math.Abs(u.PowerUptime-tt.powerUptime) > 1e-9 {
+			t.Errorf("validator %d = %+v, want %+v", i, u, tt)
+		}
+	}
+
+	var signed []int64
+	var missing [][]string
+
+	for _, b := range report.Blocks {
+		signed = append(signed, b.SignedPower)
+
+		var addresses []string
+		for _, v := range b.Missing {
+			addresses = append(addresses, v.Address)
+		}
+
+		missing = append(missing, addresses)
+	}
+
+	if !reflect.DeepEqual(signed, []int64{100, 90, 70}) || !reflect.DeepEqual(missing, [][]string{nil, {"C"}, {"B"}}) {
+		t.Errorf("blocks signed %v missing %v", signed, missing)
+	}
+}
+
+func TestPowerReportBelow(t *testing.T) {
+	report, err := New(powerStore(), &config.Config{}).PowerReport(1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ratio float64
+		want  []int64
+	}{
+		{ratio: TwoThirds},
+		{ratio: 0.8, want: []int64{4}},
+		{ratio: 0.95, want: []int64{4, 2}},
+		{ratio: 1.01, want: []int64{4, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		var heights []int64
+		for _, b := range report.Below(tt.ratio) {
+			heights = append(heights, b.Height)
+		}
+
+		if !reflect.DeepEqual(heights, tt.want) {
+			t.Errorf("Below(%f) = %v, want %v", tt.ratio, heights, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package src
+
+import (
+	"math"
+	"sort"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// ProposerStats compares the blocks a validator proposed with the number expected from its
+// share of the voting power, proposer selection is weighted round robin on voting power
+type ProposerStats struct {
+	Address      string  `json:"address"`
+	OperatorAddr string  `json:"operatorAddr"`
+	Moniker      string  `json:"moniker"`
+	Proposed     int64   `json:"proposed"`
+	Expected     float64 `json:"expected"`
+	Ratio        float64 `json:"ratio"`
+	Flagged      bool    `json:"flagged"`
+}
+
+// ProposerReport holds the proposer fairness of a window, heights without an ingested header
+// or voting power are skipped and counted
+type ProposerReport struct {
+	StartBlock     int64           `json:"startBlock"`
+	EndBlock       int64           `json:"endBlock"`
+	Heights        int64           `json:"heights"`
+	MissingHeights int64           `json:"missingHeights"`
+	Validators     []ProposerStats `json:"validators"`
+}
+
+// ProposerFairness - Counts the blocks proposed by every validator in between start and end block
+// and the expected number from the voting power ingested with power ingest. Validators expected to
+// propose at least minExpected blocks which proposed less than minRatio of them are flagged
+func (h handler) ProposerFairness(startBlock, endBlock int64, minRatio, minExpected float64) (*ProposerReport, error) {
+	report := &ProposerReport{StartBlock: startBlock, EndBlock: endBlock}
+
+	byAddress := make(map[string]*ProposerStats)
+
+	stats := func(address string) *ProposerStats {
+		s, ok := byAddress[address]
+		if !ok {
+			s = &ProposerStats{Address: address}
+			byAddress[address] = s
+		}
+
+		return s
+	}
+
+	for from := startBlock; from <= endBlock; from += liveBatchSize {
+		to := from + liveBatchSize - 1
+		if to > endBlock {
+			to = endBlock
+		}
+
+		headers, err := h.db.QueryBlockHeaders(from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		//The set of a height is stored with the next height, which includes its commit
+		powers, err := h.db.QueryBlockPowers(from, to+1)
+		if err != nil {
+			return nil, err
+		}
+
+		byHeight := make(map[int64]db.BlockPower, len(powers))
+
+		for _, power := range powers {
+			byHeight[power.Height] = power
+		}
+
+		for _, header := range headers {
+			power, ok := byHeight[header.Height+1]
+			if !ok {
+				power, ok = byHeight[header.Height]
+			}
+
+			if !ok || power.TotalPower == 0 {
+				report.MissingHeights++
+				continue
+			}
+
+			report.Heights++
+			stats(header.Proposer).Proposed++
+
+			for _, v := range power.Validators {
+				stats(v.Address).Expected += float64(v.VotingPower) / float64(power.TotalPower)
+			}
+		}
+
+		report.MissingHeights += (to - from + 1) - int64(len(headers))
+	}
+
+	validators, err := h.db.QueryValidators()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range validators {
+		if s, ok := byAddress[v.Address]; ok {
+			s.OperatorAddr = v.OperatorAddress
+			s.Moniker = v.Description.Moniker
+		}
+	}
+
+	for _, s := range byAddress {
+		if s.Expected > 0 {
+			s.Ratio = float64(s.Proposed) / s.Expected
+		}
+
+		s.Flagged = s.Expected >= minExpected && s.Ratio < minRatio
+
+		report.Validators = append(report.Validators, *s)
+	}
+
+	//Largest shortfall first
+	sort.Slice(report.Validators, func(i, j int) bool {
+		a, b := report.Validators[i], report.Validators[j]
+		if a.Ratio != b.Ratio {
+			return a.Ratio < b.Ratio
+		}
+
+		return a.Address < b.Address
+	})
+
+	return report, nil
+}
+
+// Find - Returns the stats of a validator by hex address
+func (r *ProposerReport) Find(address string) (ProposerStats, bool) {
+	for _, s := range r.Validators {
+		if s.Address == address {
+			return s, true
+		}
+	}
+
+	return ProposerStats{}, false
+}
+
+// proposerPoints - The points scale with the proposed blocks over the expected ones, capped at the
+// configured points. Validators without expected proposals get none
+func proposerPoints(points int64, stats ProposerStats) float64 {
+	if stats.Expected == 0 {
+		return 0
+	}
+
+	return float64(points) * math.Min(1, stats.Ratio)
+}
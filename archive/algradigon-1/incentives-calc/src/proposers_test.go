@@ -0,0 +1,94 @@
+package src
+
+import (
+	"math"
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// proposerStore - A has 75 of the power and B 25. B proposes height 3 and A the others, height 6
+// has no header and height 1 no voting power. The set of height 10 is only stored with height 10
+func proposerStore() *memStore {
+	s := &memStore{validators: []db.Validator{
+		{Address: "B", OperatorAddress: "xrn:valoper1b", Description: db.Description{Moniker: "bravo"}},
+	}}
+
+	for height := int64(1); height <= 10; height++ {
+		if height != 6 {
+			proposer := "A"
+			if height == 3 {
+				proposer = "B"
+			}
+
+			s.headers = append(s.headers, db.BlockHeader{Height: height, Proposer: proposer})
+		}
+
+		if height >= 3 {
+			s.powers = append(s.powers, blockPower(height,
+				db.ValidatorPower{Address: "A", VotingPower: 75}, db.ValidatorPower{Address: "B", VotingPower: 25}))
+		}
+	}
+
+	return s
+}
+
+func TestProposerFairness(t *testing.T) {
+	tests := []struct {
+		name        string
+		minRatio    float64
+		minExpected float64
+		flagged     bool
+	}{
+		{name: "below the ratio", minRatio: 0.6, minExpected: 2, flagged: true},
+		{name: "at the ratio", minRatio: 0.5, minExpected: 2},
+		{name: "too few expected blocks", minRatio: 0.6, minExpected: 2.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := New(proposerStore(), &config.Config{}).ProposerFairness(1, 10, tt.minRatio, tt.minExpected)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if report.Heights != 8 || report.MissingHeights != 2 {
+				t.Errorf("heights = %d missing %d, want 8 missing 2", report.Heights, report.MissingHeights)
+			}
+
+			if len(report.Validators) != 2 {
+				t.Fatalf("validators = %+v", report.Validators)
+			}
+
+			//Largest shortfall first
+			b, a := report.Validators[0], report.Validators[1]
+
+			if b.Address != "B" || b.Moniker != "bravo" || b.Proposed != 1 || math.Abs(b.Expected-2) > 1e-9 ||
+				math.Abs(b.Ratio-0.5) > 1e-9 || b.Flagged != tt.flagged {
+				t.Errorf("B = %+v, want 1 of 2 expected, flagged %v", b, tt.flagged)
+			}
+
+			if a.Address != "A" || a.Proposed != 7 || math.Abs(a.Expected-6) > 1e-9 || a.Flagged {
+				t.Errorf("A = %+v, want 7 of 6 expected", a)
+			}
+		})
+	}
+}
+
+func TestProposerPoints(t *testing.T) {
+	tests := []struct {
+		stats ProposerStats
+		want  float64
+	}{
+		{ProposerStats{}, 0},
+		{ProposerStats{Expected: 4, Proposed: 2, Ratio: 0.5}, 50},
+		{ProposerStats{Expected: 2, Proposed: 3, Ratio: 1.5}, 100},
+	}
+
+	for _, tt := range tests {
+		if got := proposerPoints(100, tt.stats); got != tt.want {
+			t.Errorf("proposerPoints(%+v) = %f, want %f", tt.stats, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package src
+
+import (
+	"fmt"
+
+	"github.com/regen-friends/testnets/util/uptime/address"
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/genesis"
+)
+
+// OperatorInfo is the operator identity behind a tendermint hex address
+type OperatorInfo struct {
+	OperatorAddr string `json:"operatorAddr"`
+	Moniker      string `json:"moniker"`
+	ConsPubKey   string `json:"consPubKey"`
+}
+
+// Resolver maps hex addresses found in blocks to validator operators
+type Resolver struct {
+	operators map[string]OperatorInfo
+}
+
+func NewResolver() *Resolver {
+	return &Resolver{operators: make(map[string]OperatorInfo)}
+}
+
+// AddCreateValidatorMsgs - Adds the operators from create validator messages,
+// messages with a pubkey that can't be decoded are skipped
+func (r *Resolver) AddCreateValidatorMsgs(msgs []db.Msg) {
+	for _, msg := range msgs {
+		hexAddr, err := address.ConsPubKeyToHexAddress(msg.Value.Pubkey)
+		if err != nil {
+			fmt.Printf("Skipping create validator msg of %s: %v\n", msg.Value.ValidatorAddress, err)
+			continue
+		}
+
+		r.operators[hexAddr] = OperatorInfo{
+			OperatorAddr: msg.Value.ValidatorAddress,
+			Moniker:      msg.Value.Description.Moniker,
+			ConsPubKey:   msg.Value.Pubkey,
+		}
+	}
+}
+
+// AddGenesis - Adds the operators from the gentxs of a genesis file
+func (r *Resolver) AddGenesis(path string) error {
+	gen, err := genesis.Load(path)
+	if err != nil {
+		return err
+	}
+
+	msgs, err := gen.CreateValidatorMsgs()
+	if err != nil {
+		return err
+	}
+
+	r.AddCreateValidatorMsgs(msgs)
+
+	return nil
+}
+
+// AddCreateValidatorTxs - Adds the operators from create validator transactions,
+// later transactions take precedence over gentxs
+func (r *Resolver) AddCreateValidatorTxs(txs []db.Transaction) {
+	for _, tx := range txs {
+		var msgs []db.Msg
+
+		for _, msg := range tx.Msgs {
+			if msg.Type == db.MSG_CREATE_VALIDATOR {
+				msgs = append(msgs, msg)
+			}
+		}
+
+		r.AddCreateValidatorMsgs(msgs)
+	}
+}
+
+// Resolve - Returns the operator of given hex address
+func (r *Resolver) Resolve(hexAddr string) (OperatorInfo, bool) {
+	info, ok := r.operators[hexAddr]
+	return info, ok
+}
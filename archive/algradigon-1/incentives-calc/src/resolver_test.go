@@ -0,0 +1,47 @@
+package src
+
+import (
+	"testing"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+// ed25519 key of bytes 0 to 31 as a valconspub, see address/address_test.go
+const (
+	testConsPub = "xrn:valconspub1zcjduepqqqqsyqcyq5rqwzqfpg9scrgwpugpzysnzs23v9ccrydpk8qarc0ssl033y"
+	testHexAddr = "630DCD2966C4336691125448BBB25B4FF412A49C"
+)
+
+func createValidatorTx(operator, moniker, pubKey string) db.Transaction {
+	return db.Transaction{Msgs: []db.Msg{
+		{Type: db.MSG_CREATE_VALIDATOR, Value: db.MsgValue{
+			ValidatorAddress: operator, Pubkey: pubKey, Description: db.Description{Moniker: moniker},
+		}},
+		{Type: "cosmos-sdk/MsgSend"},
+	}}
+}
+
+func TestResolver(t *testing.T) {
+	r := NewResolver()
+
+	r.AddCreateValidatorMsgs(createValidatorTx("xrn:valoper1gentx", "gentx", testConsPub).Msgs)
+
+	//Later create validator txs take precedence, undecodable pubkeys are skipped
+	r.AddCreateValidatorTxs([]db.Transaction{
+		createValidatorTx("xrn:valoper1tx", "tx", testConsPub),
+		createValidatorTx("xrn:valoper1bad", "bad", "xrn:valconspub1invalid"),
+	})
+
+	info, ok := r.Resolve(testHexAddr)
+	if !ok || info.OperatorAddr != "xrn:valoper1tx" || info.Moniker != "tx" || info.ConsPubKey != testConsPub {
+		t.Errorf("Resolve = %+v %v, want the operator of the create validator tx", info, ok)
+	}
+
+	if _, ok := r.Resolve("0000000000000000000000000000000000000000"); ok {
+		t.Error("Resolve found an unknown address")
+	}
+
+	if len(r.operators) != 1 {
+		t.Errorf("resolver has %d operators, want 1", len(r.operators))
+	}
+}
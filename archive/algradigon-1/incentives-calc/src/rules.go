@@ -0,0 +1,100 @@
+package src
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+
+	"github.com/regen-friends/testnets/util/uptime/config"
+	"github.com/regen-friends/testnets/util/uptime/upgrades"
+)
+
+// Rules are the scoring configs resolved from config.toml and the upgrade registry
+type Rules struct {
+	NodeRewards          int64           `json:"nodeRewards"`
+	MaxUptimeRewards     int64           `json:"maxUptimeRewards"`
+	ProposerPoints       int64           `json:"proposerPoints,omitempty"`
+	DoubleSignDisqualify []string        `json:"doubleSignDisqualify,omitempty"`
+	Upgrade1             upgrades.Window `json:"upgrade1"`
+	Upgrade2             upgrades.Window `json:"upgrade2"`
+	Proposal1Voters      []string        `json:"proposal1Voters"`
+	Proposal2Voters      []string        `json:"proposal2Voters"`
+	GentxValidators      []string        `json:"gentxValidators"`
+	GenesisFile          string          `json:"genesisFile,omitempty"`
+	GenesisSHA256        string          `json:"genesisSha256,omitempty"`
+	AdjustmentsFile      string          `json:"adjustmentsFile,omitempty"`
+	AdjustmentsSHA256    string          `json:"adjustmentsSha256,omitempty"`
+}
+
+// ResolveRules - Returns the scoring configs the calculation would use
+func ResolveRules(cfg *config.Config) (Rules, error) {
+	upgrade1, upgrade2 := LoadUpgradeWindows(cfg)
+
+	rules := Rules{
+		NodeRewards:          cfg.NodeRewards,
+		MaxUptimeRewards:     cfg.MaxUptimeRewards,
+		ProposerPoints:       cfg.ProposerPoints,
+		DoubleSignDisqualify: cfg.DoubleSignDisqualify,
+		Upgrade1:             upgrade1,
+		Upgrade2:             upgrade2,
+		Proposal1Voters:      cfg.ElChocoVoteValidators,
+		Proposal2Voters:      cfg.AmazonasVoteValidators,
+		GentxValidators:      cfg.GentxValidators,
+		GenesisFile:          cfg.GenesisFile,
+	}
+
+	if rules.GenesisFile != "" {
+		sum, err := FileSHA256(rules.GenesisFile)
+		if err != nil {
+			return rules, err
+		}
+
+		rules.GenesisSHA256 = sum
+	}
+
+	if rules.AdjustmentsFile = cfg.AdjustmentsFile; rules.AdjustmentsFile != "" {
+		sum, err := FileSHA256(rules.AdjustmentsFile)
+		if err != nil {
+			return rules, err
+		}
+
+		rules.AdjustmentsSHA256 = sum
+	}
+
+	return rules, nil
+}
+
+// Apply - Returns a copy of the config with the rules, so a calculation can be re-run with them.
+// The database settings are kept
+func (r Rules) Apply(cfg *config.Config) *config.Config {
+	applied := *cfg
+
+	applied.NodeRewards = r.NodeRewards
+	applied.MaxUptimeRewards = r.MaxUptimeRewards
+	applied.ProposerPoints = r.ProposerPoints
+	applied.DoubleSignDisqualify = r.DoubleSignDisqualify
+
+	applied.UpgradesFile = ""
+	applied.ElChoco = r.Upgrade1
+	applied.Amazonas = r.Upgrade2
+
+	applied.ElChocoVoteValidators = r.Proposal1Voters
+	applied.AmazonasVoteValidators = r.Proposal2Voters
+	applied.GentxValidators = r.GentxValidators
+	applied.GenesisFile = r.GenesisFile
+	applied.AdjustmentsFile = r.AdjustmentsFile
+
+	return &applied
+}
+
+// FileSHA256 - Returns the hex encoded sha256 of a file
+func FileSHA256(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,326 @@
+package src
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+	"github.com/regen-friends/testnets/util/uptime/rpc"
+)
+
+// TxProfileOptions tune the profile, Top limits the senders listed (0 for all) and Bucket is the
+// length of the throughput buckets
+type TxProfileOptions struct {
+	Top    int
+	Bucket time.Duration
+}
+
+// TxStats are the load of a group of txs, fees are summed per denom
+type TxStats struct {
+	Txs         int64     `json:"txs"`
+	Msgs        int64     `json:"msgs"`
+	Failed      int64     `json:"failed"`
+	FailureRate float64   `json:"failureRate"`
+	GasWanted   int64     `json:"gasWanted"`
+	GasUsed     int64     `json:"gasUsed"`
+	Fees        []db.Coin `json:"fees"`
+
+	fees map[string]*big.Int
+}
+
+// BlockTxProfile is the load of a block
+type BlockTxProfile struct {
+	Height int64     `json:"height"`
+	Time   time.Time `json:"time"`
+	TxStats
+}
+
+// SenderTxProfile is the load generated by an account, the sender is the first signer of a tx
+type SenderTxProfile struct {
+	Sender string `json:"sender"`
+	TxStats
+}
+
+// MsgTypeTxProfile is the load of a message type, a tx counts for every type it contains
+type MsgTypeTxProfile struct {
+	Type string `json:"type"`
+	TxStats
+}
+
+// BucketTxProfile is the throughput of a time bucket
+type BucketTxProfile struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	TxsPerSecond float64   `json:"txsPerSecond"`
+	GasPerSecond float64   `json:"gasPerSecond"`
+	TxStats
+}
+
+// TxProfile holds the tx load of a range by block, sender, message type and time bucket.
+// Senders are ordered by txs, the top spammers first
+type TxProfile struct {
+	StartBlock int64              `json:"startBlock"`
+	EndBlock   int64              `json:"endBlock"`
+	Total      TxStats            `json:"total"`
+	Blocks     []BlockTxProfile   `json:"blocks"`
+	Senders    []SenderTxProfile  `json:"senders"`
+	MsgTypes   []MsgTypeTxProfile `json:"msgTypes"`
+	Buckets    []BucketTxProfile  `json:"buckets"`
+}
+
+// ProfileTxs - Profiles the indexed txs in between start and end block, see ProfileTransactions. The
+// txs are read and aggregated one batch of blocks at a time
+func (h handler) ProfileTxs(startBlock, endBlock int64, options TxProfileOptions) (*TxProfile, error) {
+	profiler := newTxProfiler(startBlock, endBlock, options)
+
+	for from := startBlock; from <= endBlock; from += liveBatchSize {
+		to := from + liveBatchSize - 1
+		if to > endBlock {
+			to = endBlock
+		}
+
+		batch, err := h.db.QueryTxsByHeight(from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		profiler.add(batch)
+	}
+
+	return profiler.finish(), nil
+}
+
+// ProfileTransactions - Groups the txs by block, sender, message type and time bucket. It only
+// uses the given txs, so recorded fixtures (a tx dump) give the same profile as the database
+func ProfileTransactions(txs []db.Transaction, startBlock, endBlock int64, options TxProfileOptions) *TxProfile {
+	profiler := newTxProfiler(startBlock, endBlock, options)
+	profiler.add(txs)
+
+	return profiler.finish()
+}
+
+// txProfiler aggregates the txs of a profile as they are read
+type txProfiler struct {
+	profile  *TxProfile
+	options  TxProfileOptions
+	blocks   map[int64]*BlockTxProfile
+	senders  map[string]*SenderTxProfile
+	msgTypes map[string]*MsgTypeTxProfile
+	buckets  map[int64]*BucketTxProfile
+}
+
+func newTxProfiler(startBlock, endBlock int64, options TxProfileOptions) *txProfiler {
+	return &txProfiler{
+		profile:  &TxProfile{StartBlock: startBlock, EndBlock: endBlock},
+		options:  options,
+		blocks:   make(map[int64]*BlockTxProfile),
+		senders:  make(map[string]*SenderTxProfile),
+		msgTypes: make(map[string]*MsgTypeTxProfile),
+		buckets:  make(map[int64]*BucketTxProfile),
+	}
+}
+
+// add - Adds the txs within the range of the profile
+func (p *txProfiler) add(txs []db.Transaction) {
+	profile, options := p.profile, p.options
+	blocks, senders, msgTypes, buckets := p.blocks, p.senders, p.msgTypes, p.buckets
+
+	for _, tx := range txs {
+		if tx.Height < profile.StartBlock || tx.Height > profile.EndBlock {
+			continue
+		}
+
+		profile.Total.add(tx)
+
+		block, ok := blocks[tx.Height]
+		if !ok {
+			block = &BlockTxProfile{Height: tx.Height, Time: tx.Time}
+			blocks[tx.Height] = block
+		}
+
+		block.add(tx)
+
+		sender := "unknown"
+		if len(tx.Signers) > 0 {
+			sender = tx.Signers[0]
+		}
+
+		if _, ok := senders[sender]; !ok {
+			senders[sender] = &SenderTxProfile{Sender: sender}
+		}
+
+		senders[sender].add(tx)
+
+		seen := make(map[string]bool)
+
+		for _, msg := range tx.Msgs {
+			if seen[msg.Type] {
+				continue
+			}
+
+			seen[msg.Type] = true
+
+			if _, ok := msgTypes[msg.Type]; !ok {
+				msgTypes[msg.Type] = &MsgTypeTxProfile{Type: msg.Type}
+			}
+
+			msgTypes[msg.Type].add(tx)
+		}
+
+		if options.Bucket > 0 && !tx.Time.IsZero() {
+			start := tx.Time.Truncate(options.Bucket)
+
+			bucket, ok := buckets[start.Unix()]
+			if !ok {
+				bucket = &BucketTxProfile{Start: start, End: start.Add(options.Bucket)}
+				buckets[start.Unix()] = bucket
+			}
+
+			bucket.add(tx)
+		}
+	}
+}
+
+// finish - Sorts the groups of the profile and sets their rates
+func (p *txProfiler) finish() *TxProfile {
+	profile, options := p.profile, p.options
+	blocks, senders, msgTypes, buckets := p.blocks, p.senders, p.msgTypes, p.buckets
+
+	profile.Total.finish()
+
+	for _, block := range blocks {
+		block.finish()
+		profile.Blocks = append(profile.Blocks, *block)
+	}
+
+	sort.Slice(profile.Blocks, func(i, j int) bool { return profile.Blocks[i].Height < profile.Blocks[j].Height })
+
+	for _, sender := range senders {
+		sender.finish()
+		profile.Senders = append(profile.Senders, *sender)
+	}
+
+	sort.Slice(profile.Senders, func(i, j int) bool {
+		a, b := profile.Senders[i], profile.Senders[j]
+		if a.Txs != b.Txs {
+			return a.Txs > b.Txs
+		}
+
+		return a.Sender < b.Sender
+	})
+
+	if options.Top > 0 && len(profile.Senders) > options.Top {
+		profile.Senders = profile.Senders[:options.Top]
+	}
+
+	for _, msgType := range msgTypes {
+		msgType.finish()
+		profile.MsgTypes = append(profile.MsgTypes, *msgType)
+	}
+
+	sort.Slice(profile.MsgTypes, func(i, j int) bool {
+		a, b := profile.MsgTypes[i], profile.MsgTypes[j]
+		if a.Txs != b.Txs {
+			return a.Txs > b.Txs
+		}
+
+		return a.Type < b.Type
+	})
+
+	for _, bucket := range buckets {
+		bucket.finish()
+
+		seconds := options.Bucket.Seconds()
+		bucket.TxsPerSecond = float64(bucket.Txs) / seconds
+		bucket.GasPerSecond = float64(bucket.GasUsed) / seconds
+
+		profile.Buckets = append(profile.Buckets, *bucket)
+	}
+
+	sort.Slice(profile.Buckets, func(i, j int) bool { return profile.Buckets[i].Start.Before(profile.Buckets[j].Start) })
+
+	return profile
+}
+
+func (s *TxStats) add(tx db.Transaction) {
+	s.Txs++
+	s.Msgs += int64(len(tx.Msgs))
+	s.GasWanted += tx.GasWanted
+	s.GasUsed += tx.GasUsed
+
+	if tx.Code != 0 {
+		s.Failed++
+	}
+
+	if s.fees == nil {
+		s.fees = make(map[string]*big.Int)
+	}
+
+	for _, coin := range tx.Fee {
+		amount, ok := new(big.Int).SetString(coin.Amount, 10)
+		if !ok {
+			continue
+		}
+
+		if _, ok := s.fees[coin.Denom]; !ok {
+			s.fees[coin.Denom] = new(big.Int)
+		}
+
+		s.fees[coin.Denom].Add(s.fees[coin.Denom], amount)
+	}
+}
+
+// finish - Sets the failure rate and the fees ordered by denom
+func (s *TxStats) finish() {
+	if s.Txs > 0 {
+		s.FailureRate = float64(s.Failed) / float64(s.Txs)
+	}
+
+	s.Fees = nil
+
+	for denom, amount := range s.fees {
+		s.Fees = append(s.Fees, db.Coin{Denom: denom, Amount: amount.String()})
+	}
+
+	sort.Slice(s.Fees, func(i, j int) bool { return s.Fees[i].Denom < s.Fees[j].Denom })
+}
+
+// FeeString - Formats the fees like 100utree,5uxrn
+func (s TxStats) FeeString() string {
+	fees := ""
+
+	for i, coin := range s.Fees {
+		if i > 0 {
+			fees += ","
+		}
+
+		fees += coin.Amount + coin.Denom
+	}
+
+	return fees
+}
+
+// IngestTransactions - Stores the txs of every height in between from and to with their gas and
+// fee, replacing the indexed txs with the same hash
+func (h handler) IngestTransactions(lcd *rpc.LCD, fromHeight, toHeight int64) (int, error) {
+	ingested := 0
+
+	for height := fromHeight; height <= toHeight; height++ {
+		txs, err := lcd.Txs(height)
+		if err != nil {
+			return ingested, fmt.Errorf("height %d: %v", height, err)
+		}
+
+		for i := range txs {
+			if err := h.db.SaveTransaction(&txs[i]); err != nil {
+				return ingested, err
+			}
+
+			ingested++
+		}
+	}
+
+	return ingested, nil
+}
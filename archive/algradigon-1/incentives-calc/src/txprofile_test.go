@@ -0,0 +1,155 @@
+package src
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/regen-friends/testnets/util/uptime/db"
+)
+
+const (
+	spammer  = "xrn:1p4dzl2tn9vmj0q6ywmgtxx5pyv7m7ufq3h8t9c"
+	receiver = "xrn:1m9l358xunhhwds0568za49mzhvuxx9uxh3qd0s"
+)
+
+func loadExampleTxs(t *testing.T) []db.Transaction {
+	dump, err := db.LoadTxDump("../txs.json.example")
+	if err != nil {
+		t.Fatalf("loading txs.json.example: %v", err)
+	}
+
+	txs, err := dump.QueryTxsByHeight(0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return txs
+}
+
+func TestProfileTransactions(t *testing.T) {
+	txs := loadExampleTxs(t)
+
+	tests := []struct {
+		name       string
+		start, end int64
+		options    TxProfileOptions
+		total      TxStats
+		fees       string
+		blocks     []int64
+		senders    []string
+		msgTypes   []string
+		buckets    []int64
+	}{
+		{
+			name: "whole range", start: 1, end: 100,
+			total:    TxStats{Txs: 3, Msgs: 4, Failed: 1, FailureRate: 1.0 / 3, GasWanted: 700000, GasUsed: 370001},
+			fees:     "205utree,7uxrn",
+			blocks:   []int64{10, 12},
+			senders:  []string{spammer, receiver},
+			msgTypes: []string{"cosmos-sdk/MsgSend", "wasm/execute"},
+		},
+		{
+			name: "single block", start: 10, end: 10,
+			total:    TxStats{Txs: 2, Msgs: 3, Failed: 1, FailureRate: 0.5, GasWanted: 400000, GasUsed: 250001},
+			fees:     "200utree",
+			blocks:   []int64{10},
+			senders:  []string{spammer},
+			msgTypes: []string{"cosmos-sdk/MsgSend"},
+		},
+		{
+			name: "empty range", start: 11, end: 11,
+		},
+		{
+			name: "top sender with minute buckets", start: 1, end: 100, options: TxProfileOptions{Top: 1, Bucket: time.Minute},
+			total:    TxStats{Txs: 3, Msgs: 4, Failed: 1, FailureRate: 1.0 / 3, GasWanted: 700000, GasUsed: 370001},
+			fees:     "205utree,7uxrn",
+			blocks:   []int64{10, 12},
+			senders:  []string{spammer},
+			msgTypes: []string{"cosmos-sdk/MsgSend", "wasm/execute"},
+			buckets:  []int64{2, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := ProfileTransactions(txs, tt.start, tt.end, tt.options)
+
+			total := profile.Total
+			total.Fees, total.fees = nil, nil
+
+			if !reflect.DeepEqual(total, tt.total) {
+				t.Errorf("total = %+v, want %+v", total, tt.total)
+			}
+
+			if fees := profile.Total.FeeString(); fees != tt.fees {
+				t.Errorf("fees = %q, want %q", fees, tt.fees)
+			}
+
+			var blocks []int64
+			for _, b := range profile.Blocks {
+				blocks = append(blocks, b.Height)
+			}
+
+			if !reflect.DeepEqual(blocks, tt.blocks) {
+				t.Errorf("blocks = %v, want %v", blocks, tt.blocks)
+			}
+
+			var senders []string
+			for _, s := range profile.Senders {
+				senders = append(senders, s.Sender)
+			}
+
+			if !reflect.DeepEqual(senders, tt.senders) {
+				t.Errorf("senders = %v, want %v", senders, tt.senders)
+			}
+
+			var msgTypes []string
+			for _, m := range profile.MsgTypes {
+				msgTypes = append(msgTypes, m.Type)
+			}
+
+			if !reflect.DeepEqual(msgTypes, tt.msgTypes) {
+				t.Errorf("msg types = %v, want %v", msgTypes, tt.msgTypes)
+			}
+
+			var buckets []int64
+			for _, b := range profile.Buckets {
+				buckets = append(buckets, b.Txs)
+
+				if want := float64(b.Txs) / 60; b.TxsPerSecond != want {
+					t.Errorf("bucket %s txs per second = %f, want %f", b.Start, b.TxsPerSecond, want)
+				}
+			}
+
+			if !reflect.DeepEqual(buckets, tt.buckets) {
+				t.Errorf("buckets = %v, want %v", buckets, tt.buckets)
+			}
+		})
+	}
+}
+
+func TestProfileTransactionsMsgTypeCountsTxOnce(t *testing.T) {
+	profile := ProfileTransactions(loadExampleTxs(t), 10, 10, TxProfileOptions{})
+
+	if len(profile.MsgTypes) != 1 || profile.MsgTypes[0].Txs != 2 || profile.MsgTypes[0].Msgs != 3 {
+		t.Errorf("msg types = %+v, want MsgSend in 2 txs with 3 msgs", profile.MsgTypes)
+	}
+}
+
+func TestTxProfilerBatches(t *testing.T) {
+	txs := loadExampleTxs(t)
+	options := TxProfileOptions{Bucket: time.Minute}
+
+	want := ProfileTransactions(txs, 1, 100, options)
+
+	//One tx per batch, as ProfileTxs reads the txs of a range of blocks at a time
+	profiler := newTxProfiler(1, 100, options)
+	for i := range txs {
+		profiler.add(txs[i : i+1])
+	}
+
+	if got := profiler.finish(); !reflect.DeepEqual(got, want) {
+		t.Errorf("batched profile = %+v, want %+v", got, want)
+	}
+}
@@ -1,5 +1,7 @@
 package src
 
+import "github.com/regen-friends/testnets/util/uptime/upgrades"
+
 type Validator struct {
 	ValidatorInfo []ValidatorInfo `json:"validatorInfo"`
 }
@@ -18,7 +20,32 @@ type Info struct {
 	StartBlock         int64   `json:"startBlock"`
 	UptimeCount        int64   `json:"uptimeCount"`
 	GenesisPoints      int64   `json:"genesisPoints"`
+	NodePoints         int64   `json:"nodePoints"`
 	TotalPoints        float64 `json:"totalPoints"`
 	Proposal1VoteScore int64   `json:"proposal1VoteScore"`
 	Proposal2VoteScore int64   `json:"proposal2VoteScore"`
+	ProposerPoints     float64 `json:"proposerPoints,omitempty"`
+
+	DoubleSigns  int64    `json:"doubleSigns,omitempty"`
+	Disqualified []string `json:"disqualified,omitempty"`
+
+	AdjustmentPoints float64      `json:"adjustmentPoints"`
+	Adjustments      []Adjustment `json:"adjustments,omitempty"`
+}
+
+// Results holds the scored validators together with the parameters they were scored with
+type Results struct {
+	StartBlock       int64            `json:"startBlock"`
+	EndBlock         int64            `json:"endBlock"`
+	MaxUptimeRewards int64            `json:"maxUptimeRewards"`
+	NodeRewards      int64            `json:"nodeRewards"`
+	ProposerPoints   int64            `json:"proposerPoints,omitempty"`
+	Upgrade1         upgrades.Window  `json:"upgrade1"`
+	Upgrade2         upgrades.Window  `json:"upgrade2"`
+	Validators       []ValidatorInfo  `json:"validators"`
+	Unresolved       []ValidatorInfo  `json:"unresolved"`
+	Unapplied        []Adjustment     `json:"unapplied,omitempty"`
+	Evidence         []EvidenceRecord `json:"evidence,omitempty"`
+
+	DoubleSignDisqualify []string `json:"doubleSignDisqualify,omitempty"`
 }
@@ -2,54 +2,49 @@ package src
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"text/tabwriter"
 
+	"github.com/regen-friends/testnets/util/uptime/config"
 	"github.com/regen-friends/testnets/util/uptime/db"
-	"github.com/spf13/viper"
+	"github.com/regen-friends/testnets/util/uptime/upgrades"
 	"gopkg.in/mgo.v2/bson"
 )
 
-var (
-	elChocoStartBlock     int64
-	elChocoEndBlock       int64
-	elChocoPointsPerBlock int64
-
-	amazonasStartBlock     int64
-	amazonasEndBlock       int64
-	amazonasPointsPerBlock int64
-
-	nodeRewards int64
-)
-
 type handler struct {
-	db db.DB
+	db  db.DB
+	cfg *config.Config
 }
 
-func New(db db.DB) handler {
-	return handler{db}
+// New - Creates the handler of a calculation, the config is read once by the caller and used for
+// every validator
+func New(db db.DB, cfg *config.Config) handler {
+	return handler{db, cfg}
 }
 
-func CalculateProposal1VoteScore(address string) int64 {
-	proposal1Voters := viper.Get("elchoco_vote_validators").([]interface{})
+func (h handler) CalculateProposal1VoteScore(address string) int64 {
+	proposal1Voters := h.cfg.ElChocoVoteValidators
 
 	for _, obj := range proposal1Voters {
-		if obj.(string) == address {
+		if obj == address {
 			return 100
 		}
 	}
 	return 0
 }
 
-func CalculateProposal2VoteScore(address string) int64 {
-	proposal2Voters := viper.Get("amazonas_vote_validators").([]interface{})
+func (h handler) CalculateProposal2VoteScore(address string) int64 {
+	proposal2Voters := h.cfg.AmazonasVoteValidators
 
 	for _, obj := range proposal2Voters {
-		if obj.(string) == address {
+		if obj == address {
 			return 100
 		}
 	}
@@ -175,93 +170,138 @@ func GetCommonValidators(gentxVals, blockVals []string) (results []string) {
 	return results
 }
 
-func (h handler) CalculateGenesisPoints(address string) int64 {
-	var aggQuery []bson.M
-
-	matchQuery := bson.M{
-		"$match": bson.M{
-			"height": 2,
-		},
-	}
-
-	aggQuery = append(aggQuery, matchQuery)
-
-	lookUpQuery := bson.M{
-		"$lookup": bson.M{
-			"from": "validators",
-			"let":  bson.M{"id": "$validators"},
-			"pipeline": []bson.M{
-				bson.M{
-					"$match": bson.M{
-						"$expr": bson.M{"$in": []string{"$address", "$$id"}},
-					},
-				},
-				bson.M{
-					"$project": bson.M{
-						"description.moniker": 1, "operator_address": 1, "address": 1, "_id": 0,
-					},
-				},
-			},
-			"as": "validator_details",
-		},
+// GenesisBlockValidators - Returns the operator addresses of the validators which signed the genesis
+// block (in the block at height 2), from the validators collection or the resolver
+func (h handler) GenesisBlockValidators(resolver *Resolver) []string {
+	blocks, err := h.db.QueryBlocks(2, 2)
+	if err != nil {
+		fmt.Printf("Error while fetching validator data at height 2 %v", err)
+		db.HandleError(err)
 	}
 
-	aggQuery = append(aggQuery, lookUpQuery)
-
-	results, err := h.db.QueryValAggregateData(aggQuery)
-
+	validators, err := h.db.QueryValidators()
 	if err != nil {
-		fmt.Printf("Error while fetching validator data at height 2 %v", err)
+		fmt.Printf("Error while fetching validators %v", err)
 		db.HandleError(err)
 	}
 
-	var blockValidators []string
+	operators := make(map[string]string, len(validators))
 
-	if len(results) > 0 {
-		for _, val := range results[0].Validator_details {
-			blockValidators = append(blockValidators, val.Operator_address)
-		}
+	for _, v := range validators {
+		operators[v.Address] = v.OperatorAddress
 	}
 
-	GentxValidators := viper.Get("gentx_validators").([]interface{})
-	var genxVal []string
+	var blockValidators []string
 
-	for _, val := range GentxValidators {
-		genxVal = append(genxVal, val.(string))
+	for _, block := range blocks {
+		for _, hexAddr := range block.Validators {
+			if operator := operators[hexAddr]; operator != "" {
+				blockValidators = append(blockValidators, operator)
+			} else if info, ok := resolver.Resolve(hexAddr); ok {
+				blockValidators = append(blockValidators, info.OperatorAddr)
+			}
+		}
 	}
 
-	commonValidators := GetCommonValidators(genxVal, blockValidators)
+	return blockValidators
+}
 
-	for _, val := range commonValidators {
+// CalculateGenesisPoints - Gentx validators which signed the genesis block get 100 points
+func (h handler) CalculateGenesisPoints(address string, blockValidators []string) int64 {
+	for _, val := range GetCommonValidators(h.cfg.GentxValidators, blockValidators) {
 		if val == address {
 			return 100
 		}
 	}
 
 	return 0
-
 }
 
+// CalculateUptime - Scores the validators, prints the results and exports them to result.csv and result.json
 func (h handler) CalculateUptime(startBlock int64, endBlock int64) {
-	//Read node rewards from config
-	nodeRewards = viper.Get("node_rewards").(int64)
+	results := h.ScoreValidators(startBlock, endBlock)
 
-	// Read El Choco upgrade configs
-	elChocoStartBlock = viper.Get("el_choco_startblock").(int64) + 1 //Need to consider votes from next block after upgrade
-	elChocoEndBlock = viper.Get("el_choco_endblock").(int64) + 1
-	elChocoPointsPerBlock = viper.Get("el_choco_reward_points_per_block").(int64)
+	PrintResults(results)
 
-	// Read Amazonas upgrade configs
-	amazonasStartBlock = viper.Get("amazonas_startblock").(int64) + 1 //Need to consider votes from next block after upgrade
-	amazonasEndBlock = viper.Get("amazonas_endblock").(int64) + 1
-	amazonasPointsPerBlock = viper.Get("amazonas_reward_points_per_block").(int64)
+	//Export data to csv file
+	ExportToCsv(results.Validators)
 
-	var validatorsList []ValidatorInfo //Intializing validators uptime
+	//Export data to json file, it can be served with the serve command
+	ExportToJSON(results)
+}
+
+// PrintResults - Prints the results in tabular view followed by the unresolved validators
+func PrintResults(results *Results) {
+	validatorsList := results.Validators
+
+	//Printing Uptime results in tabular view
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Operator Addr \t Moniker\t Uptime Count "+
+		"\t Upgrade-1 Points \t Upgrade-2 Points \t Uptime Points \t Node Points"+
+		" \t Proposal-1 Points \t Proposal-2 Points \t Genesis Points \t Proposer Points \t Adjustment Points \t Total points")
+
+	for _, data := range validatorsList {
+		fmt.Fprintln(w, " "+data.Info.OperatorAddr+"\t "+data.Info.Moniker+
+			"\t  "+strconv.Itoa(int(data.Info.UptimeCount))+" \t"+fmt.Sprintf("%f", data.Info.UptimePoints)+
+			"\t "+strconv.Itoa(int(data.Info.Upgrade1Points))+" \t"+strconv.Itoa(int(data.Info.Upgrade2Points))+
+			"\t"+strconv.Itoa(int(data.Info.NodePoints))+"\t"+
+			"\t"+strconv.Itoa(int(data.Info.Proposal1VoteScore))+"\t"+strconv.Itoa(int(data.Info.Proposal2VoteScore))+
+			"\t"+strconv.Itoa(int(data.Info.GenesisPoints))+"\t"+fmt.Sprintf("%f", data.Info.ProposerPoints)+
+			"\t"+fmt.Sprintf("%g", data.Info.AdjustmentPoints)+
+			"\t"+fmt.Sprintf("%f", data.Info.TotalPoints))
+	}
+
+	w.Flush()
+
+	PrintAdjustments(results)
+
+	PrintEvidence(results.Evidence)
+
+	PrintUnresolved(results.Unresolved)
+}
+
+// PrintAdjustments - Lists the applied adjustments with their attribution, followed by
+// the adjustments of operators without results
+func PrintAdjustments(results *Results) {
+	var applied []ValidatorInfo
+
+	for _, v := range results.Validators {
+		if len(v.Info.Adjustments) > 0 {
+			applied = append(applied, v)
+		}
+	}
+
+	if len(applied) > 0 {
+		fmt.Println("\nAdjustments:")
+
+		w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+		fmt.Fprintln(w, " Operator Addr \t Moniker \t Category \t Points \t Reason \t Approver \t Evidence")
+
+		for _, v := range applied {
+			for _, adj := range v.Info.Adjustments {
+				fmt.Fprintln(w, " "+v.Info.OperatorAddr+"\t "+v.Info.Moniker+"\t "+adj.Category+
+					"\t "+fmt.Sprintf("%+g", adj.Points)+"\t "+adj.Reason+"\t "+adj.Approver+"\t "+adj.Evidence)
+			}
+		}
+
+		w.Flush()
+	}
+
+	for _, adj := range results.Unapplied {
+		fmt.Printf("Adjustment not applied, %s has no results: %s\n", adj.Operator, adj)
+	}
+}
+
+// ScoreValidators - Calculates the points of every validator which signed blocks in between start and end block
+func (h handler) ScoreValidators(startBlock int64, endBlock int64) *Results {
+	// Read the upgrade windows from the registry or the El Choco and Amazonas configs
+	upgrade1, upgrade2 := LoadUpgradeWindows(h.cfg)
 
 	fmt.Println("Fetching blocks from:", startBlock, ", to:", endBlock)
 
-	aggQuery := GenerateAggregateQuery(startBlock, endBlock, elChocoStartBlock,
-		elChocoEndBlock, amazonasStartBlock, amazonasEndBlock)
+	//Need to consider votes from next block after upgrade
+	aggQuery := GenerateAggregateQuery(startBlock, endBlock, upgrade1.StartBlock+1,
+		upgrade1.EndBlock+1, upgrade2.StartBlock+1, upgrade2.EndBlock+1)
 
 	results, err := h.db.QueryValAggregateData(aggQuery)
 
@@ -270,80 +310,238 @@ func (h handler) CalculateUptime(startBlock int64, endBlock int64) {
 		db.HandleError(err)
 	}
 
+	return h.scoreAggregates(results, startBlock, endBlock, upgrade1, upgrade2)
+}
+
+// scoreAggregates - Calculates the points of the validators from their block counts
+func (h handler) scoreAggregates(results []db.ValAggregateResult, startBlock, endBlock int64,
+	upgrade1, upgrade2 upgrades.Window) *Results {
+	cfg := h.cfg
+
+	var validatorsList []ValidatorInfo //Intializing validators uptime
+
+	resolver := h.LoadResolver()
+
+	var unresolvedList []ValidatorInfo //Validators without a known operator address
+
 	for _, obj := range results {
+		var operator OperatorInfo
+
+		if len(obj.Validator_details) > 0 && obj.Validator_details[0].Operator_address != "" {
+			operator.OperatorAddr = obj.Validator_details[0].Operator_address
+			operator.Moniker = obj.Validator_details[0].Description.Moniker
+		} else if info, ok := resolver.Resolve(obj.Id); ok {
+			operator = info
+		} else {
+			unresolvedList = append(unresolvedList, ValidatorInfo{
+				ValAddress: obj.Id,
+				Info:       Info{UptimeCount: obj.Uptime_count},
+			})
+			continue
+		}
+
 		valInfo := ValidatorInfo{
-			ValAddress: obj.Validator_details[0].Address,
+			ValAddress: obj.Id,
 			Info: Info{
-				OperatorAddr:   obj.Validator_details[0].Operator_address,
-				Moniker:        obj.Validator_details[0].Description.Moniker,
+				OperatorAddr:   operator.OperatorAddr,
+				Moniker:        operator.Moniker,
 				UptimeCount:    obj.Uptime_count,
-				Upgrade1Points: CalculateUpgradePoints(elChocoPointsPerBlock, obj.Upgrade1_block, elChocoEndBlock),
-				Upgrade2Points: CalculateUpgradePoints(amazonasPointsPerBlock, obj.Upgrade2_block, amazonasEndBlock),
+				Upgrade1Points: CalculateUpgradePoints(upgrade1.PointsPerBlock, obj.Upgrade1_block, upgrade1.EndBlock+1),
+				Upgrade2Points: CalculateUpgradePoints(upgrade2.PointsPerBlock, obj.Upgrade2_block, upgrade2.EndBlock+1),
 			},
 		}
 
 		validatorsList = append(validatorsList, valInfo)
 	}
 
+	//Proposer fairness is an optional scoring input, it needs the ingested headers and voting power
+	var proposers *ProposerReport
+
+	if cfg.ProposerPoints > 0 {
+		report, err := h.ProposerFairness(startBlock, endBlock, 0, 0)
+		if err != nil {
+			log.Fatalf("Error while calculating proposer fairness: %v", err)
+		}
+
+		if report.Heights == 0 {
+			log.Fatalf("proposer_points is set but no block headers and voting power are ingested in %d - %d", startBlock, endBlock)
+		}
+
+		proposers = report
+	}
+
+	//Validators with double-sign evidence in the window lose the configured bonuses
+	evidence, err := h.EvidenceRecords(startBlock, endBlock, resolver)
+	if err != nil {
+		log.Fatalf("Error while fetching evidence: %v", err)
+	}
+
+	doubleSigns := make(map[string]int64)
+
+	for _, e := range evidence {
+		doubleSigns[e.Address]++
+	}
+
+	genesisValidators := h.GenesisBlockValidators(resolver)
+
 	//calculating uptime points
 	for i, v := range validatorsList {
-		maxUptimeRewards := viper.Get("max_uptime_rewards").(int64)
-		uptimePoints := float64(v.Info.UptimeCount*maxUptimeRewards) / (float64(endBlock) - float64(startBlock))
+		uptimePoints := float64(v.Info.UptimeCount*cfg.MaxUptimeRewards) / (float64(endBlock) - float64(startBlock))
 
 		validatorsList[i].Info.UptimePoints = uptimePoints
 
 		//calculate proposal1 vote score
-		proposal1VoteScore := CalculateProposal1VoteScore(validatorsList[i].Info.OperatorAddr)
+		proposal1VoteScore := h.CalculateProposal1VoteScore(validatorsList[i].Info.OperatorAddr)
 
 		//calculate proposal2 vote score
-		proposal2VoteScore := CalculateProposal2VoteScore(validatorsList[i].Info.OperatorAddr)
+		proposal2VoteScore := h.CalculateProposal2VoteScore(validatorsList[i].Info.OperatorAddr)
 
 		validatorsList[i].Info.Proposal1VoteScore = proposal1VoteScore
 		validatorsList[i].Info.Proposal2VoteScore = proposal2VoteScore
 
-		genesisPoints := h.CalculateGenesisPoints(validatorsList[i].Info.OperatorAddr)
+		genesisPoints := h.CalculateGenesisPoints(validatorsList[i].Info.OperatorAddr, genesisValidators)
 		validatorsList[i].Info.GenesisPoints = genesisPoints
+		validatorsList[i].Info.NodePoints = cfg.NodeRewards
 
-		validatorsList[i].Info.TotalPoints = float64(validatorsList[i].Info.Upgrade1Points) +
-			float64(validatorsList[i].Info.Upgrade2Points) + uptimePoints + float64(nodeRewards) +
-			float64(proposal1VoteScore) + float64(proposal2VoteScore) + float64(genesisPoints)
+		if proposers != nil {
+			stats, _ := proposers.Find(v.ValAddress)
+			validatorsList[i].Info.ProposerPoints = proposerPoints(cfg.ProposerPoints, stats)
+		}
+
+		if count := doubleSigns[v.ValAddress]; count > 0 {
+			validatorsList[i].Info.DoubleSigns = count
+			disqualify(&validatorsList[i].Info, cfg.DoubleSignDisqualify)
+		}
+
+		info := validatorsList[i].Info
+
+		validatorsList[i].Info.TotalPoints = float64(info.Upgrade1Points) + float64(info.Upgrade2Points) +
+			info.UptimePoints + float64(info.NodePoints) + float64(info.Proposal1VoteScore) +
+			float64(info.Proposal2VoteScore) + float64(info.GenesisPoints) + info.ProposerPoints
 
 	}
 
-	//Printing Uptime results in tabular view
-	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
-	fmt.Fprintln(w, " Operator Addr \t Moniker\t Uptime Count "+
-		"\t Upgrade-1 Points \t Upgrade-2 Points \t Uptime Points \t Node Points"+
-		" \t Proposal-1 Points \t Proposal-2 Points \t Genesis Points \t Total points")
+	//Merge the manual bonuses and penalties of the adjustments ledger into the totals
+	ledger, err := loadConfiguredAdjustments(cfg)
+	if err != nil {
+		log.Fatalf("Error while reading adjustments: %v", err)
+	}
 
-	for _, data := range validatorsList {
-		var address string = data.Info.OperatorAddr
+	unapplied := ApplyAdjustments(validatorsList, ledger)
+
+	return &Results{
+		StartBlock:       startBlock,
+		EndBlock:         endBlock,
+		MaxUptimeRewards: cfg.MaxUptimeRewards,
+		NodeRewards:      cfg.NodeRewards,
+		ProposerPoints:   cfg.ProposerPoints,
+		Upgrade1:         upgrade1,
+		Upgrade2:         upgrade2,
+		Validators:       validatorsList,
+		Unresolved:       unresolvedList,
+		Unapplied:        unapplied,
+		Evidence:         evidence,
+
+		DoubleSignDisqualify: cfg.DoubleSignDisqualify,
+	}
+}
+
+// LoadUpgradeWindows - Reads the two scored upgrade windows from the configured upgrades_file, exits
+// when the registry does not have exactly 2 scored upgrades. Falls back to the el_choco and amazonas configs when no registry is configured
+func LoadUpgradeWindows(cfg *config.Config) (upgrades.Window, upgrades.Window) {
+	registryFile := cfg.UpgradesFile
+
+	if registryFile == "" {
+		return cfg.ElChoco, cfg.Amazonas
+	}
+
+	registry, err := upgrades.Load(registryFile)
+	if err != nil {
+		log.Fatalf("Error while reading upgrade registry: %v", err)
+	}
+
+	scored := registry.Scored()
+	if len(scored) != 2 {
+		log.Fatalf("Upgrade registry %s has %d scored upgrades, 2 are required", registryFile, len(scored))
+	}
+
+	windows := make([]upgrades.Window, len(scored))
+
+	for i, upgrade := range scored {
+		windows[i] = *upgrade.Window
+		fmt.Printf("Scoring upgrade %s in blocks %d - %d\n", upgrade.Name, upgrade.Window.StartBlock, upgrade.Window.EndBlock)
+	}
+
+	return windows[0], windows[1]
+}
 
-		//Assigning validator address if operator address is not found
-		if address == "" {
-			address = data.ValAddress + " (Hex Address)"
+// LoadResolver - Builds the hex address to operator mapping from the gentxs of the
+// configured genesis file and the create validator transactions
+func (h handler) LoadResolver() *Resolver {
+	resolver := NewResolver()
+
+	if genesisFile := h.cfg.GenesisFile; genesisFile != "" {
+		if err := resolver.AddGenesis(genesisFile); err != nil {
+			fmt.Printf("Error while reading gentxs from %s %v\n", genesisFile, err)
 		}
+	}
 
-		fmt.Fprintln(w, " "+address+"\t "+data.Info.Moniker+
-			"\t  "+strconv.Itoa(int(data.Info.UptimeCount))+" \t"+fmt.Sprintf("%f", data.Info.UptimePoints)+
-			"\t "+strconv.Itoa(int(data.Info.Upgrade1Points))+" \t"+strconv.Itoa(int(data.Info.Upgrade2Points))+
-			"\t"+strconv.Itoa(int(nodeRewards))+"\t"+
-			"\t"+strconv.Itoa(int(data.Info.Proposal1VoteScore))+"\t"+strconv.Itoa(int(data.Info.Proposal2VoteScore))+
-			"\t"+strconv.Itoa(int(data.Info.GenesisPoints))+"\t"+fmt.Sprintf("%f", data.Info.TotalPoints))
+	txs, err := h.db.QueryTxsByMsgType(db.MSG_CREATE_VALIDATOR)
+
+	if err != nil {
+		fmt.Printf("Error while fetching create validator txs %v\n", err)
+	}
+
+	resolver.AddCreateValidatorTxs(txs)
+
+	return resolver
+}
+
+// PrintEvidence - Lists the double-sign evidence of the window with the attributed operators
+func PrintEvidence(evidence []EvidenceRecord) {
+	if len(evidence) == 0 {
+		return
+	}
+
+	fmt.Println("\nEvidence:")
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Height \t Vote Height \t Type \t Hex Address \t Operator Addr \t Moniker")
+
+	for _, e := range evidence {
+		fmt.Fprintln(w, " "+strconv.FormatInt(e.Height, 10)+"\t "+strconv.FormatInt(e.VoteHeight, 10)+"\t "+e.Type+
+			"\t "+e.Address+"\t "+e.OperatorAddr+"\t "+e.Moniker)
 	}
 
 	w.Flush()
+}
 
-	//Export data to csv file
-	ExportToCsv(validatorsList, nodeRewards)
+// PrintUnresolved - Lists the validators whose operator address could not be resolved
+func PrintUnresolved(data []ValidatorInfo) {
+	if len(data) == 0 {
+		return
+	}
+
+	fmt.Println("\nUnresolved validators (no operator address found):")
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 0, ' ', tabwriter.Debug)
+	fmt.Fprintln(w, " Hex Address \t Uptime Count")
+
+	for _, record := range data {
+		fmt.Fprintln(w, " "+record.ValAddress+"\t "+strconv.Itoa(int(record.Info.UptimeCount)))
+	}
+
+	w.Flush()
 }
 
 // ExportToCsv - Export data to CSV file
-func ExportToCsv(data []ValidatorInfo, nodeRewards int64) {
+func ExportToCsv(data []ValidatorInfo) {
 	Header := []string{
 		"ValOper Address", "Moniker", "Uptime Count", "Upgrade1 Points",
 		"Upgrade2 Points", "Uptime Points", "Node points",
-		"Proposal1 Vote Points", "Proposal2 Vote Points", "Genesis Points", "Total Points",
+		"Proposal1 Vote Points", "Proposal2 Vote Points", "Genesis Points", "Proposer Points", "Adjustment Points",
+		"Total Points",
+		"Adjustments", "Double Signs", "Disqualified",
 	}
 
 	file, err := os.Create("result.csv")
@@ -362,24 +560,26 @@ func ExportToCsv(data []ValidatorInfo, nodeRewards int64) {
 	_ = writer.Write(Header)
 
 	for _, record := range data {
-		var address string = record.Info.OperatorAddr
-
-		//Assigning validator address if operator address is not found
-		if address == "" {
-			address = record.ValAddress + " (Hex Address)"
-		}
-
 		uptimeCount := strconv.Itoa(int(record.Info.UptimeCount))
 		uptimePoints := fmt.Sprintf("%f", record.Info.UptimePoints)
 		up1Points := strconv.Itoa(int(record.Info.Upgrade1Points))
 		up2Points := strconv.Itoa(int(record.Info.Upgrade2Points))
-		nodePoints := strconv.Itoa(int(nodeRewards))
+		nodePoints := strconv.Itoa(int(record.Info.NodePoints))
 		totalPoints := fmt.Sprintf("%f", record.Info.TotalPoints)
+		adjPoints := fmt.Sprintf("%g", record.Info.AdjustmentPoints)
+
+		var adjustments []string
+		for _, adj := range record.Info.Adjustments {
+			adjustments = append(adjustments, adj.String())
+		}
 		p1VoteScore := strconv.Itoa(int(record.Info.Proposal1VoteScore))
 		p2VoteScore := strconv.Itoa(int(record.Info.Proposal2VoteScore))
 		genPoints := strconv.Itoa(int(record.Info.GenesisPoints))
-		addrObj := []string{address, record.Info.Moniker, uptimeCount, up1Points,
-			up2Points, uptimePoints, nodePoints, p1VoteScore, p2VoteScore, genPoints, totalPoints}
+		propPoints := fmt.Sprintf("%f", record.Info.ProposerPoints)
+		addrObj := []string{record.Info.OperatorAddr, record.Info.Moniker, uptimeCount, up1Points,
+			up2Points, uptimePoints, nodePoints, p1VoteScore, p2VoteScore, genPoints, propPoints, adjPoints, totalPoints,
+			strings.Join(adjustments, "; "), strconv.FormatInt(record.Info.DoubleSigns, 10),
+			strings.Join(record.Info.Disqualified, " ")}
 		err := writer.Write(addrObj)
 
 		if err != nil {
@@ -387,3 +587,37 @@ func ExportToCsv(data []ValidatorInfo, nodeRewards int64) {
 		}
 	}
 }
+
+// ExportToJSON - Export the results to a JSON file
+func ExportToJSON(results *Results) {
+	file, err := os.Create("result.json")
+
+	if err != nil {
+		log.Fatal("Cannot write to file", err)
+	}
+
+	defer file.Close() //Close file
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(results); err != nil {
+		log.Fatal("Cannot write to file", err)
+	}
+}
+
+// LoadResults - Reads the results exported to a JSON file
+func LoadResults(path string) (*Results, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var results Results
+
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return &results, nil
+}
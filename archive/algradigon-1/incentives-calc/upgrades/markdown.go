@@ -0,0 +1,149 @@
+package upgrades
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Generated blocks are placed between <!-- upgrades --> (overview of all upgrades) or
+// <!-- upgrades:<name> --> (details of an upgrade) and <!-- /upgrades --> markers
+var blockRegex = regexp.MustCompile(`(?s)<!-- upgrades(?::([\w.-]+))? -->\n.*?<!-- /upgrades -->`)
+
+const docTimeFormat = "2006-01-02 15:04:05 UTC"
+
+// Overview - Renders a Markdown table listing every upgrade of the registry
+func (r *Registry) Overview() string {
+	var b strings.Builder
+
+	b.WriteString("| Proposal ID | Name | Title | Upgrade Height | Upgrade Time | Binary |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+
+	for _, u := range r.Upgrades {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", proposalID(u), u.Name, u.Title,
+			height(u.UpgradeHeight), docTime(u.UpgradeTime), orDash(u.Binary))
+	}
+
+	return b.String()
+}
+
+// Details - Renders the proposal details table of an upgrade
+func (u Upgrade) Details() string {
+	var b strings.Builder
+
+	b.WriteString("|    |            |\n")
+	b.WriteString("|----------|:-------------:|\n")
+
+	row := func(key, value string) {
+		if value != "" && value != "-" {
+			fmt.Fprintf(&b, "| %s | %s |\n", key, value)
+		}
+	}
+
+	row("Proposal ID", proposalID(u))
+	row("Name", u.Name)
+	row("Title", u.Title)
+	row("Description", u.Description)
+	row("Proposal Time", docTime(u.ProposalTime))
+	row("Voting Start Time", docTime(u.VotingStartTime))
+	row("Voting End Time", docTime(u.VotingEndTime))
+	row("Upgrade Height", height(u.UpgradeHeight))
+	row("Upgrade Time", docTime(u.UpgradeTime))
+	row("Binary", u.Binary)
+
+	if u.Skip {
+		row("Skipped", fmt.Sprintf("yes, start with `--unsafe-skip-upgrades %d`", u.UpgradeHeight))
+	}
+
+	if u.Window != nil {
+		row("Scoring Window", fmt.Sprintf("%d - %d (%d points per signed block)",
+			u.Window.StartBlock, u.Window.EndBlock, u.Window.PointsPerBlock))
+	}
+
+	row("Link", u.Link)
+
+	return b.String()
+}
+
+// Sync - Regenerates the marked blocks of a Markdown file, returns whether the file changed.
+// The file is only written when write is set, so the result can be used as a drift check
+func (r *Registry) Sync(path string, write bool) (bool, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	var syncErr error
+
+	updated := blockRegex.ReplaceAllFunc(content, func(block []byte) []byte {
+		name := blockRegex.FindSubmatch(block)[1]
+
+		body := r.Overview()
+		open := "<!-- upgrades -->"
+
+		if len(name) > 0 {
+			upgrade, ok := r.Find(string(name))
+			if !ok {
+				syncErr = fmt.Errorf("%s: upgrade %s is not in the registry", path, name)
+				return block
+			}
+
+			body = upgrade.Details()
+			open = "<!-- upgrades:" + string(name) + " -->"
+		}
+
+		return []byte(open + "\n" + body + "<!-- /upgrades -->")
+	})
+
+	if syncErr != nil {
+		return false, syncErr
+	}
+
+	if bytes.Equal(content, updated) {
+		return false, nil
+	}
+
+	if write {
+		if err := ioutil.WriteFile(path, updated, 0644); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func proposalID(u Upgrade) string {
+	if u.ProposalID == 0 {
+		return "-"
+	}
+
+	return strconv.FormatUint(u.ProposalID, 10)
+}
+
+func height(h int64) string {
+	if h <= 0 {
+		return "-"
+	}
+
+	return strconv.FormatInt(h, 10)
+}
+
+func docTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.UTC().Format(docTimeFormat)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
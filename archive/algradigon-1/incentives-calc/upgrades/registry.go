@@ -0,0 +1,143 @@
+package upgrades
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Window is the block range in which signing validators earn upgrade points
+type Window struct {
+	StartBlock     int64 `json:"startBlock"`
+	EndBlock       int64 `json:"endBlock"`
+	PointsPerBlock int64 `json:"pointsPerBlock"`
+}
+
+// Upgrade holds the proposal metadata of an upgrade, the window is only set
+// for upgrades which are scored by the calculator
+type Upgrade struct {
+	Name            string    `json:"name"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description,omitempty"`
+	ProposalID      uint64    `json:"proposalId,omitempty"`
+	ProposalTime    time.Time `json:"proposalTime,omitempty"`
+	VotingStartTime time.Time `json:"votingStartTime,omitempty"`
+	VotingEndTime   time.Time `json:"votingEndTime,omitempty"`
+	UpgradeTime     time.Time `json:"upgradeTime,omitempty"`
+	UpgradeHeight   int64     `json:"upgradeHeight,omitempty"`
+	Binary          string    `json:"binary,omitempty"`
+	Skip            bool      `json:"skip,omitempty"`
+	Link            string    `json:"link,omitempty"`
+	Window          *Window   `json:"window,omitempty"`
+}
+
+// Registry lists the upgrades of a testnet in the order they were proposed
+type Registry struct {
+	ChainID  string    `json:"chainId"`
+	Upgrades []Upgrade `json:"upgrades"`
+}
+
+// Load - Reads and validates an upgrade registry file
+func Load(path string) (*Registry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	var registry Registry
+
+	if err := decoder.Decode(&registry); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if errs := registry.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %s", path, strings.Join(errs, "; "))
+	}
+
+	return &registry, nil
+}
+
+// Validate - Returns every problem found in the registry
+func (r *Registry) Validate() []string {
+	var errs []string
+
+	if r.ChainID == "" {
+		errs = append(errs, "chainId is missing")
+	}
+
+	names := make(map[string]bool)
+	proposals := make(map[uint64]string)
+
+	for i, upgrade := range r.Upgrades {
+		label := upgrade.Name
+		if label == "" {
+			label = fmt.Sprintf("upgrade %d", i)
+			errs = append(errs, label+": name is missing")
+		}
+
+		if names[upgrade.Name] {
+			errs = append(errs, label+": duplicate name")
+		}
+
+		names[upgrade.Name] = true
+
+		if upgrade.ProposalID > 0 {
+			if other, ok := proposals[upgrade.ProposalID]; ok {
+				errs = append(errs, fmt.Sprintf("%s: proposal id %d is also used by %s", label, upgrade.ProposalID, other))
+			}
+
+			proposals[upgrade.ProposalID] = label
+		}
+
+		if upgrade.UpgradeHeight <= 0 && upgrade.UpgradeTime.IsZero() {
+			errs = append(errs, label+": upgradeHeight or upgradeTime is required")
+		}
+
+		if !upgrade.VotingEndTime.IsZero() && upgrade.VotingEndTime.Before(upgrade.VotingStartTime) {
+			errs = append(errs, label+": votingEndTime is before votingStartTime")
+		}
+
+		if w := upgrade.Window; w != nil {
+			if w.StartBlock <= 0 || w.EndBlock < w.StartBlock {
+				errs = append(errs, fmt.Sprintf("%s: invalid window %d-%d", label, w.StartBlock, w.EndBlock))
+			}
+
+			if w.PointsPerBlock <= 0 {
+				errs = append(errs, label+": window pointsPerBlock must be positive")
+			}
+		}
+	}
+
+	return errs
+}
+
+// Find - Returns the upgrade with the given name
+func (r *Registry) Find(name string) (Upgrade, bool) {
+	for _, upgrade := range r.Upgrades {
+		if upgrade.Name == name {
+			return upgrade, true
+		}
+	}
+
+	return Upgrade{}, false
+}
+
+// Scored - Returns the upgrades with a scoring window
+func (r *Registry) Scored() []Upgrade {
+	var scored []Upgrade
+
+	for _, upgrade := range r.Upgrades {
+		if upgrade.Window != nil {
+			scored = append(scored, upgrade)
+		}
+	}
+
+	return scored
+}